@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorFlag(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	file := filepath.Join(tempDir, "file2.md")
+	outputFile := filepath.Join(tempDir, "out.term")
+
+	tests := []struct {
+		name    string
+		color   string
+		colored bool
+	}{
+		{name: "always keeps escape codes in files", color: "always", colored: true},
+		{name: "never drops them", color: "never", colored: false},
+		{name: "auto drops them outside a terminal", color: "auto", colored: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand("--color", tt.color, "--render-markdown", "--output", outputFile, file)
+			if err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			data, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatalf("Failed to read output: %v", err)
+			}
+			if got := strings.Contains(string(data), "\x1b["); got != tt.colored {
+				t.Errorf("Expected escape codes = %v, got %q", tt.colored, data)
+			}
+			if !strings.Contains(string(data), "Title") {
+				t.Errorf("Expected the rendered heading, got %q", data)
+			}
+		})
+	}
+
+	t.Run("file escape codes are kept", func(t *testing.T) {
+		ansiFile := filepath.Join(tempDir, "ansi.txt")
+		if err := os.WriteFile(ansiFile, []byte("\x1b[31mred\x1b[0m\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, color := range []string{"auto", "never"} {
+			output, err := executeCommand("--color", color, ansiFile)
+			if err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			if !strings.Contains(output, "\x1b[31mred\x1b[0m") {
+				t.Errorf("--color %s: expected the file's escape codes kept, got %q", color, output)
+			}
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := executeCommand("--color", "sometimes", file)
+		if err == nil || !strings.Contains(err.Error(), "invalid --color value: sometimes") {
+			t.Errorf("Expected an invalid --color error, got %v", err)
+		}
+	})
+}
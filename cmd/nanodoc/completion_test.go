@@ -60,10 +60,20 @@ func TestCompletionOutput(t *testing.T) {
 			args: []string{"__complete", "nanodoc", "--header-format", ""},
 			wantContains: []string{
 				"nice",
-				"simple",
 				"path",
 				"filename",
-				"title",
+				"relative",
+			},
+		},
+		{
+			name: "header-style value completion",
+			args: []string{"__complete", "nanodoc", "--header-style", ""},
+			wantContains: []string{
+				"none",
+				"dashed",
+				"solid",
+				"boxed",
+				"rule",
 			},
 		},
 		{
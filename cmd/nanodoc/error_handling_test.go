@@ -34,25 +34,31 @@ func TestCLIErrorDisplay(t *testing.T) {
 			name:         "invalid flag",
 			args:         []string{"--invalid-option"},
 			wantError:    "unknown flag: --invalid-option",
-			wantExitCode: 1,
+			wantExitCode: 2,
 		},
 		{
 			name:         "invalid linenum value",
 			args:         []string{"--linenum", "invalid", "README.md"},
 			wantError:    "invalid --linenum value: invalid (must be 'file' or 'global')",
-			wantExitCode: 1,
+			wantExitCode: 2,
 		},
 		{
 			name:         "invalid output format",
 			args:         []string{"--output-format", "wrongformat", "README.md"},
-			wantError:    "invalid --output-format value: wrongformat (must be 'term', 'plain', or 'markdown')",
-			wantExitCode: 1,
+			wantError:    "invalid --output-format value: wrongformat (must be 'term', 'plain', 'markdown', or 'json')",
+			wantExitCode: 2,
+		},
+		{
+			name:         "invalid seq-start",
+			args:         []string{"--seq-start", "0", "README.md"},
+			wantError:    "invalid --seq-start value: 0 (must be 1 or more)",
+			wantExitCode: 2,
 		},
 		{
 			name:         "missing required arguments",
 			args:         []string{},
 			wantError:    "Missing paths to bundle",
-			wantExitCode: 1,
+			wantExitCode: 2,
 		},
 	}
 
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/arthur-debert/nanodoc/pkg/nanodoc"
+)
+
+// Exit codes, so scripts can tell error classes apart
+const (
+	ExitOK                 = 0
+	ExitError              = 1
+	ExitUsage              = 2
+	ExitFileNotFound       = 3
+	ExitCircularDependency = 4
+	ExitRender             = 5
+)
+
+// exitError tags an error with the exit code it should end the process with
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode tags err with an exit code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode maps an error returned by Execute to the process exit code
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var tagged *exitError
+	if errors.As(err, &tagged) {
+		return tagged.code
+	}
+	var option *nanodoc.OptionError
+	if errors.As(err, &option) {
+		return ExitUsage
+	}
+	var circular *nanodoc.CircularDependencyError
+	if errors.As(err, &circular) || errors.Is(err, nanodoc.ErrCircularDependency) {
+		return ExitCircularDependency
+	}
+	if errors.Is(err, nanodoc.ErrFileNotFound) {
+		return ExitFileNotFound
+	}
+	return ExitError
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitCodes(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	file1 := filepath.Join(tempDir, "file1.txt")
+	bundleA := filepath.Join(tempDir, "a.bundle.txt")
+	bundleB := filepath.Join(tempDir, "b.bundle.txt")
+	if err := os.WriteFile(bundleA, []byte("b.bundle.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bundleB, []byte("a.bundle.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	badBundle := filepath.Join(tempDir, "bad.bundle.txt")
+	if err := os.WriteFile(badBundle, []byte("--overflow=bad\nfile1.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{file1}, ExitOK},
+		{"unknown flag", []string{"--invalid-option", file1}, ExitUsage},
+		{"missing paths", []string{}, ExitUsage},
+		{"invalid flag value", []string{"--linenum", "invalid", file1}, ExitUsage},
+		{"unknown theme", []string{"--theme", "classci-dark", file1}, ExitUsage},
+		{"invalid max file size", []string{"--max-file-size", "10XB", file1}, ExitUsage},
+		{"file not found", []string{filepath.Join(tempDir, "missing.txt")}, ExitFileNotFound},
+		{"circular dependency", []string{bundleA}, ExitCircularDependency},
+		{"invalid bundle option", []string{badBundle}, ExitUsage},
+		{"invalid highlight lines", []string{"--highlight-lines", "3-5", file1}, ExitUsage},
+		{"render error", []string{"--cache-dir", filepath.Join(file1, "cache"), file1}, ExitRender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(tt.args...)
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d (error: %v)\nOutput:\n%s", got, tt.want, err, output)
+			}
+		})
+	}
+}
+
+func TestExitCodeUnwrapsErrors(t *testing.T) {
+	err := fmt.Errorf("outer: %w", withExitCode(ExitRender, errors.New("inner")))
+	if got := exitCode(err); got != ExitRender {
+		t.Errorf("exitCode() = %d, want %d", got, ExitRender)
+	}
+	if got := exitCode(errors.New("other")); got != ExitError {
+		t.Errorf("exitCode() = %d, want %d", got, ExitError)
+	}
+}
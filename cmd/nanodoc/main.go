@@ -7,6 +7,6 @@ import (
 func main() {
 	if err := Execute(); err != nil {
 		// Don't print error message here since we handle it in root.go
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 } 
\ No newline at end of file
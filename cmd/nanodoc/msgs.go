@@ -11,6 +11,12 @@ const (
 Running 'nanodoc topics' lists all available topics.
 Running 'nanodoc topics <topic-name>' displays the content of that topic.`
 
+	StylesShort = "List the header styles"
+	StylesLong  = `List the header styles --header-style accepts, each with a description
+and a sample header drawn with it.
+
+Use --page-width to draw the samples for a given width.`
+
 	CompletionShort = "Generate completion script"
 
 	ManShort = "Generate man page"
@@ -22,9 +28,17 @@ const (
 	ErrMinArgs           = "requires at least 1 arg(s), only received %d"
 	ErrResolvingPaths    = "error resolving paths: %w"
 	ErrGeneratingDryRun  = "error generating dry run info: %w"
+	ErrGeneratingPlan    = "error generating resolve plan: %w"
 	ErrBuildingDocument  = "error building document: %w"
 	ErrCreatingContext   = "error creating formatting context: %w"
 	ErrRenderingDocument = "error rendering document: %w"
+	ErrCheckingLinks     = "error checking links: %w"
+	ErrWritingOutput     = "error writing output file: %w"
+	ErrListingGitFiles   = "error listing git files: %w"
+	ErrAppendNeedsOutput = "--append requires --output"
+	ErrInvalidSeqStart   = "invalid --seq-start value: %d (must be 1 or more)"
+	ErrInvalidColor      = "invalid --color value: %s (must be 'auto', 'always' or 'never')"
+	ErrBrokenLinks       = "found %d broken link(s)"
 	ErrTopicNotFound     = "topic '%s' not found"
 	ErrFailedToGetTopics = "failed to get available topics: %w"
 	ErrFailedGenManPage  = "failed to generate man page: %w"
@@ -33,21 +47,92 @@ const (
 // Flag descriptions
 const (
 	FlagLineNum           = "Line numbers: file|global (help line-numbering)"
+	FlagLineNumScope      = "Files to number: all|code|text"
+	FlagLineNumMinLines   = "Number only files with at least N lines (0: all files)"
+	FlagEmptyNoNumber     = "Show the (empty file) placeholder without a line number"
 	FlagTOC               = "Generate a table of contents (help toc)"
+	FlagTOCMaxEntries     = "Show at most N table of contents entries (0: all)"
 	FlagTheme             = "Theme (help themes)"
 	FlagFilenames         = "Show filenames"
 	FlagHeaderFormat      = "Header style (help filenames)"
 	FlagFileNumbering     = "File numbering"
+	FlagFileNumberReset   = "Restart file numbers at 1: none|bundle|dir"
+	FlagSeqStart          = "Number of the first file header (1 or more)"
 	FlagExt               = "Additional file extensions to treat as text"
+	FlagNoDefaultExt      = "Only treat --ext extensions as text; globs match any file"
+	FlagSection           = "Only include the markdown section with this heading (repeatable)"
+	FlagSectionOnly       = "Skip files that have none of the --section headings"
+	FlagUniqueBy          = "Deduplicate files: none|basename|dir"
+	FlagGitDiff           = "Also bundle the text files changed since a git ref (e.g. HEAD~1)"
+	FlagGitTracked        = "Also bundle every text file git tracks under the current directory"
+	FlagOnDuplicate       = "What to do with a file included twice with the same ranges: keep|skip|error"
+	FlagDedupeLines       = "Drop lines already included earlier in the document"
+	FlagDedupeLinesScope  = "Where --dedupe-lines looks for earlier lines: document|file"
+	FlagBundleAsSection   = "Show one header per bundle with file sub-headers"
+	FlagSortBundle        = "Sort the files listed in each bundle alphabetically"
+	FlagCommentSections   = "Show bundle comments like \"# --- Title ---\" as section headers"
 	FlagInclude           = "Include files matching patterns (help content)"
 	FlagExclude           = "Exclude files matching patterns (help content)"
+	FlagProcessIncludesIn = "Process [[file:]] directives in matching files, even READMEs"
+	FlagGlobBase          = "Match relative glob arguments under this directory"
+	FlagMaxFileSize       = "Skip or reject files larger than this, e.g. 10MB (default: no limit)"
+	FlagOnOversize        = "What to do with files over --max-file-size: skip|error"
 	FlagDryRun            = "Preview files to process without bundling"
+	FlagShowSkipped       = "With --dry-run, list directory files left out for their extension"
+	FlagKeepGoing         = "Skip paths that can't be resolved instead of failing"
 	FlagVersion           = "Print the version number"
 	FlagHeaderAlign       = "Header alignment"
 	FlagHeaderStyle       = "Header style"
+	FlagHeaderChar        = "Character for the lines of dashed/solid headers and the boxed border"
 	FlagPageWidth         = "Page width"
+	FlagOverflow          = "Lines wider than the page: none|truncate|wrap"
+	FlagRTL               = "Right-to-left layout: right-aligned text, line numbers after it"
+	FlagZebra             = "Shade every other line in term output (theme zebra style)"
+	FlagHighlight         = "Syntax-highlight code files in term output (terminals only, see --color)"
+	FlagColor             = "Write colors and other escape codes: auto (terminals only), always or never"
+	FlagPlainHeaders      = "Start each file in plain output with an \"=== name ===\" line"
+	FlagSqueezeBlanks     = "Collapse runs of blank lines in term output to one"
+	FlagBlankMarker       = "With --squeeze-blanks, show each run as an unnumbered \"⋮\" line"
+	FlagCompact           = "Dense output: inline \"name:\" headers, squeezed blanks, no TOC"
+	FlagReflow            = "With --render-markdown, rewrap paragraphs to the page width"
+	FlagWrap              = "Word-wrap file content to N columns before numbering lines"
+	FlagStrict            = "Fail on live bundle directives that name missing files"
+	FlagStripFrontMatter  = "Remove the YAML front matter of markdown files and use its title in the header"
+	FlagTOCLinks          = "Link TOC entries to their files in terminals with hyperlinks"
+	FlagHeadersOnly       = "Show only the file headers and TOC, without file content"
+	FlagAnchorEvery       = "Mark every Nth line of plain output with \"# --- line N ---\""
+	FlagHighlightLines    = "Mark these lines of each file in term output, e.g. L3-5,L8"
+	FlagHighlightLegend   = "Legend printed before the content when lines are highlighted"
+	FlagHighlightGutter   = "Color the line numbers of highlighted lines"
+	FlagFootnotePaths     = "Use [1] markers as file headers and list the paths at the end"
+	FlagHeaderShowSize    = "Append each file's size to its header, e.g. \"1. app.log (12.3 KB)\""
+	FlagHeaderShowRange   = "Append a ranged file's line ranges to its header, e.g. \"1. intro.txt (L10-20)\""
 	FlagSaveToBundle      = "Save the current invocation as a bundle file"
-	FlagOutputFormat      = "Output format: term|plain|markdown"
+	FlagBundleAbsPaths    = "Write absolute content paths with --save-to-bundle"
+	FlagBundleMinimal     = "Only write non-default options with --save-to-bundle"
+	FlagVerbose           = "Print how long resolving, extracting and rendering took to stderr"
+	FlagDumpOptions       = "Print the merged formatting options to stderr"
+	FlagResolveOnly       = "Print the merged options and resolved files as JSON and exit"
+	FlagEmptyDocMessage   = "Print this when no files are left to bundle"
+	FlagCacheDir          = "Reuse rendered files from this directory when unchanged"
+	FlagBundleVar         = "Set a ${KEY} variable used in bundle files (KEY=VALUE)"
+	FlagBundleVarDefault  = "Value for bundle variables not set with --bundle-var"
+	FlagOutputFormat      = "Output format: term|plain|markdown|json"
+	FlagJSONContent       = "Include each file's content in json output"
+	FlagOutput            = "Write the output to a file (.md implies markdown, .txt plain)"
+	FlagAppend            = "With --output, add to the end of the file instead of replacing it"
+	FlagFileIndex         = "Show a numbered index of the included files (help toc)"
+	FlagFileIndexPosition = "File index position: before-toc|after-toc"
+	FlagMdCodeFences      = "Fence non-markdown files as code in markdown output"
+	FlagMdCollapsible     = "Make each file a collapsible <details> block in markdown output"
+	FlagMdNormalize       = "Keep one H1 in markdown output: the first file's top heading; other files start at H2"
+	FlagMdFrontMatter     = "Add a YAML front matter KEY=VALUE to markdown output (repeatable)"
+	FlagRenderMarkdown    = "Style markdown files for the terminal in term output"
+	FlagRenderMdTables    = "With --render-markdown, draw markdown tables as aligned boxes"
+	FlagCheckLinks        = "Report broken relative links in markdown files"
+	FlagCheckExternal     = "Also check external URLs with --check-links"
+	FlagCountOnly         = "Print file and line totals instead of the document; with --dry-run, one summary line"
+	FlagCountByExt        = "With --count-only, also break the totals down by extension"
 )
 
 // Output messages
@@ -56,6 +141,10 @@ const (
 	AvailableTopics  = "Available help topics:"
 	RunTopicHelp     = `Run "nanodoc topics <topic-name>" for more information.`
 	TopicNotFoundMsg = "topic not found"
+	AvailableStyles  = "Available header styles:"
+	RunStyleHelp     = `Pick one with --header-style, e.g. "nanodoc --header-style boxed docs/".`
+	SkippedSources   = "Skipped %d source(s) that could not be resolved:\n"
+	SkippedOversize  = "Skipped %d file(s) larger than --max-file-size:\n"
 )
 
 // Man page constants
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputFile(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	file2 := filepath.Join(tempDir, "file2.md")
+
+	tests := []struct {
+		name       string
+		outputName string
+		args       []string
+		want       []string
+		dontWant   []string
+	}{
+		{
+			name:       "md extension implies markdown",
+			outputName: "report.md",
+			want:       []string{"## 1. File2\n\n# Title\n\ncontent"},
+		},
+		{
+			name:       "explicit output format wins",
+			outputName: "report.md",
+			args:       []string{"--output-format", "plain"},
+			want:       []string{"# Title\n\ncontent"},
+			dontWant:   []string{"## 1."},
+		},
+		{
+			name:       "txt extension implies plain",
+			outputName: "report.txt",
+			want:       []string{"# Title\n\ncontent"},
+			dontWant:   []string{"Title\n\n# Title"},
+		},
+		{
+			name:       "json extension implies json",
+			outputName: "report.json",
+			want:       []string{`"path": "` + file2 + `"`, `"content": "# Title\n\ncontent`},
+			dontWant:   []string{"## 1."},
+		},
+		{
+			name:       "unknown extension keeps term",
+			outputName: "report.out",
+			want:       []string{"1. Title", "# Title"},
+			dontWant:   []string{"## 1."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), tt.outputName)
+			args := append([]string{"--output", outputPath}, tt.args...)
+			args = append(args, file2)
+
+			stdout, err := executeCommand(args...)
+			if err != nil {
+				t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, stdout)
+			}
+			if stdout != "" {
+				t.Errorf("Expected nothing on stdout with --output, got:\n%s", stdout)
+			}
+
+			content, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output file: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("Output file does not contain %q.\nGot:\n%s", want, content)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(string(content), dontWant) {
+					t.Errorf("Output file contains %q, but should not.\nGot:\n%s", dontWant, content)
+				}
+			}
+		})
+	}
+}
+
+func TestOutputFileAppend(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	file1 := filepath.Join(tempDir, "file1.txt")
+	file2 := filepath.Join(tempDir, "file2.md")
+	outputPath := filepath.Join(t.TempDir(), "report.txt")
+
+	// The first run creates the file, the second adds to its end
+	var runs []string
+	for _, file := range []string{file1, file2} {
+		single, err := executeCommand("--output-format", "plain", file)
+		if err != nil {
+			t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, single)
+		}
+		runs = append(runs, single)
+
+		if out, err := executeCommand("--output", outputPath, "--append", file); err != nil {
+			t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, out)
+		}
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if want := strings.Join(runs, ""); string(content) != want {
+		t.Errorf("Expected both runs one after the other:\n%q\ngot:\n%q", want, content)
+	}
+
+	if _, err := executeCommand("--append", file1); err == nil {
+		t.Error("Expected an error for --append without --output")
+	}
+}
+
+func TestOutputFileAtomic(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	file1 := filepath.Join(tempDir, "file1.txt")
+	outDir := t.TempDir()
+
+	// Missing parent directories are created
+	outputPath := filepath.Join(outDir, "nested", "dir", "report.txt")
+	if out, err := executeCommand("--output", outputPath, file1); err != nil {
+		t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, out)
+	}
+	if _, err := os.Stat(outputPath); err != nil {
+		t.Fatalf("Expected the output file to be created: %v", err)
+	}
+
+	// Replacing a file keeps its mode and leaves no temporary files behind
+	if err := os.Chmod(outputPath, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := executeCommand("--output", outputPath, file1); err != nil {
+		t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, out)
+	}
+	if info, err := os.Stat(outputPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the file mode to be kept, got %v (%v)", info.Mode().Perm(), err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(outputPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the output file in its directory, got %d entries", len(entries))
+	}
+
+	// - writes to stdout
+	stdout, err := executeCommand("--output", "-", "--output-format", "plain", file1)
+	if err != nil {
+		t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, stdout)
+	}
+	if !strings.Contains(stdout, "hello\nworld") {
+		t.Errorf("Expected the output on stdout with --output -, got:\n%s", stdout)
+	}
+	if _, err := os.Stat("-"); err == nil {
+		t.Error("Expected no file named - to be written")
+	}
+
+	// An unwritable directory is reported and the old file is untouched
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	readOnly := filepath.Join(outDir, "readonly")
+	if err := os.Mkdir(readOnly, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(readOnly, "report.txt")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(readOnly, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chmod(readOnly, 0755) }()
+
+	_, err = executeCommand("--output", existing, file1)
+	if err == nil || !strings.Contains(err.Error(), "is not writable") {
+		t.Errorf("Expected a not writable error, got %v", err)
+	}
+	if content, _ := os.ReadFile(existing); string(content) != "old" {
+		t.Errorf("Expected the existing file to be untouched, got %q", content)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveOnly(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	bundlePath := filepath.Join(tempDir, "docs.bundle.txt")
+	bundle := "--linenum file\n--toc\n\nfile2.md\nfile1.txt:L1\n"
+	if err := os.WriteFile(bundlePath, []byte(bundle), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand("--resolve-only", "--theme", "classic-dark", bundlePath)
+	if err != nil {
+		t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, output)
+	}
+
+	var plan struct {
+		Options map[string]interface{} `json:"options"`
+		Files   []struct {
+			Path   string   `json:"path"`
+			Source string   `json:"source"`
+			Ranges []string `json:"ranges"`
+		} `json:"files"`
+		Bundles []string `json:"bundles"`
+	}
+	if err := json.Unmarshal([]byte(output), &plan); err != nil {
+		t.Fatalf("Output is not a JSON plan: %v\n%s", err, output)
+	}
+
+	// Options are merged: bundle values plus the command line theme
+	if plan.Options["LineNumbers"] != "file" || plan.Options["ShowTOC"] != true || plan.Options["Theme"] != "classic-dark" {
+		t.Errorf("Unexpected merged options: %v", plan.Options)
+	}
+
+	if len(plan.Files) != 2 {
+		t.Fatalf("Expected 2 files, got %+v", plan.Files)
+	}
+	if filepath.Base(plan.Files[0].Path) != "file2.md" || len(plan.Files[0].Ranges) != 0 {
+		t.Errorf("Unexpected first file: %+v", plan.Files[0])
+	}
+	if filepath.Base(plan.Files[1].Path) != "file1.txt:L1" || len(plan.Files[1].Ranges) != 1 || plan.Files[1].Ranges[0] != "L1" {
+		t.Errorf("Unexpected second file: %+v", plan.Files[1])
+	}
+	for _, file := range plan.Files {
+		if file.Source != "bundle: docs.bundle.txt" {
+			t.Errorf("Expected the bundle as the source of %s, got %q", file.Path, file.Source)
+		}
+	}
+
+	if len(plan.Bundles) != 1 || plan.Bundles[0] != bundlePath {
+		t.Errorf("Expected bundles [%s], got %v", bundlePath, plan.Bundles)
+	}
+}
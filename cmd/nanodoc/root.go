@@ -2,9 +2,12 @@ package main
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/arthur-debert/nanodoc/pkg/nanodoc"
 	"github.com/spf13/cobra"
@@ -13,22 +16,93 @@ import (
 
 var (
 	// Flags
-	lineNum            string
-	toc                bool
-	theme              string
-	showFilenames      bool
-	fileNumbering      string
-	filenameFormat     string  // renamed from headerFormat
-	filenameAlign      string  // renamed from headerAlign
-	filenameBanner     string  // renamed from headerStyle
-	pageWidth          int
-	additionalExt      []string
-	includePatterns    []string
-	excludePatterns    []string
-	dryRun             bool
-	saveToBundlePath   string
-	outputFormat       string
-	explicitFlags      map[string]bool
+	lineNum           string
+	toc               bool
+	theme             string
+	showFilenames     bool
+	fileNumbering     string
+	filenameFormat    string // renamed from headerFormat
+	filenameAlign     string // renamed from headerAlign
+	filenameBanner    string // renamed from headerStyle
+	pageWidth         int
+	additionalExt     []string
+	includePatterns   []string
+	excludePatterns   []string
+	dryRun            bool
+	showSkipped       bool
+	saveToBundlePath  string
+	outputFormat      string
+	fileIndex         bool
+	fileIndexPosition string
+	mdCodeFences      bool
+	mdCollapsible     bool
+	mdNormalize       bool
+	mdFrontMatter     []string
+	checkLinks        bool
+	checkExternal     bool
+	countOnly         bool
+	countByExt        bool
+	overflow          string
+	noDefaultExt      bool
+	uniqueBy          string
+	gitDiff           string
+	gitTracked        bool
+	onDuplicate       string
+	dedupeLines       bool
+	dedupeLinesScope  string
+	maxFileSize       string
+	onOversize        string
+	keepGoing         bool
+	bundleAbsPaths    bool
+	bundleMinimal     bool
+	dumpOptions       bool
+	bundleAsSection   bool
+	sortBundle        bool
+	renderMarkdown    bool
+	renderMdTables    bool
+	emptyNoNumber     bool
+	commentSections   bool
+	outputFile        string
+	appendOutput      bool
+	sections          []string
+	sectionOnly       bool
+	highlightLines    string
+	highlightLegend   string
+	highlightGutter   bool
+	footnotePaths     bool
+	headerShowSize    bool
+	headerShowRange   bool
+	rtl               bool
+	zebra             bool
+	highlight         bool
+	colorMode         string
+	tocMaxEntries     int
+	anchorEvery       int
+	jsonContent       bool
+	plainHeaders      bool
+	squeezeBlanks     bool
+	blankMarker       bool
+	compact           bool
+	reflow            bool
+	wrapWidth         int
+	strict            bool
+	stripFrontMatter  bool
+	tocLinks          bool
+	headersOnly       bool
+	lineNumScope      string
+	lineNumMinLines   int
+	headerChar        string
+	fileNumberReset   string
+	seqStart          int
+	resolveOnly       bool
+	cacheDir          string
+	verbose           bool
+	globBase          string
+	processIncludesIn []string
+	emptyDocMessage   string
+	bundleVars        []string
+	bundleVarDefault  string
+	explicitFlags     map[string]bool
 
 	// Version information - set by ldflags during build
 	version = "dev"     // Set by goreleaser: -X main.version={{.Version}}
@@ -43,12 +117,12 @@ var rootLongHelp string
 var rootExamples string
 
 var rootCmd = &cobra.Command{
-	Use:     "nanodoc [paths...]",
-	Short:   RootShort,
-	Long:    rootLongHelp,
-	Example: rootExamples,
-	Args:    cobra.ArbitraryArgs,
-	SilenceUsage: true,
+	Use:           "nanodoc [paths...]",
+	Short:         RootShort,
+	Long:          rootLongHelp,
+	Example:       rootExamples,
+	Args:          cobra.ArbitraryArgs,
+	SilenceUsage:  true,
 	SilenceErrors: false,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		// Default to file completion
@@ -60,17 +134,41 @@ var rootCmd = &cobra.Command{
 			fmt.Printf(VersionFormat, version, commit, date)
 			return nil
 		}
-		
+
 		// Check args only if not printing version
-		if len(args) < 1 {
+		if len(args) < 1 && gitDiff == "" && !gitTracked {
 			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Missing paths to bundle: $ nanodoc <path...>")
 			_, _ = fmt.Fprintln(cmd.ErrOrStderr())
 			cmd.SilenceUsage = false
-			return fmt.Errorf("")
+			return withExitCode(ExitUsage, fmt.Errorf(""))
 		}
 		// Track explicitly set flags
 		explicitFlags = nanodoc.TrackExplicitFlags(cmd)
 
+		if appendOutput && outputFile == "" {
+			return withExitCode(ExitUsage, errors.New(ErrAppendNeedsOutput))
+		}
+		if seqStart < 1 {
+			return withExitCode(ExitUsage, fmt.Errorf(ErrInvalidSeqStart, seqStart))
+		}
+		switch colorMode {
+		case nanodoc.ColorAuto, nanodoc.ColorAlways, nanodoc.ColorNever:
+		default:
+			return withExitCode(ExitUsage, fmt.Errorf(ErrInvalidColor, colorMode))
+		}
+		// --output - is the same as no --output
+		if outputFile == "-" {
+			outputFile = ""
+		}
+
+		// Without --output-format, an output file's extension picks the format
+		if outputFile != "" && !explicitFlags["output-format"] {
+			if format := inferOutputFormat(outputFile); format != "" {
+				outputFormat = format
+				explicitFlags["output-format"] = true
+			}
+		}
+
 		// 1. Set up Formatting Options first
 		opts, err := nanodoc.BuildFormattingOptions(
 			lineNum,
@@ -88,16 +186,118 @@ var rootCmd = &cobra.Command{
 			outputFormat,
 		)
 		if err != nil {
-			return err
+			return withExitCode(ExitUsage, err)
 		}
+		opts.ShowFileIndex = fileIndex
+		opts.FileIndexPosition = fileIndexPosition
+		opts.MarkdownCodeFences = mdCodeFences
+		opts.MarkdownCollapsible = mdCollapsible
+		opts.MarkdownNormalize = mdNormalize
+		opts.MarkdownFrontMatter = mdFrontMatter
+		opts.Overflow = overflow
+		opts.UniqueBy = uniqueBy
+		opts.OnDuplicate = onDuplicate
+		opts.DedupeLines = dedupeLines
+		opts.DedupeLinesScope = dedupeLinesScope
+		opts.BundleAsSection = bundleAsSection
+		opts.SortBundle = sortBundle
+		opts.RenderMarkdown = renderMarkdown
+		opts.RenderMarkdownTables = renderMdTables
+		opts.EmptyNoNumber = emptyNoNumber
+		opts.BundleCommentsAsSections = commentSections
+		opts.Sections = sections
+		opts.SectionOnly = sectionOnly
+		opts.ProcessIncludesIn = processIncludesIn
+		opts.HighlightLines = highlightLines
+		opts.HighlightLegend = highlightLegend
+		opts.HighlightGutter = highlightGutter
+		opts.FootnotePaths = footnotePaths
+		opts.HeaderShowSize = headerShowSize
+		opts.HeaderShowRange = headerShowRange
+		opts.RTL = rtl
+		opts.Zebra = zebra
+		opts.Highlight = highlight
+		opts.TOCMaxEntries = tocMaxEntries
+		opts.AnchorEvery = anchorEvery
+		opts.JSONOmitContent = !jsonContent
+		opts.PlainHeaders = plainHeaders
+		opts.SqueezeBlanks = squeezeBlanks
+		opts.BlankMarker = blankMarker
+		opts.Compact = compact
+		opts.Reflow = reflow
+		opts.WrapWidth = wrapWidth
+		opts.Strict = strict
+		opts.StripFrontMatter = stripFrontMatter
+		opts.TOCLinks = tocLinks
+		opts.HeadersOnly = headersOnly
+		opts.LineNumberScope = lineNumScope
+		opts.LineNumberMinLines = lineNumMinLines
+		opts.HeaderChar = headerChar
+		opts.FileNumberReset = fileNumberReset
+		opts.SequenceStart = seqStart
+		opts.CacheDir = cacheDir
+		if maxFileSize != "" {
+			opts.MaxFileSize, err = nanodoc.ParseFileSize(maxFileSize)
+			if err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+		}
+		opts.OnOversize = onOversize
+		opts.EmptyDocumentMessage = emptyDocMessage
+		opts.BundleVars, err = nanodoc.ParseBundleVars(bundleVars)
+		if err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+		if cmd.Flags().Changed("bundle-var-default") {
+			opts.BundleVarDefault = &bundleVarDefault
+		}
+		if err := opts.Validate(); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+
+		// Time the pipeline stages for --verbose
+		var timings stageTimings
+		clock := time.Now()
+
+		// A "-" source reads standard input, buffered so it is read once
+		nanodoc.SetStdin(cmd.InOrStdin())
 
 		// 2. Resolve Paths with pattern options
 		pathOpts := &nanodoc.FormattingOptions{
 			AdditionalExtensions: additionalExt,
-			IncludePatterns: includePatterns,
-			ExcludePatterns: excludePatterns,
+			NoDefaultExtensions:  noDefaultExt,
+			IncludePatterns:      includePatterns,
+			ExcludePatterns:      excludePatterns,
+			GlobBase:             globBase,
+		}
+
+		// Files from git are bundled after the paths given as arguments
+		if gitDiff != "" || gitTracked {
+			var gitPaths []string
+			if gitDiff != "" {
+				gitPaths, err = nanodoc.GitChangedFiles(".", gitDiff, pathOpts)
+			} else {
+				gitPaths, err = nanodoc.GitTrackedFiles(".", pathOpts)
+			}
+			if err != nil {
+				return fmt.Errorf(ErrListingGitFiles, err)
+			}
+			args = append(args, gitPaths...)
+		}
+
+		var pathInfos []nanodoc.PathInfo
+		if keepGoing {
+			var skipped []error
+			pathInfos, skipped, err = nanodoc.ResolvePathsKeepGoing(args, pathOpts)
+			if len(skipped) > 0 {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), SkippedSources, len(skipped))
+				for _, skipErr := range skipped {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  - %v\n", skipErr)
+				}
+			}
+		} else {
+			pathInfos, err = nanodoc.ResolvePathsWithOptions(args, pathOpts)
 		}
-		pathInfos, err := nanodoc.ResolvePathsWithOptions(args, pathOpts)
 		if err != nil {
 			return fmt.Errorf(ErrResolvingPaths, err)
 		}
@@ -108,51 +308,115 @@ var rootCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf(ErrGeneratingDryRun, err)
 			}
-			
+			if showSkipped {
+				dryRunInfo.SkippedByExtension, err = nanodoc.FindSkippedFiles(pathInfos, pathOpts)
+				if err != nil {
+					return fmt.Errorf(ErrGeneratingDryRun, err)
+				}
+			}
+
 			output := nanodoc.FormatDryRunOutput(dryRunInfo)
+			if countOnly {
+				output = nanodoc.FormatCountSummary(dryRunInfo)
+			}
 			_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
 			return nil
 		}
 
-		// 3. Extract bundle option lines and merge with command options
-		bundleOptionLines, err := nanodoc.ExtractBundleOptionLines(pathInfos)
-		if err != nil {
-			return fmt.Errorf("error extracting bundle options: %w", err)
-		}
-		
-		// Parse bundle options using Cobra if there are any
-		mergedOpts := opts
-		if len(bundleOptionLines) > 0 {
-			bundleOpts, err := nanodoc.ParseBundleOptions(bundleOptionLines)
+		timings.resolve = since(&clock)
+
+		// If only resolving, print the plan as JSON and exit
+		if resolveOnly {
+			mergedOpts, err := nanodoc.MergeBundleOptions(pathInfos, opts, explicitFlags)
+			if err != nil {
+				return err
+			}
+			plan, err := nanodoc.GenerateResolvePlan(pathInfos, mergedOpts)
 			if err != nil {
-				return fmt.Errorf("error parsing bundle options: %w", err)
+				return fmt.Errorf(ErrGeneratingPlan, err)
 			}
-			// Merge options - command line takes precedence
-			mergedOpts = nanodoc.MergeOptionsWithExplicitFlags(bundleOpts, opts, explicitFlags)
+			output, err := nanodoc.FormatResolvePlan(plan)
+			if err != nil {
+				return fmt.Errorf(ErrGeneratingPlan, err)
+			}
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
 		}
-		
-		// 4. Build Document with merged options
-		doc, err := nanodoc.BuildDocument(pathInfos, mergedOpts)
+
+		// 3. Merge bundle options with command options - the command line
+		// takes precedence for the flags it sets - then build the document
+		// and its formatting context
+		doc, ctx, err := nanodoc.PrepareDocument(pathInfos, opts, explicitFlags)
 		if err != nil {
+			if errors.Is(err, nanodoc.ErrInvalidTheme) {
+				return withExitCode(ExitUsage, fmt.Errorf(ErrCreatingContext, err))
+			}
 			return fmt.Errorf(ErrBuildingDocument, err)
 		}
+		timings.extract = since(&clock)
+		if len(doc.Skipped) > 0 {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), SkippedOversize, len(doc.Skipped))
+			for _, skipErr := range doc.Skipped {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  - %v\n", skipErr)
+			}
+		}
 
-		// 5. Create Formatting Context
-		ctx, err := nanodoc.NewFormattingContext(doc.FormattingOptions)
-		if err != nil {
-			return fmt.Errorf(ErrCreatingContext, err)
+		// Show the merged options the document will be rendered with
+		if dumpOptions {
+			_, _ = fmt.Fprint(cmd.ErrOrStderr(), nanodoc.FormatOptionsDump(doc.FormattingOptions))
+		}
+
+		// If checking links, report broken targets instead of rendering
+		if checkLinks {
+			result, err := nanodoc.CheckLinks(doc, checkExternal)
+			if err != nil {
+				return fmt.Errorf(ErrCheckingLinks, err)
+			}
+
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), nanodoc.FormatLinkCheckOutput(result))
+			if len(result.Broken) > 0 {
+				return fmt.Errorf(ErrBrokenLinks, len(result.Broken))
+			}
+			return nil
+		}
+
+		// If only counting, report file and line totals instead of rendering
+		if countOnly {
+			counts := nanodoc.CountContent(doc)
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), nanodoc.FormatCountOutput(counts, countByExt))
+			return nil
 		}
 
-		// 5. Render Document
+		// --color auto only writes escape sequences to a terminal that shows
+		// them; without colors there are no hyperlinks or highlighting either
+		ctx.Color = colorMode
+		if colorMode == nanodoc.ColorAuto {
+			ctx.Color = nanodoc.ColorNever
+			if outputFile == "" && nanodoc.SupportsColor(int(os.Stdout.Fd())) {
+				ctx.Color = nanodoc.ColorAlways
+			}
+		}
+
+		// 4. Render Document
 		output, err := nanodoc.RenderDocument(doc, ctx)
 		if err != nil {
-			return fmt.Errorf(ErrRenderingDocument, err)
+			return withExitCode(ExitRender, fmt.Errorf(ErrRenderingDocument, err))
 		}
+		timings.render = since(&clock)
 
-		// 6. Print to stdout
-		_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
+		// 5. Print to stdout, or write the output file
+		if outputFile != "" {
+			if err := writeOutputFile(outputFile, output, appendOutput); err != nil {
+				return fmt.Errorf(ErrWritingOutput, err)
+			}
+		} else {
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
+		}
+		if verbose {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), timings)
+		}
 
-		// 7. Save to bundle if requested
+		// 6. Save to bundle if requested
 		if saveToBundlePath != "" {
 			if err := saveBundleFile(saveToBundlePath, args, opts, cmd); err != nil {
 				return err
@@ -164,6 +428,70 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// outputFormatsByExt maps output file extensions to the format they imply
+var outputFormatsByExt = map[string]string{
+	".md":       "markdown",
+	".markdown": "markdown",
+	".txt":      "plain",
+	".json":     "json",
+}
+
+// inferOutputFormat returns the output format implied by the extension of
+// path, or "" to keep the default
+func inferOutputFormat(path string) string {
+	return outputFormatsByExt[strings.ToLower(filepath.Ext(path))]
+}
+
+// writeOutputFile writes output to path, or with appendMode adds it to the
+// end of the file, creating it and its parent directories when missing.
+// A replaced file is written atomically, so a failed run never leaves it
+// half written.
+func writeOutputFile(path, output string, appendMode bool) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if !appendMode {
+		return writeFileAtomic(path, output)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(output); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeFileAtomic writes output to a temporary file next to path and renames
+// it into place, keeping the mode of the file it replaces
+func writeFileAtomic(path, output string) error {
+	dir := filepath.Dir(path)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(output); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
 
 // saveBundleFile saves the current invocation as a bundle file
 func saveBundleFile(path string, args []string, opts nanodoc.FormattingOptions, cmd *cobra.Command) error {
@@ -180,10 +508,24 @@ func saveBundleFile(path string, args []string, opts nanodoc.FormattingOptions,
 	// Write options section
 	content.WriteString("# --- Options ---\n")
 
-	// Always write all options including defaults
+	// Options that always carry a value are written even at their defaults,
+	// unless --bundle-minimal asks for only the non-default ones
+	writeValue := func(name, value string) {
+		if bundleMinimal {
+			if flag := cmd.Flags().Lookup(name); flag != nil && flag.DefValue == value {
+				return
+			}
+		}
+		content.WriteString(fmt.Sprintf("--%s=%s\n", name, value))
+	}
+
 	if opts.ShowTOC {
 		content.WriteString("--toc\n")
 	}
+	if opts.ShowFileIndex {
+		content.WriteString("--file-index\n")
+		content.WriteString(fmt.Sprintf("--file-index-position=%s\n", opts.FileIndexPosition))
+	}
 
 	// Line numbering
 	switch opts.LineNumbers {
@@ -194,7 +536,7 @@ func saveBundleFile(path string, args []string, opts nanodoc.FormattingOptions,
 	}
 
 	// Theme
-	content.WriteString(fmt.Sprintf("--theme=%s\n", opts.Theme))
+	writeValue("theme", opts.Theme)
 
 	// File filenames
 	if !opts.ShowFilenames {
@@ -202,18 +544,132 @@ func saveBundleFile(path string, args []string, opts nanodoc.FormattingOptions,
 	}
 
 	// File header format
-	content.WriteString(fmt.Sprintf("--header-format=%s\n", string(opts.HeaderFormat)))
-	content.WriteString(fmt.Sprintf("--header-align=%s\n", opts.HeaderAlignment))
-	content.WriteString(fmt.Sprintf("--header-style=%s\n", opts.HeaderStyle))
-	content.WriteString(fmt.Sprintf("--page-width=%d\n", opts.PageWidth))
+	writeValue("header-format", string(opts.HeaderFormat))
+	writeValue("header-align", opts.HeaderAlignment)
+	writeValue("header-style", opts.HeaderStyle)
+	if opts.HeaderChar != "" {
+		content.WriteString(fmt.Sprintf("--header-char=%s\n", opts.HeaderChar))
+	}
+	if opts.FileNumberReset != "" && opts.FileNumberReset != nanodoc.FileNumberResetNone {
+		content.WriteString(fmt.Sprintf("--file-number-reset=%s\n", opts.FileNumberReset))
+	}
+	if opts.SequenceStart > 1 {
+		content.WriteString(fmt.Sprintf("--seq-start=%d\n", opts.SequenceStart))
+	}
+	writeValue("page-width", fmt.Sprintf("%d", opts.PageWidth))
+	if opts.LineNumberScope != "" && opts.LineNumberScope != nanodoc.LineNumberScopeAll {
+		content.WriteString(fmt.Sprintf("--linenum-scope=%s\n", opts.LineNumberScope))
+	}
+	if opts.LineNumberMinLines > 0 {
+		content.WriteString(fmt.Sprintf("--linenum-min-lines=%d\n", opts.LineNumberMinLines))
+	}
+	if opts.Overflow != "" && opts.Overflow != nanodoc.OverflowNone {
+		content.WriteString(fmt.Sprintf("--overflow=%s\n", opts.Overflow))
+	}
+	if opts.BundleAsSection {
+		content.WriteString("--bundle-as-section\n")
+	}
+	if opts.SortBundle {
+		content.WriteString("--sort-bundle\n")
+	}
+	if opts.BundleCommentsAsSections {
+		content.WriteString("--bundle-comments-as-sections\n")
+	}
+	if opts.UniqueBy != "" && opts.UniqueBy != nanodoc.UniqueByNone {
+		content.WriteString(fmt.Sprintf("--unique-by=%s\n", opts.UniqueBy))
+	}
+	if opts.OnDuplicate != "" && opts.OnDuplicate != nanodoc.DuplicateKeep {
+		content.WriteString(fmt.Sprintf("--on-duplicate=%s\n", opts.OnDuplicate))
+	}
+	if opts.DedupeLines {
+		content.WriteString("--dedupe-lines\n")
+	}
+	if opts.DedupeLinesScope != "" && opts.DedupeLinesScope != nanodoc.DedupeScopeDocument {
+		content.WriteString(fmt.Sprintf("--dedupe-lines-scope=%s\n", opts.DedupeLinesScope))
+	}
 
 	// File numbering
-	content.WriteString(fmt.Sprintf("--file-numbering=%s\n", string(opts.SequenceStyle)))
+	writeValue("file-numbering", string(opts.SequenceStyle))
 
 	// Output format
 	if opts.OutputFormat != "" && opts.OutputFormat != "term" {
 		content.WriteString(fmt.Sprintf("--output-format=%s\n", opts.OutputFormat))
 	}
+	if opts.MarkdownCodeFences {
+		content.WriteString("--md-code-fences\n")
+	}
+	if opts.MarkdownCollapsible {
+		content.WriteString("--md-collapsible\n")
+	}
+	if opts.MarkdownNormalize {
+		content.WriteString("--md-normalize\n")
+	}
+	if opts.RenderMarkdown {
+		content.WriteString("--render-markdown\n")
+	}
+	if opts.RenderMarkdownTables {
+		content.WriteString("--render-markdown-tables\n")
+	}
+	if opts.EmptyNoNumber {
+		content.WriteString("--empty-no-number\n")
+	}
+	if opts.FootnotePaths {
+		content.WriteString("--footnote-paths\n")
+	}
+	if opts.HeaderShowSize {
+		content.WriteString("--header-show-size\n")
+	}
+	if opts.HeaderShowRange {
+		content.WriteString("--header-show-range\n")
+	}
+	if opts.RTL {
+		content.WriteString("--rtl\n")
+	}
+	if opts.Zebra {
+		content.WriteString("--zebra\n")
+	}
+	if opts.Highlight {
+		content.WriteString("--highlight\n")
+	}
+	if opts.TOCMaxEntries > 0 {
+		content.WriteString(fmt.Sprintf("--toc-max-entries=%d\n", opts.TOCMaxEntries))
+	}
+	if opts.AnchorEvery > 0 {
+		content.WriteString(fmt.Sprintf("--anchor-every=%d\n", opts.AnchorEvery))
+	}
+	if opts.JSONOmitContent {
+		content.WriteString("--json-include-content=false\n")
+	}
+	if opts.PlainHeaders {
+		content.WriteString("--plain-headers\n")
+	}
+	if opts.SqueezeBlanks {
+		content.WriteString("--squeeze-blanks\n")
+	}
+	if opts.BlankMarker {
+		content.WriteString("--blank-marker\n")
+	}
+	if opts.Compact {
+		content.WriteString("--compact\n")
+	}
+	if opts.Reflow {
+		content.WriteString("--reflow\n")
+	}
+	if opts.WrapWidth > 0 {
+		content.WriteString(fmt.Sprintf("--wrap=%d\n", opts.WrapWidth))
+	}
+	if opts.Strict {
+		content.WriteString("--strict\n")
+	}
+	if opts.StripFrontMatter {
+		content.WriteString("--strip-front-matter\n")
+	}
+	if opts.TOCLinks {
+		content.WriteString("--toc-links\n")
+	}
+	if opts.HeadersOnly {
+		content.WriteString("--headers-only\n")
+	}
 
 	// Additional extensions
 	for _, ext := range opts.AdditionalExtensions {
@@ -231,8 +687,16 @@ func saveBundleFile(path string, args []string, opts nanodoc.FormattingOptions,
 	}
 
 	// Write content section
+	contentPaths := args
+	if bundleAbsPaths {
+		var err error
+		contentPaths, err = absolutePaths(args)
+		if err != nil {
+			return err
+		}
+	}
 	content.WriteString("\n# --- Content ---\n")
-	for _, arg := range args {
+	for _, arg := range contentPaths {
 		content.WriteString(arg + "\n")
 	}
 
@@ -240,7 +704,23 @@ func saveBundleFile(path string, args []string, opts nanodoc.FormattingOptions,
 	return os.WriteFile(path, []byte(content.String()), 0644)
 }
 
+// absolutePaths converts paths to absolute form, keeping any line range suffix
+func absolutePaths(paths []string) ([]string, error) {
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		base, rangeSuffix := path, ""
+		if idx := strings.LastIndex(path, ":L"); idx > 0 {
+			base, rangeSuffix = path[:idx], path[idx:]
+		}
 
+		absPath, err := filepath.Abs(base)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving %s: %w", path, err)
+		}
+		result = append(result, absPath+rangeSuffix)
+	}
+	return result, nil
+}
 
 // reconstructCommand reconstructs the command-line invocation from cobra flags and args
 func reconstructCommand(cmd *cobra.Command, args []string) string {
@@ -278,78 +758,257 @@ func Execute() error {
 }
 
 func init() {
+	setupFlags(rootCmd)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(ExitUsage, err)
+	})
+
+	// Initialize custom help system
+	initHelpSystem()
+}
+
+// setupFlags defines the root command flags, binding them to their defaults
+func setupFlags(cmd *cobra.Command) {
 	// Line numbering flag
-	rootCmd.Flags().StringVarP(&lineNum, "linenum", "l", "", FlagLineNum)
-	_ = rootCmd.RegisterFlagCompletionFunc("linenum", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cmd.Flags().StringVarP(&lineNum, "linenum", "l", "", FlagLineNum)
+	_ = cmd.RegisterFlagCompletionFunc("linenum", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"file", "global"}, cobra.ShellCompDirectiveNoFileComp
 	})
-	_ = rootCmd.Flags().SetAnnotation("linenum", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("linenum", "group", []string{"Formatting"})
+	cmd.Flags().StringVar(&lineNumScope, "linenum-scope", nanodoc.LineNumberScopeAll, FlagLineNumScope)
+	_ = cmd.RegisterFlagCompletionFunc("linenum-scope", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.LineNumberScopeAll, nanodoc.LineNumberScopeCode, nanodoc.LineNumberScopeText}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("linenum-scope", "group", []string{"Formatting"})
+	cmd.Flags().IntVar(&lineNumMinLines, "linenum-min-lines", 0, FlagLineNumMinLines)
+	_ = cmd.Flags().SetAnnotation("linenum-min-lines", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&emptyNoNumber, "empty-no-number", false, FlagEmptyNoNumber)
+	_ = cmd.Flags().SetAnnotation("empty-no-number", "group", []string{"Formatting"})
 
 	// TOC flag
-	rootCmd.Flags().BoolVar(&toc, "toc", false, FlagTOC)
-	_ = rootCmd.Flags().SetAnnotation("toc", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&toc, "toc", false, FlagTOC)
+	_ = cmd.Flags().SetAnnotation("toc", "group", []string{"Features"})
+	cmd.Flags().IntVar(&tocMaxEntries, "toc-max-entries", 0, FlagTOCMaxEntries)
+	_ = cmd.Flags().SetAnnotation("toc-max-entries", "group", []string{"Features"})
+
+	// File index flags
+	cmd.Flags().BoolVar(&fileIndex, "file-index", false, FlagFileIndex)
+	cmd.Flags().StringVar(&fileIndexPosition, "file-index-position", nanodoc.FileIndexBeforeTOC, FlagFileIndexPosition)
+	_ = cmd.RegisterFlagCompletionFunc("file-index-position", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.FileIndexBeforeTOC, nanodoc.FileIndexAfterTOC}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("file-index", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("file-index-position", "group", []string{"Features"})
 
 	// Theme flag
-	rootCmd.Flags().StringVar(&theme, "theme", "classic", FlagTheme)
-	_ = rootCmd.RegisterFlagCompletionFunc("theme", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cmd.Flags().StringVar(&theme, "theme", "classic", FlagTheme)
+	_ = cmd.RegisterFlagCompletionFunc("theme", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		themes, err := nanodoc.GetAvailableThemes()
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
 		return themes, cobra.ShellCompDirectiveNoFileComp
 	})
-	_ = rootCmd.Flags().SetAnnotation("theme", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("theme", "group", []string{"Formatting"})
 
 	// File name flags
-	rootCmd.Flags().BoolVar(&showFilenames, "filenames", true, FlagFilenames)
-	rootCmd.Flags().StringVar(&filenameFormat, "header-format", "nice", FlagHeaderFormat)
-	_ = rootCmd.RegisterFlagCompletionFunc("header-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"nice", "simple", "path", "filename", "title"}, cobra.ShellCompDirectiveNoFileComp
+	cmd.Flags().BoolVar(&showFilenames, "filenames", true, FlagFilenames)
+	cmd.Flags().StringVar(&filenameFormat, "header-format", "nice", FlagHeaderFormat)
+	_ = cmd.RegisterFlagCompletionFunc("header-format", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(nanodoc.HeaderFormatNice), string(nanodoc.HeaderFormatFilename), string(nanodoc.HeaderFormatPath), string(nanodoc.HeaderFormatRelative)}, cobra.ShellCompDirectiveNoFileComp
 	})
-	rootCmd.Flags().StringVar(&filenameAlign, "header-align", "left", FlagHeaderAlign)
-	_ = rootCmd.RegisterFlagCompletionFunc("header-align", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cmd.Flags().StringVar(&filenameAlign, "header-align", "left", FlagHeaderAlign)
+	_ = cmd.RegisterFlagCompletionFunc("header-align", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"left", "center", "right"}, cobra.ShellCompDirectiveNoFileComp
 	})
-	rootCmd.Flags().StringVar(&filenameBanner, "header-style", "none", FlagHeaderStyle)
-	_ = rootCmd.RegisterFlagCompletionFunc("header-style", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cmd.Flags().StringVar(&filenameBanner, "header-style", "none", FlagHeaderStyle)
+	cmd.Flags().StringVar(&headerChar, "header-char", "", FlagHeaderChar)
+	_ = cmd.RegisterFlagCompletionFunc("header-style", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		// Dynamically get banner styles from registry
 		return nanodoc.GetBannerStyleNames(), cobra.ShellCompDirectiveNoFileComp
 	})
 	// Auto-detect terminal width as default for page width
 	defaultPageWidth := nanodoc.GetTerminalWidth()
-	rootCmd.Flags().IntVar(&pageWidth, "page-width", defaultPageWidth, FlagPageWidth)
-	rootCmd.Flags().StringVar(&fileNumbering, "file-numbering", "numerical", FlagFileNumbering)
-	_ = rootCmd.RegisterFlagCompletionFunc("file-numbering", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"numerical", "alphabetical", "roman"}, cobra.ShellCompDirectiveNoFileComp
+	cmd.Flags().IntVar(&pageWidth, "page-width", defaultPageWidth, FlagPageWidth)
+	cmd.Flags().StringVar(&fileNumbering, "file-numbering", "numerical", FlagFileNumbering)
+	_ = cmd.RegisterFlagCompletionFunc("file-numbering", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"numerical", "alphabetical", "alpha-upper", "roman", "roman-upper"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&fileNumberReset, "file-number-reset", nanodoc.FileNumberResetNone, FlagFileNumberReset)
+	_ = cmd.RegisterFlagCompletionFunc("file-number-reset", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.FileNumberResetNone, nanodoc.FileNumberResetBundle, nanodoc.FileNumberResetDir}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().IntVar(&seqStart, "seq-start", 1, FlagSeqStart)
+	_ = cmd.Flags().SetAnnotation("filenames", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("header-format", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("header-align", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("header-style", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("header-char", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("page-width", "group", []string{"Formatting"})
+	cmd.Flags().StringVar(&overflow, "overflow", nanodoc.OverflowNone, FlagOverflow)
+	_ = cmd.RegisterFlagCompletionFunc("overflow", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.OverflowNone, nanodoc.OverflowTruncate, nanodoc.OverflowWrap}, cobra.ShellCompDirectiveNoFileComp
 	})
-	_ = rootCmd.Flags().SetAnnotation("filenames", "group", []string{"Features"})
-	_ = rootCmd.Flags().SetAnnotation("header-format", "group", []string{"Formatting"})
-	_ = rootCmd.Flags().SetAnnotation("header-align", "group", []string{"Formatting"})
-	_ = rootCmd.Flags().SetAnnotation("header-style", "group", []string{"Formatting"})
-	_ = rootCmd.Flags().SetAnnotation("page-width", "group", []string{"Formatting"})
-	_ = rootCmd.Flags().SetAnnotation("file-numbering", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("overflow", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&rtl, "rtl", false, FlagRTL)
+	_ = cmd.Flags().SetAnnotation("rtl", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&zebra, "zebra", false, FlagZebra)
+	_ = cmd.Flags().SetAnnotation("zebra", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&highlight, "highlight", false, FlagHighlight)
+	_ = cmd.Flags().SetAnnotation("highlight", "group", []string{"Formatting"})
+	cmd.Flags().StringVar(&colorMode, "color", nanodoc.ColorAuto, FlagColor)
+	_ = cmd.RegisterFlagCompletionFunc("color", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.ColorAuto, nanodoc.ColorAlways, nanodoc.ColorNever}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("color", "group", []string{"Formatting"})
+	cmd.Flags().IntVar(&anchorEvery, "anchor-every", 0, FlagAnchorEvery)
+	_ = cmd.Flags().SetAnnotation("anchor-every", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&plainHeaders, "plain-headers", false, FlagPlainHeaders)
+	_ = cmd.Flags().SetAnnotation("plain-headers", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&squeezeBlanks, "squeeze-blanks", false, FlagSqueezeBlanks)
+	_ = cmd.Flags().SetAnnotation("squeeze-blanks", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&blankMarker, "blank-marker", false, FlagBlankMarker)
+	_ = cmd.Flags().SetAnnotation("blank-marker", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&compact, "compact", false, FlagCompact)
+	_ = cmd.Flags().SetAnnotation("compact", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&reflow, "reflow", false, FlagReflow)
+	_ = cmd.Flags().SetAnnotation("reflow", "group", []string{"Formatting"})
+	cmd.Flags().IntVar(&wrapWidth, "wrap", 0, FlagWrap)
+	_ = cmd.Flags().SetAnnotation("wrap", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&strict, "strict", false, FlagStrict)
+	_ = cmd.Flags().SetAnnotation("strict", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&stripFrontMatter, "strip-front-matter", false, FlagStripFrontMatter)
+	_ = cmd.Flags().SetAnnotation("strip-front-matter", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&tocLinks, "toc-links", false, FlagTOCLinks)
+	_ = cmd.Flags().SetAnnotation("toc-links", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&headersOnly, "headers-only", false, FlagHeadersOnly)
+	_ = cmd.Flags().SetAnnotation("headers-only", "group", []string{"Features"})
+	cmd.Flags().StringVar(&highlightLines, "highlight-lines", "", FlagHighlightLines)
+	cmd.Flags().StringVar(&highlightLegend, "highlight-legend", "", FlagHighlightLegend)
+	_ = cmd.Flags().SetAnnotation("highlight-lines", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&highlightGutter, "highlight-gutter", false, FlagHighlightGutter)
+	_ = cmd.Flags().SetAnnotation("highlight-legend", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("highlight-gutter", "group", []string{"Formatting"})
+	_ = cmd.Flags().SetAnnotation("file-numbering", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("file-number-reset", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("seq-start", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&bundleAsSection, "bundle-as-section", false, FlagBundleAsSection)
+	_ = cmd.Flags().SetAnnotation("bundle-as-section", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&sortBundle, "sort-bundle", false, FlagSortBundle)
+	_ = cmd.Flags().SetAnnotation("sort-bundle", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&commentSections, "bundle-comments-as-sections", false, FlagCommentSections)
+	_ = cmd.Flags().SetAnnotation("bundle-comments-as-sections", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&footnotePaths, "footnote-paths", false, FlagFootnotePaths)
+	_ = cmd.Flags().SetAnnotation("footnote-paths", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&headerShowSize, "header-show-size", false, FlagHeaderShowSize)
+	_ = cmd.Flags().SetAnnotation("header-show-size", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&headerShowRange, "header-show-range", false, FlagHeaderShowRange)
+	_ = cmd.Flags().SetAnnotation("header-show-range", "group", []string{"Formatting"})
 
 	// File filtering flags
-	rootCmd.Flags().StringSliceVar(&additionalExt, "ext", []string{}, FlagExt)
-	rootCmd.Flags().StringSliceVar(&includePatterns, "include", []string{}, FlagInclude)
-	rootCmd.Flags().StringSliceVar(&excludePatterns, "exclude", []string{}, FlagExclude)
-	_ = rootCmd.Flags().SetAnnotation("ext", "group", []string{"File Selection"})
-	_ = rootCmd.Flags().SetAnnotation("include", "group", []string{"File Selection"})
-	_ = rootCmd.Flags().SetAnnotation("exclude", "group", []string{"File Selection"})
-	
+	cmd.Flags().StringSliceVar(&additionalExt, "ext", []string{}, FlagExt)
+	cmd.Flags().BoolVar(&noDefaultExt, "no-default-extensions", false, FlagNoDefaultExt)
+	cmd.Flags().StringSliceVar(&includePatterns, "include", []string{}, FlagInclude)
+	cmd.Flags().StringSliceVar(&excludePatterns, "exclude", []string{}, FlagExclude)
+	_ = cmd.Flags().SetAnnotation("ext", "group", []string{"File Selection"})
+	_ = cmd.Flags().SetAnnotation("no-default-extensions", "group", []string{"File Selection"})
+	cmd.Flags().StringVar(&uniqueBy, "unique-by", nanodoc.UniqueByNone, FlagUniqueBy)
+	_ = cmd.RegisterFlagCompletionFunc("unique-by", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.UniqueByNone, nanodoc.UniqueByBasename, nanodoc.UniqueByDir}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("unique-by", "group", []string{"File Selection"})
+	cmd.Flags().StringVar(&gitDiff, "git-diff", "", FlagGitDiff)
+	cmd.Flags().BoolVar(&gitTracked, "git-tracked", false, FlagGitTracked)
+	_ = cmd.Flags().SetAnnotation("git-diff", "group", []string{"File Selection"})
+	_ = cmd.Flags().SetAnnotation("git-tracked", "group", []string{"File Selection"})
+	cmd.Flags().StringVar(&onDuplicate, "on-duplicate", nanodoc.DuplicateKeep, FlagOnDuplicate)
+	_ = cmd.RegisterFlagCompletionFunc("on-duplicate", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.DuplicateKeep, nanodoc.DuplicateSkip, nanodoc.DuplicateError}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("on-duplicate", "group", []string{"File Selection"})
+	cmd.Flags().BoolVar(&dedupeLines, "dedupe-lines", false, FlagDedupeLines)
+	_ = cmd.Flags().SetAnnotation("dedupe-lines", "group", []string{"File Selection"})
+	cmd.Flags().StringVar(&dedupeLinesScope, "dedupe-lines-scope", nanodoc.DedupeScopeDocument, FlagDedupeLinesScope)
+	_ = cmd.RegisterFlagCompletionFunc("dedupe-lines-scope", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.DedupeScopeDocument, nanodoc.DedupeScopeFile}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("dedupe-lines-scope", "group", []string{"File Selection"})
+	cmd.Flags().StringVar(&maxFileSize, "max-file-size", "", FlagMaxFileSize)
+	cmd.Flags().StringVar(&onOversize, "on-oversize", nanodoc.OversizeSkip, FlagOnOversize)
+	_ = cmd.RegisterFlagCompletionFunc("on-oversize", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{nanodoc.OversizeSkip, nanodoc.OversizeError}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.Flags().SetAnnotation("max-file-size", "group", []string{"File Selection"})
+	_ = cmd.Flags().SetAnnotation("on-oversize", "group", []string{"File Selection"})
+	_ = cmd.Flags().SetAnnotation("include", "group", []string{"File Selection"})
+	_ = cmd.Flags().SetAnnotation("exclude", "group", []string{"File Selection"})
+	cmd.Flags().StringArrayVar(&sections, "section", []string{}, FlagSection)
+	cmd.Flags().BoolVar(&sectionOnly, "section-only", false, FlagSectionOnly)
+	_ = cmd.Flags().SetAnnotation("section", "group", []string{"File Selection"})
+	_ = cmd.Flags().SetAnnotation("section-only", "group", []string{"File Selection"})
+	cmd.Flags().StringVar(&globBase, "glob-base", "", FlagGlobBase)
+	cmd.Flags().StringArrayVar(&processIncludesIn, "process-includes-in", []string{}, FlagProcessIncludesIn)
+	_ = cmd.Flags().SetAnnotation("process-includes-in", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("glob-base", "group", []string{"File Selection"})
+
 	// Other flags
-	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, FlagDryRun)
-	rootCmd.Flags().StringVar(&saveToBundlePath, "save-to-bundle", "", FlagSaveToBundle)
-	rootCmd.Flags().StringVar(&outputFormat, "output-format", "term", FlagOutputFormat)
-	_ = rootCmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"term", "plain", "markdown"}, cobra.ShellCompDirectiveNoFileComp
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, FlagDryRun)
+	cmd.Flags().BoolVar(&showSkipped, "show-skipped", false, FlagShowSkipped)
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, FlagKeepGoing)
+	cmd.Flags().StringVar(&saveToBundlePath, "save-to-bundle", "", FlagSaveToBundle)
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", FlagOutput)
+	cmd.Flags().BoolVar(&appendOutput, "append", false, FlagAppend)
+	cmd.Flags().StringVar(&outputFormat, "output-format", "term", FlagOutputFormat)
+	_ = cmd.RegisterFlagCompletionFunc("output-format", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{"term", "plain", "markdown", "json"}, nanodoc.GetOutputFormatNames()...), cobra.ShellCompDirectiveNoFileComp
 	})
-	rootCmd.Flags().BoolP("version", "v", false, FlagVersion)
-	_ = rootCmd.Flags().SetAnnotation("dry-run", "group", []string{"Misc"})
-	_ = rootCmd.Flags().SetAnnotation("save-to-bundle", "group", []string{"Features"})
-	_ = rootCmd.Flags().SetAnnotation("version", "group", []string{"Misc"})
-	_ = rootCmd.Flags().SetAnnotation("help", "group", []string{"Misc"})
-	
-	// Initialize custom help system
-	initHelpSystem()
-}
\ No newline at end of file
+	cmd.Flags().BoolVar(&jsonContent, "json-include-content", true, FlagJSONContent)
+	_ = cmd.Flags().SetAnnotation("json-include-content", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&mdCodeFences, "md-code-fences", false, FlagMdCodeFences)
+	_ = cmd.Flags().SetAnnotation("md-code-fences", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&mdCollapsible, "md-collapsible", false, FlagMdCollapsible)
+	_ = cmd.Flags().SetAnnotation("md-collapsible", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&mdNormalize, "md-normalize", false, FlagMdNormalize)
+	_ = cmd.Flags().SetAnnotation("md-normalize", "group", []string{"Formatting"})
+	cmd.Flags().StringArrayVar(&mdFrontMatter, "md-frontmatter", []string{}, FlagMdFrontMatter)
+	_ = cmd.Flags().SetAnnotation("md-frontmatter", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&renderMarkdown, "render-markdown", false, FlagRenderMarkdown)
+	_ = cmd.Flags().SetAnnotation("render-markdown", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&renderMdTables, "render-markdown-tables", false, FlagRenderMdTables)
+	_ = cmd.Flags().SetAnnotation("render-markdown-tables", "group", []string{"Formatting"})
+	cmd.Flags().BoolVar(&checkLinks, "check-links", false, FlagCheckLinks)
+	cmd.Flags().BoolVar(&checkExternal, "check-external", false, FlagCheckExternal)
+	cmd.Flags().BoolVar(&countOnly, "count-only", false, FlagCountOnly)
+	cmd.Flags().BoolVar(&countByExt, "by-ext", false, FlagCountByExt)
+	cmd.Flags().BoolP("version", "v", false, FlagVersion)
+	_ = cmd.Flags().SetAnnotation("dry-run", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("show-skipped", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("keep-going", "group", []string{"Misc"})
+	cmd.Flags().BoolVar(&verbose, "verbose", false, FlagVerbose)
+	_ = cmd.Flags().SetAnnotation("verbose", "group", []string{"Misc"})
+	cmd.Flags().BoolVar(&dumpOptions, "dump-options", false, FlagDumpOptions)
+	_ = cmd.Flags().SetAnnotation("dump-options", "group", []string{"Misc"})
+	cmd.Flags().BoolVar(&resolveOnly, "resolve-only", false, FlagResolveOnly)
+	_ = cmd.Flags().SetAnnotation("resolve-only", "group", []string{"Misc"})
+	cmd.Flags().StringVar(&emptyDocMessage, "empty-document-message", "", FlagEmptyDocMessage)
+	_ = cmd.Flags().SetAnnotation("empty-document-message", "group", []string{"Misc"})
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", FlagCacheDir)
+	_ = cmd.Flags().SetAnnotation("cache-dir", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("check-links", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("check-external", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("count-only", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("by-ext", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("output", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("append", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("save-to-bundle", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&bundleAbsPaths, "bundle-absolute-paths", false, FlagBundleAbsPaths)
+	_ = cmd.Flags().SetAnnotation("bundle-absolute-paths", "group", []string{"Features"})
+	cmd.Flags().BoolVar(&bundleMinimal, "bundle-minimal", false, FlagBundleMinimal)
+	_ = cmd.Flags().SetAnnotation("bundle-minimal", "group", []string{"Features"})
+	cmd.Flags().StringArrayVar(&bundleVars, "bundle-var", []string{}, FlagBundleVar)
+	cmd.Flags().StringVar(&bundleVarDefault, "bundle-var-default", "", FlagBundleVarDefault)
+	_ = cmd.Flags().SetAnnotation("bundle-var", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("bundle-var-default", "group", []string{"Features"})
+	_ = cmd.Flags().SetAnnotation("version", "group", []string{"Misc"})
+	_ = cmd.Flags().SetAnnotation("help", "group", []string{"Misc"})
+}
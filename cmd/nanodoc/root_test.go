@@ -38,22 +38,9 @@ func executeCommand(args ...string) (string, error) {
 	// Reset all flag values to ensure clean state
 	rootCmd.ResetFlags()
 	// Re-initialize flags after reset
-	rootCmd.Flags().StringVarP(&lineNum, "linenum", "l", "", FlagLineNum)
-	rootCmd.Flags().BoolVar(&toc, "toc", false, FlagTOC)
-	rootCmd.Flags().StringVar(&theme, "theme", "classic", FlagTheme)
-	rootCmd.Flags().BoolVar(&showFilenames, "filenames", true, FlagFilenames)
-	rootCmd.Flags().StringVar(&filenameFormat, "header-format", "nice", FlagHeaderFormat)
-	rootCmd.Flags().StringVar(&filenameAlign, "header-align", "left", FlagHeaderAlign)
-	rootCmd.Flags().StringVar(&filenameBanner, "header-style", "none", FlagHeaderStyle)
-	rootCmd.Flags().IntVar(&pageWidth, "page-width", 80, FlagPageWidth)
-	rootCmd.Flags().StringVar(&fileNumbering, "file-numbering", "numerical", FlagFileNumbering)
-	rootCmd.Flags().StringSliceVar(&additionalExt, "ext", []string{}, FlagExt)
-	rootCmd.Flags().StringSliceVar(&includePatterns, "include", []string{}, FlagInclude)
-	rootCmd.Flags().StringSliceVar(&excludePatterns, "exclude", []string{}, FlagExclude)
-	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, FlagDryRun)
-	rootCmd.Flags().StringVar(&saveToBundlePath, "save-to-bundle", "", FlagSaveToBundle)
-	rootCmd.Flags().StringVar(&outputFormat, "output-format", "term", FlagOutputFormat)
-	rootCmd.Flags().BoolP("version", "v", false, FlagVersion)
+	setupFlags(rootCmd)
+	// Use a fixed page width regardless of the test terminal
+	pageWidth = 80
 	
 	// Use the actual root command
 	rootCmd.SetOut(&out)
@@ -102,6 +89,17 @@ func TestRootCmd(t *testing.T) {
 			wantOutput: []string{"Table of Contents", "file2.md", "- Title"},
 			wantErr:    false,
 		},
+		{
+			name:       "with file index",
+			args:       []string{"--file-index", file1, file2},
+			wantOutput: []string{"Files", "1. file1.txt", "2. file2.md"},
+			wantErr:    false,
+		},
+		{
+			name:    "invalid file index position",
+			args:    []string{"--file-index", "--file-index-position", "middle", file1},
+			wantErr: true,
+		},
 		{
 			name:          "without filenames",
 			args:          []string{"--filenames=false", file1},
@@ -147,11 +145,29 @@ func TestRootCmd(t *testing.T) {
 			wantOutput: []string{"a minimal document bundler"},
 			wantErr:    false,
 		},
+		{
+			name:           "check links",
+			args:           []string{"--check-links", file2},
+			wantOutput:     []string{"Checked 0 link(s), 0 broken"},
+			dontWantOutput: []string{"Title"},
+			wantErr:        false,
+		},
 		{
 			name:    "non-existent file",
 			args:    []string{"nonexistent.txt"},
 			wantErr: true,
 		},
+		{
+			name:    "missing file fails without keep going",
+			args:    []string{file1, "nonexistent.txt"},
+			wantErr: true,
+		},
+		{
+			name:       "keep going skips missing file",
+			args:       []string{"--keep-going", file1, "nonexistent.txt"},
+			wantOutput: []string{"hello", "world", "Skipped 1 source(s)", "nonexistent.txt: file not found"},
+			wantErr:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -246,6 +262,18 @@ func TestRootCmdBundleOptions(t *testing.T) {
 				"test.txt",           // No filename should be shown
 			},
 		},
+		{
+			name: "dump_options_shows_merged_theme",
+			args: []string{"--dump-options", "--theme", "classic-light", bundleFile},
+			wantOutput: []string{
+				"Theme=classic-light\n", // CLI overrides the bundle's classic-dark
+				"ShowTOC=true\n",        // --toc from bundle
+				"LineNumbers=file\n",    // --linenum file from bundle
+			},
+			dontWantOutput: []string{
+				"Theme=classic-dark",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -289,7 +317,78 @@ func resetFlags() {
 	includePatterns = []string{}
 	excludePatterns = []string{}
 	dryRun = false
+	showSkipped = false
 	saveToBundlePath = ""
 	outputFormat = "term"
+	outputFile = ""
+	appendOutput = false
+	fileIndex = false
+	fileIndexPosition = "before-toc"
+	mdCodeFences = false
+	mdCollapsible = false
+	mdNormalize = false
+	mdFrontMatter = []string{}
+	checkLinks = false
+	checkExternal = false
+	countOnly = false
+	countByExt = false
+	overflow = "none"
+	noDefaultExt = false
+	sections = []string{}
+	sectionOnly = false
+	highlightLines = ""
+	highlightLegend = ""
+	highlightGutter = false
+	footnotePaths = false
+	headerShowSize = false
+	headerShowRange = false
+	rtl = false
+	zebra = false
+	highlight = false
+	colorMode = "auto"
+	tocMaxEntries = 0
+	anchorEvery = 0
+	jsonContent = true
+	plainHeaders = false
+	squeezeBlanks = false
+	blankMarker = false
+	compact = false
+	reflow = false
+	wrapWidth = 0
+	strict = false
+	stripFrontMatter = false
+	tocLinks = false
+	headersOnly = false
+	lineNumScope = "all"
+	lineNumMinLines = 0
+	headerChar = ""
+	fileNumberReset = "none"
+	seqStart = 1
+	resolveOnly = false
+	cacheDir = ""
+	uniqueBy = "none"
+	gitDiff = ""
+	gitTracked = false
+	onDuplicate = "keep"
+	dedupeLines = false
+	dedupeLinesScope = "document"
+	keepGoing = false
+	maxFileSize = ""
+	onOversize = "skip"
+	bundleAbsPaths = false
+	bundleMinimal = false
+	dumpOptions = false
+	verbose = false
+	globBase = ""
+	processIncludesIn = []string{}
+	emptyDocMessage = ""
+	bundleAsSection = false
+	sortBundle = false
+	commentSections = false
+	renderMarkdown = false
+	renderMdTables = false
+	emptyNoNumber = false
+	bundleVars = []string{}
+	bundleVarDefault = ""
 	explicitFlags = make(map[string]bool)
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -231,3 +232,102 @@ func TestSaveToBundleWithAllFlags(t *testing.T) {
 		t.Errorf("output from bundle does not match original output.\nOriginal:\n%s\n\nBundle:\n%s", originalOutput, bundleOutput)
 	}
 }
+
+func TestSaveToBundleAbsolutePaths(t *testing.T) {
+	tempDir := t.TempDir()
+	bundlePath := filepath.Join(tempDir, "portable.bundle.txt")
+
+	oldDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldDir) }()
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	if err := os.Mkdir("docs", 0755); err != nil {
+		t.Fatalf("Failed to create docs directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("docs", "intro.txt"), []byte("line 1\nline 2"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	_, err := executeCommand("docs/intro.txt:L1", "--bundle-absolute-paths", "--save-to-bundle", bundlePath)
+	if err != nil {
+		t.Fatalf("unexpected error when saving bundle: %v", err)
+	}
+
+	content, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatalf("failed to read bundle file: %v", err)
+	}
+
+	// Resolve symlinks in the temp dir (e.g. /var -> /private/var on macOS)
+	cwd, _ := os.Getwd()
+	expected := filepath.Join(cwd, "docs", "intro.txt") + ":L1\n"
+	contentStr := string(content)
+	if !strings.Contains(contentStr, "# --- Content ---\n"+expected) {
+		t.Errorf("bundle file missing absolute path %q\n\nGot:\n%s", expected, contentStr)
+	}
+	if !strings.Contains(contentStr, "docs/intro.txt:L1\n\n# --- Options ---") {
+		t.Errorf("command comment should keep the args as typed\n\nGot:\n%s", contentStr)
+	}
+}
+
+func TestSaveToBundleMinimal(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("Test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		contains   []string
+		notContain []string
+	}{
+		{
+			name:       "default theme omitted",
+			args:       []string{"--bundle-minimal"},
+			notContain: []string{"--theme=", "--header-format=", "--header-align=", "--header-style=", "--page-width=", "--file-numbering="},
+		},
+		{
+			name:       "non-default values kept",
+			args:       []string{"--bundle-minimal", "--theme=classic-dark", "--file-numbering=roman"},
+			contains:   []string{"--theme=classic-dark\n", "--file-numbering=roman\n"},
+			notContain: []string{"--header-format=", "--page-width="},
+		},
+		{
+			name:     "defaults written without minimal",
+			contains: []string{"--theme=classic\n", "--header-format=nice\n", "--file-numbering=numerical\n"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundlePath := filepath.Join(tempDir, fmt.Sprintf("minimal%d.bundle.txt", i))
+			args := append([]string{testFile}, tt.args...)
+			args = append(args, "--save-to-bundle", bundlePath)
+
+			if _, err := executeCommand(args...); err != nil {
+				t.Fatalf("unexpected error when saving bundle: %v", err)
+			}
+
+			content, err := os.ReadFile(bundlePath)
+			if err != nil {
+				t.Fatalf("failed to read bundle file: %v", err)
+			}
+			_, options, _ := strings.Cut(string(content), "# --- Options ---\n")
+
+			for _, want := range tt.contains {
+				if !strings.Contains(options, want) {
+					t.Errorf("bundle options missing %q\n\nGot:\n%s", want, options)
+				}
+			}
+			for _, unwanted := range tt.notContain {
+				if strings.Contains(options, unwanted) {
+					t.Errorf("bundle options should not contain %q\n\nGot:\n%s", unwanted, options)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStdinArgument(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd.SetIn(strings.NewReader("piped one\npiped two\n"))
+	defer rootCmd.SetIn(nil)
+
+	output, err := executeCommand("--header-format", "filename", "--", filepath.Join(tempDir, "file1.txt"), "-", "-:L2")
+	if err != nil {
+		t.Fatalf("executeCommand() error = %v\nOutput:\n%s", err, output)
+	}
+
+	for _, want := range []string{"1. file1.txt", "hello\nworld", "2. <stdin>", "piped one\npiped two\npiped two"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthur-debert/nanodoc/pkg/nanodoc"
+	"github.com/spf13/cobra"
+)
+
+// stylesPageWidth is the width the style samples are drawn for
+var stylesPageWidth int
+
+var stylesCmd = &cobra.Command{
+	Use:   "styles",
+	Short: StylesShort,
+	Long:  StylesLong,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listStyles(cmd, stylesPageWidth)
+		return nil
+	},
+}
+
+func init() {
+	stylesCmd.Flags().IntVar(&stylesPageWidth, "page-width", nanodoc.GetTerminalWidth(), FlagPageWidth)
+	rootCmd.AddCommand(stylesCmd)
+}
+
+// The sample header drawn with each style, and its indent
+const (
+	styleSample       = "1. example.txt"
+	styleSampleIndent = "    "
+)
+
+// listStyles prints each header style with its description and a sample
+// header drawn with it. Samples are drawn narrower by their indent, so they
+// fit in width.
+func listStyles(cmd *cobra.Command, width int) {
+	out := cmd.OutOrStdout()
+	descriptions := nanodoc.GetBannerStyleDescriptions()
+	opts := &nanodoc.FormattingOptions{
+		PageWidth:       width - len(styleSampleIndent),
+		HeaderAlignment: "left",
+	}
+
+	_, _ = fmt.Fprintln(out, AvailableStyles)
+	_, _ = fmt.Fprintln(out)
+	for _, name := range nanodoc.GetBannerStyleNames() {
+		style, _ := nanodoc.GetBannerStyle(name)
+		_, _ = fmt.Fprintf(out, "  %s: %s\n\n", name, descriptions[name])
+		for _, line := range strings.Split(style.Apply(styleSample, opts), "\n") {
+			_, _ = fmt.Fprintln(out, strings.TrimRight(styleSampleIndent+line, " "))
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	_, _ = fmt.Fprintln(out, RunStyleHelp)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanodoc/pkg/nanodoc"
+)
+
+func TestStylesCommand(t *testing.T) {
+	output, err := executeCommand("styles", "--page-width", "40")
+	if err != nil {
+		t.Fatalf("styles command failed: %v", err)
+	}
+
+	for _, name := range []string{"none", "dashed", "solid", "boxed"} {
+		if !strings.Contains(output, "  "+name+": ") {
+			t.Errorf("Expected style %q in output, got:\n%s", name, output)
+		}
+	}
+	for name, description := range nanodoc.GetBannerStyleDescriptions() {
+		if !strings.Contains(output, "  "+name+": "+description+"\n") {
+			t.Errorf("Expected style %q with its description in output, got:\n%s", name, output)
+		}
+	}
+
+	// Samples fit in the page width, indent included
+	boxLine := styleSampleIndent + strings.Repeat("#", 36) + "\n"
+	if !strings.Contains(output, boxLine) {
+		t.Errorf("Expected a boxed sample 40 columns wide, got:\n%s", output)
+	}
+	if !strings.Contains(output, styleSampleIndent+"1. example.txt:\n") {
+		t.Errorf("Expected an inline sample, got:\n%s", output)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// stageTimings records how long the main pipeline stages took, for --verbose
+type stageTimings struct {
+	resolve time.Duration
+	extract time.Duration
+	render  time.Duration
+}
+
+// since returns the time elapsed since start and resets start to now, so
+// consecutive stages can be timed with one clock
+func since(start *time.Time) time.Duration {
+	now := time.Now()
+	elapsed := now.Sub(*start)
+	*start = now
+	return elapsed
+}
+
+// String formats the timings as the line --verbose prints to stderr
+func (t stageTimings) String() string {
+	return fmt.Sprintf("timings: resolve=%s extract=%s render=%s",
+		t.resolve.Round(time.Microsecond), t.extract.Round(time.Microsecond), t.render.Round(time.Microsecond))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// executeCommandSplit runs the root command like executeCommand, but keeps
+// stdout and stderr apart
+func executeCommandSplit(args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	resetFlags()
+	rootCmd.ResetFlags()
+	setupFlags(rootCmd)
+	pageWidth = 80
+
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestVerboseTimings(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+	file1 := filepath.Join(tempDir, "file1.txt")
+
+	timingLine := regexp.MustCompile(`(?m)^timings: resolve=\S+ extract=\S+ render=\S+$`)
+
+	quietOut, quietErr, err := executeCommandSplit(file1)
+	if err != nil {
+		t.Fatalf("executeCommand() error = %v", err)
+	}
+	if strings.Contains(quietErr, "timings:") {
+		t.Errorf("Expected no timings without --verbose, got stderr:\n%s", quietErr)
+	}
+
+	stdout, stderr, err := executeCommandSplit("--verbose", file1)
+	if err != nil {
+		t.Fatalf("executeCommand() error = %v", err)
+	}
+	if !timingLine.MatchString(stderr) {
+		t.Errorf("Expected a timings line on stderr, got:\n%s", stderr)
+	}
+	if stdout != quietOut {
+		t.Errorf("--verbose changed stdout:\n%q\nwant:\n%q", stdout, quietOut)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	markdown "github.com/teekennedy/goldmark-markdown"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 )
@@ -37,6 +38,18 @@ func NewParser() *Parser {
 	}
 }
 
+// NewTableParser creates a markdown parser that also recognizes GFM tables
+func NewTableParser() *Parser {
+	return &Parser{
+		gm: goldmark.New(
+			goldmark.WithExtensions(extension.Table),
+			goldmark.WithParserOptions(
+				parser.WithAutoHeadingID(),
+			),
+		),
+	}
+}
+
 // Parse converts markdown content into a Document
 func (p *Parser) Parse(content []byte) (*Document, error) {
 	reader := text.NewReader(content)
@@ -91,6 +104,48 @@ func (t *Transformer) HasH1(doc *Document) bool {
 	return hasH1
 }
 
+// NormalizeHeaderLevels shifts the headers so the shallowest one is at level
+// top, keeping their relative depth. With single set, only the first header
+// at that level stays there; the others move one level down. It reports
+// whether the document has any header.
+func (t *Transformer) NormalizeHeaderLevels(doc *Document, top int, single bool) bool {
+	var headings []*ast.Heading
+	_ = ast.Walk(doc.AST, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			if heading, ok := n.(*ast.Heading); ok {
+				headings = append(headings, heading)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(headings) == 0 {
+		return false
+	}
+
+	shallowest := 6
+	for _, heading := range headings {
+		if heading.Level < shallowest {
+			shallowest = heading.Level
+		}
+	}
+
+	seenTop := false
+	for _, heading := range headings {
+		level := heading.Level + top - shallowest
+		if level == top && single {
+			if seenTop {
+				level++
+			}
+			seenTop = true
+		}
+		if level > 6 {
+			level = 6 // Max header level in markdown
+		}
+		heading.Level = level
+	}
+	return true
+}
+
 // InsertFileHeader adds a header at the beginning of the document
 func (t *Transformer) InsertFileHeader(doc *Document, headerText string, level int) error {
 	// Create new header node
@@ -107,6 +162,80 @@ func (t *Transformer) InsertFileHeader(doc *Document, headerText string, level i
 	return nil
 }
 
+// Link represents a link or image found in a markdown document
+type Link struct {
+	Destination string
+	Text        string
+	IsImage     bool
+}
+
+// CollectLinks returns all links and images in the document, in source order
+func (t *Transformer) CollectLinks(doc *Document) []Link {
+	var links []Link
+
+	_ = ast.Walk(doc.AST, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			switch node := n.(type) {
+			case *ast.Link:
+				links = append(links, Link{
+					Destination: string(node.Destination),
+					Text:        extractNodeText(node, doc.Source),
+				})
+			case *ast.Image:
+				links = append(links, Link{
+					Destination: string(node.Destination),
+					Text:        extractNodeText(node, doc.Source),
+					IsImage:     true,
+				})
+			case *ast.AutoLink:
+				links = append(links, Link{
+					Destination: string(node.URL(doc.Source)),
+					Text:        string(node.Label(doc.Source)),
+				})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return links
+}
+
+// ExtractSection returns the source of the section started by the top-level
+// heading whose text matches title, ignoring case, up to the next heading of
+// the same or a higher level. It reports false when no heading matches.
+func (t *Transformer) ExtractSection(doc *Document, title string) (string, bool) {
+	title = strings.TrimSpace(title)
+	start, level := -1, 0
+
+	for n := doc.AST.FirstChild(); n != nil; n = n.NextSibling() {
+		heading, ok := n.(*ast.Heading)
+		if !ok || heading.Lines().Len() == 0 {
+			continue
+		}
+		offset := lineStart(doc.Source, heading.Lines().At(0).Start)
+
+		if start >= 0 {
+			if heading.Level <= level {
+				return strings.TrimRight(string(doc.Source[start:offset]), "\n"), true
+			}
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(extractNodeText(heading, doc.Source)), title) {
+			start, level = offset, heading.Level
+		}
+	}
+
+	if start < 0 {
+		return "", false
+	}
+	return strings.TrimRight(string(doc.Source[start:]), "\n"), true
+}
+
+// lineStart returns the offset where the line containing offset begins
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
+
 // Renderer converts markdown AST back to markdown text
 type Renderer struct {
 	gm goldmark.Markdown
@@ -148,15 +277,18 @@ type TOCEntry struct {
 	ID    string
 }
 
-// ExtractTOC extracts all headers from the document for TOC generation
+// ExtractTOC extracts all headers from the document for TOC generation.
+// Repeated headings get GitHub-style numeric suffixes ("notes", "notes-1")
+// so every ID is a distinct anchor.
 func (tg *TOCGenerator) ExtractTOC(doc *Document) []TOCEntry {
 	var entries []TOCEntry
+	usedIDs := make(map[string]bool)
 	
 	_ = ast.Walk(doc.AST, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if entering {
 			if heading, ok := n.(*ast.Heading); ok {
 				text := tg.extractHeadingText(heading, doc.Source)
-				id := tg.generateAnchorID(text)
+				id := uniqueAnchorID(tg.generateAnchorID(text), usedIDs)
 				
 				entries = append(entries, TOCEntry{
 					Level: heading.Level,
@@ -191,9 +323,14 @@ func (tg *TOCGenerator) GenerateTOCMarkdown(entries []TOCEntry) string {
 
 // extractHeadingText extracts the text content from a heading node
 func (tg *TOCGenerator) extractHeadingText(heading *ast.Heading, source []byte) string {
+	return extractNodeText(heading, source)
+}
+
+// extractNodeText concatenates the text content of a node and its children
+func extractNodeText(node ast.Node, source []byte) string {
 	var text strings.Builder
 	
-	_ = ast.Walk(heading, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if entering {
 			switch node := n.(type) {
 			case *ast.Text:
@@ -227,6 +364,17 @@ func (tg *TOCGenerator) generateAnchorID(text string) string {
 	return cleaned.String()
 }
 
+// uniqueAnchorID returns id, or id with the first free "-N" suffix when it
+// is already used, and marks the result as used
+func uniqueAnchorID(id string, used map[string]bool) string {
+	unique := id
+	for n := 1; used[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", id, n)
+	}
+	used[unique] = true
+	return unique
+}
+
 // HeaderFormatter formats file headers according to nanodoc options
 type HeaderFormatter struct{}
 
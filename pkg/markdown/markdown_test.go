@@ -76,6 +76,12 @@ func TestTransformer_AdjustHeaderLevels(t *testing.T) {
 			increment: 2,
 			want:      "###### H5\n\n###### H6",
 		},
+		{
+			name:      "fenced code untouched",
+			content:   "# H1\n\n```bash\n# not a heading\n## nor this\n```\n\n~~~\n# tilde fence\n~~~\n\n## H2",
+			increment: 1,
+			want:      "## H1\n\n```bash\n# not a heading\n## nor this\n```\n\n```\n# tilde fence\n```\n\n### H2",
+		},
 	}
 
 	parser := NewParser()
@@ -135,6 +141,11 @@ func TestTransformer_HasH1(t *testing.T) {
 			content: "",
 			want:    false,
 		},
+		{
+			name:    "H1 only inside fenced code",
+			content: "## Subtitle\n\n```\n# comment\n```",
+			want:    false,
+		},
 	}
 
 	parser := NewParser()
@@ -155,7 +166,138 @@ func TestTransformer_HasH1(t *testing.T) {
 	}
 }
 
+func TestTransformer_NormalizeHeaderLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		top     int
+		single  bool
+		want    string
+		found   bool
+	}{
+		{
+			name:    "promote to H1",
+			content: "## Setup\n\n### Details",
+			top:     1,
+			single:  true,
+			want:    "# Setup\n\n## Details",
+			found:   true,
+		},
+		{
+			name:    "single keeps one heading at top",
+			content: "# Usage\n\n## Flags\n\n# Other",
+			top:     1,
+			single:  true,
+			want:    "# Usage\n\n## Flags\n\n## Other",
+			found:   true,
+		},
+		{
+			name:    "demote to H2",
+			content: "# Usage\n\n## Flags\n\n# Other",
+			top:     2,
+			want:    "## Usage\n\n### Flags\n\n## Other",
+			found:   true,
+		},
+		{
+			name:    "max level cap",
+			content: "# Top\n\n###### Deep",
+			top:     2,
+			want:    "## Top\n\n###### Deep",
+			found:   true,
+		},
+		{
+			name:    "no headers",
+			content: "Just text",
+			top:     1,
+			single:  true,
+			want:    "Just text",
+			found:   false,
+		},
+	}
+
+	parser := NewParser()
+	transformer := NewTransformer()
+	renderer := NewRenderer()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if found := transformer.NormalizeHeaderLevels(doc, tt.top, tt.single); found != tt.found {
+				t.Errorf("NormalizeHeaderLevels() = %v, want %v", found, tt.found)
+			}
+
+			result, err := renderer.Render(doc)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			got := normalizeNewlines(string(result))
+			want := normalizeNewlines(tt.want)
+			if got != want {
+				t.Errorf("NormalizeHeaderLevels() got = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
 // Test file header insertion
+func TestTransformer_ExtractSection(t *testing.T) {
+	content := "# Guide\n\nintro\n\n## Install\n\nrun it\n\n### From source\n\nbuild it\n\n## Usage\n\nuse it\n"
+	tests := []struct {
+		name   string
+		title  string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "section with subsections",
+			title:  "Install",
+			want:   "## Install\n\nrun it\n\n### From source\n\nbuild it",
+			wantOK: true,
+		},
+		{
+			name:   "last section",
+			title:  "usage",
+			want:   "## Usage\n\nuse it",
+			wantOK: true,
+		},
+		{
+			name:   "top level heading spans the file",
+			title:  " Guide ",
+			want:   strings.TrimRight(content, "\n"),
+			wantOK: true,
+		},
+		{
+			name:   "missing section",
+			title:  "Missing",
+			wantOK: false,
+		},
+	}
+
+	parser := NewParser()
+	transformer := NewTransformer()
+	doc, err := parser.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := transformer.ExtractSection(doc, tt.title)
+			if ok != tt.wantOK {
+				t.Fatalf("ExtractSection() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractSection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTransformer_InsertFileHeader(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -218,6 +360,47 @@ func TestTransformer_InsertFileHeader(t *testing.T) {
 }
 
 // Test TOC extraction
+// Test Transformer link collection
+func TestTransformer_CollectLinks(t *testing.T) {
+	content := "# Guide\n\n" +
+		"Read the [setup *notes*](setup.md#install) first.\n\n" +
+		"![Architecture](img/arch.png)\n\n" +
+		"- [Home](https://example.com)\n" +
+		"- <https://go.dev>\n"
+
+	parser := NewParser()
+	transformer := NewTransformer()
+
+	doc, err := parser.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	want := []Link{
+		{Destination: "setup.md#install", Text: "setup notes"},
+		{Destination: "img/arch.png", Text: "Architecture", IsImage: true},
+		{Destination: "https://example.com", Text: "Home"},
+		{Destination: "https://go.dev", Text: "https://go.dev"},
+	}
+
+	got := transformer.CollectLinks(doc)
+	if len(got) != len(want) {
+		t.Fatalf("CollectLinks() returned %d links, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Link %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTransformer_CollectLinksEmpty(t *testing.T) {
+	doc, _ := NewParser().Parse([]byte("# No links here\n\nJust text."))
+	if links := NewTransformer().CollectLinks(doc); len(links) != 0 {
+		t.Errorf("Expected no links, got %+v", links)
+	}
+}
+
 func TestTOCGenerator_ExtractTOC(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -258,6 +441,32 @@ func TestTOCGenerator_ExtractTOC(t *testing.T) {
 				{Level: 3, Text: "Code & Testing", ID: "code--testing"},
 			},
 		},
+		{
+			name: "repeated headings",
+			content: `# Guide
+
+## Notes
+
+## Notes 1
+
+## Notes`,
+			want: []TOCEntry{
+				{Level: 1, Text: "Guide", ID: "guide"},
+				{Level: 2, Text: "Notes", ID: "notes"},
+				{Level: 2, Text: "Notes 1", ID: "notes-1"},
+				{Level: 2, Text: "Notes", ID: "notes-2"},
+			},
+		},
+		{
+			name: "two notes headings",
+			content: `## Notes
+
+## Notes`,
+			want: []TOCEntry{
+				{Level: 2, Text: "Notes", ID: "notes"},
+				{Level: 2, Text: "Notes", ID: "notes-1"},
+			},
+		},
 	}
 
 	parser := NewParser()
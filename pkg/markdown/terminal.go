@@ -0,0 +1,324 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+)
+
+// ANSI SGR sequences used by the terminal renderer. Each style is turned off
+// with its own sequence so nested styles survive the end of an inner one.
+const (
+	sgrBold         = "\x1b[1m"
+	sgrBoldOff      = "\x1b[22m"
+	sgrItalic       = "\x1b[3m"
+	sgrItalicOff    = "\x1b[23m"
+	sgrUnderline    = "\x1b[4m"
+	sgrUnderlineOff = "\x1b[24m"
+	sgrCyan         = "\x1b[36m"
+	sgrColorOff     = "\x1b[39m"
+)
+
+// sgrPattern matches the SGR sequences the terminal renderer emits
+var sgrPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// TerminalRenderer renders a markdown AST as styled text for terminals
+type TerminalRenderer struct {
+	// Width returns the number of terminal cells text occupies, used to size
+	// table columns. When nil, each rune outside escape sequences is one cell.
+	Width func(text string) int
+
+	// ReflowWidth, when positive, rejoins the lines of top-level paragraphs
+	// and wraps them at word boundaries to this many cells. Hard line breaks
+	// are kept; code blocks, lists and blockquotes keep their lines.
+	ReflowWidth int
+
+	// Plain leaves out the escape sequences, keeping only the layout
+	Plain bool
+
+	// softBreak is written for soft line breaks while rendering inlines
+	softBreak string
+}
+
+// NewTerminalRenderer creates a new terminal renderer
+func NewTerminalRenderer() *TerminalRenderer {
+	return &TerminalRenderer{}
+}
+
+// Render converts a Document to text styled with ANSI escape sequences:
+// headings and strong emphasis in bold, emphasis in italics, code spans in
+// color and links underlined. Markdown markers are removed.
+func (tr *TerminalRenderer) Render(doc *Document) string {
+	var b strings.Builder
+	tr.renderBlocks(&b, doc.AST, doc.Source)
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// renderBlocks renders the block-level children of a node, separated by blank lines
+func (tr *TerminalRenderer) renderBlocks(b *strings.Builder, parent ast.Node, source []byte) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		tr.renderBlock(b, n, source)
+	}
+}
+
+func (tr *TerminalRenderer) renderBlock(b *strings.Builder, n ast.Node, source []byte) {
+	switch node := n.(type) {
+	case *ast.Heading:
+		text := tr.renderInlines(node, source)
+		if node.Level == 1 {
+			b.WriteString(tr.style(sgrBold+sgrUnderline, text, sgrUnderlineOff+sgrBoldOff))
+		} else {
+			b.WriteString(tr.style(sgrBold, text, sgrBoldOff))
+		}
+		b.WriteString("\n\n")
+
+	case *ast.Paragraph:
+		if _, topLevel := node.Parent().(*ast.Document); topLevel && tr.ReflowWidth > 0 {
+			tr.softBreak = " "
+			text := tr.renderInlines(node, source)
+			tr.softBreak = ""
+			b.WriteString(tr.wrapWords(text, tr.ReflowWidth))
+		} else {
+			b.WriteString(tr.renderInlines(node, source))
+		}
+		b.WriteString("\n\n")
+
+	case *ast.TextBlock:
+		// Text of a tight list item
+		b.WriteString(tr.renderInlines(node, source))
+		b.WriteString("\n")
+
+	case *ast.List:
+		number := node.Start
+		for item := node.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "• "
+			if node.IsOrdered() {
+				marker = fmt.Sprintf("%d. ", number)
+				number++
+			}
+
+			var itemText strings.Builder
+			tr.renderBlocks(&itemText, item, source)
+			b.WriteString(prefixLines(strings.TrimRight(itemText.String(), "\n"), marker, strings.Repeat(" ", len(marker))))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+
+	case *ast.Blockquote:
+		var quoted strings.Builder
+		tr.renderBlocks(&quoted, node, source)
+		b.WriteString(prefixLines(strings.TrimRight(quoted.String(), "\n"), "│ ", "│ "))
+		b.WriteString("\n\n")
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		lines := node.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			line := lines.At(i)
+			b.WriteString("    ")
+			b.Write(line.Value(source))
+		}
+		b.WriteString("\n")
+
+	case *ast.HTMLBlock:
+		lines := node.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			line := lines.At(i)
+			b.Write(line.Value(source))
+		}
+		b.WriteString("\n")
+
+	case *extast.Table:
+		b.WriteString(tr.renderTable(node, source))
+		b.WriteString("\n\n")
+
+	case *ast.ThematicBreak:
+		b.WriteString(strings.Repeat("─", 40))
+		b.WriteString("\n\n")
+
+	default:
+		tr.renderBlocks(b, n, source)
+	}
+}
+
+// style wraps text in the on and off sequences, unless the renderer is plain
+func (tr *TerminalRenderer) style(on, text, off string) string {
+	if tr.Plain {
+		return text
+	}
+	return on + text + off
+}
+
+// renderInlines renders the inline children of a block node
+func (tr *TerminalRenderer) renderInlines(parent ast.Node, source []byte) string {
+	var b strings.Builder
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		tr.renderInline(&b, n, source)
+	}
+	return b.String()
+}
+
+func (tr *TerminalRenderer) renderInline(b *strings.Builder, n ast.Node, source []byte) {
+	switch node := n.(type) {
+	case *ast.Text:
+		b.Write(node.Segment.Value(source))
+		if node.SoftLineBreak() && tr.softBreak != "" {
+			b.WriteString(tr.softBreak)
+		} else if node.SoftLineBreak() || node.HardLineBreak() {
+			b.WriteString("\n")
+		}
+
+	case *ast.String:
+		b.Write(node.Value)
+
+	case *ast.Emphasis:
+		if node.Level >= 2 {
+			b.WriteString(tr.style(sgrBold, tr.renderInlines(node, source), sgrBoldOff))
+		} else {
+			b.WriteString(tr.style(sgrItalic, tr.renderInlines(node, source), sgrItalicOff))
+		}
+
+	case *ast.CodeSpan:
+		b.WriteString(tr.style(sgrCyan, tr.renderInlines(node, source), sgrColorOff))
+
+	case *ast.Link:
+		text := tr.renderInlines(node, source)
+		b.WriteString(tr.style(sgrUnderline, text, sgrUnderlineOff))
+		if destination := string(node.Destination); destination != text {
+			b.WriteString(" (" + destination + ")")
+		}
+
+	case *ast.AutoLink:
+		b.WriteString(tr.style(sgrUnderline, string(node.URL(source)), sgrUnderlineOff))
+
+	case *ast.Image:
+		b.WriteString("[image: " + extractNodeText(node, source) + "]")
+
+	case *ast.RawHTML:
+		for i := 0; i < node.Segments.Len(); i++ {
+			segment := node.Segments.At(i)
+			b.Write(segment.Value(source))
+		}
+
+	default:
+		b.WriteString(tr.renderInlines(n, source))
+	}
+}
+
+// renderTable draws a GFM table as a box sized to its content, with the
+// header row in bold and each column aligned as its delimiter row asks
+func (tr *TerminalRenderer) renderTable(table *extast.Table, source []byte) string {
+	var rows [][]string
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			text := strings.TrimSpace(tr.renderInlines(cell, source))
+			if _, isHeader := row.(*extast.TableHeader); isHeader && text != "" {
+				text = tr.style(sgrBold, text, sgrBoldOff)
+			}
+			cells = append(cells, text)
+		}
+		rows = append(rows, cells)
+	}
+
+	widths := make([]int, len(table.Alignments))
+	for _, cells := range rows {
+		for i, cell := range cells {
+			if i < len(widths) {
+				widths[i] = max(widths[i], tr.width(cell))
+			}
+		}
+	}
+
+	rule := func(left, middle, right string) string {
+		segments := make([]string, len(widths))
+		for i, width := range widths {
+			segments[i] = strings.Repeat("─", width+2)
+		}
+		return left + strings.Join(segments, middle) + right
+	}
+
+	lines := []string{rule("┌", "┬", "┐")}
+	for r, cells := range rows {
+		segments := make([]string, len(widths))
+		for i, width := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			segments[i] = " " + tr.alignCell(cell, width, table.Alignments[i]) + " "
+		}
+		lines = append(lines, "│"+strings.Join(segments, "│")+"│")
+		if r == 0 {
+			lines = append(lines, rule("├", "┼", "┤"))
+		}
+	}
+	lines = append(lines, rule("└", "┴", "┘"))
+	return strings.Join(lines, "\n")
+}
+
+// alignCell pads text to width cells according to alignment
+func (tr *TerminalRenderer) alignCell(text string, width int, alignment extast.Alignment) string {
+	padding := width - tr.width(text)
+	if padding <= 0 {
+		return text
+	}
+	switch alignment {
+	case extast.AlignRight:
+		return strings.Repeat(" ", padding) + text
+	case extast.AlignCenter:
+		left := padding / 2
+		return strings.Repeat(" ", left) + text + strings.Repeat(" ", padding-left)
+	default:
+		return text + strings.Repeat(" ", padding)
+	}
+}
+
+// width returns the number of terminal cells text occupies
+func (tr *TerminalRenderer) width(text string) int {
+	if tr.Width != nil {
+		return tr.Width(text)
+	}
+	return utf8.RuneCountInString(sgrPattern.ReplaceAllString(text, ""))
+}
+
+// wrapWords wraps each line of text at spaces so it fits in width cells.
+// Words wider than width get a line of their own.
+func (tr *TerminalRenderer) wrapWords(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		var wrapped []string
+		current, currentWidth := "", 0
+		for _, word := range strings.Fields(line) {
+			wordWidth := tr.width(word)
+			if current != "" && currentWidth+1+wordWidth > width {
+				wrapped = append(wrapped, current)
+				current, currentWidth = "", 0
+			}
+			if current != "" {
+				current += " "
+				currentWidth++
+			}
+			current += word
+			currentWidth += wordWidth
+		}
+		lines[i] = strings.Join(append(wrapped, current), "\n")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// prefixLines prefixes the first line of text with first and the others with rest
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = first + line
+		} else if line != "" {
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
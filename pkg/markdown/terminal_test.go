@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerminalRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "bold",
+			content: "Some **bold** text",
+			want:    []string{"Some \x1b[1mbold\x1b[22m text"},
+			notWant: []string{"**"},
+		},
+		{
+			name:    "italic",
+			content: "Some *italic* text",
+			want:    []string{"Some \x1b[3mitalic\x1b[23m text"},
+		},
+		{
+			name:    "headings",
+			content: "# Title\n\n## Section",
+			want:    []string{"\x1b[1m\x1b[4mTitle\x1b[24m\x1b[22m", "\x1b[1mSection\x1b[22m"},
+			notWant: []string{"#"},
+		},
+		{
+			name:    "code span and link",
+			content: "Run `go test` and see [docs](https://go.dev).",
+			want:    []string{"\x1b[36mgo test\x1b[39m", "\x1b[4mdocs\x1b[24m (https://go.dev)"},
+			notWant: []string{"`", "]("},
+		},
+		{
+			name:    "lists",
+			content: "- one\n- two\n\n1. first\n2. second",
+			want:    []string{"• one\n• two", "1. first\n2. second"},
+		},
+		{
+			name:    "code block",
+			content: "```go\nfunc main() {}\n```",
+			want:    []string{"    func main() {}"},
+			notWant: []string{"```"},
+		},
+		{
+			name:    "blockquote",
+			content: "> quoted *text*",
+			want:    []string{"│ quoted \x1b[3mtext\x1b[23m"},
+		},
+	}
+
+	parser := NewParser()
+	renderer := NewTerminalRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got := renderer.Render(doc)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want it to contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Render() = %q, should not contain %q", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestTerminalRenderer_Table(t *testing.T) {
+	content := "| Name | Count |\n|:-----|------:|\n| a | 1 |\n| bcd | 10 |\n\nAfter the table."
+	doc, err := NewTableParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := sgrPattern.ReplaceAllString(NewTerminalRenderer().Render(doc), "")
+	want := "┌──────┬───────┐\n" +
+		"│ Name │ Count │\n" +
+		"├──────┼───────┤\n" +
+		"│ a    │     1 │\n" +
+		"│ bcd  │    10 │\n" +
+		"└──────┴───────┘\n" +
+		"\n" +
+		"After the table.\n"
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without the table extension the source is kept as text
+	doc, err = NewParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := NewTerminalRenderer().Render(doc); !strings.Contains(got, "| a | 1 |") {
+		t.Errorf("Expected the table source without the extension, got:\n%s", got)
+	}
+}
+
+func TestTerminalRenderer_TableWidth(t *testing.T) {
+	doc, err := NewTableParser().Parse([]byte("| k | v |\n|---|---|\n| 名前 | x |"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// A width function that counts wide characters as two cells
+	renderer := NewTerminalRenderer()
+	renderer.Width = func(text string) int {
+		width := 0
+		for _, r := range sgrPattern.ReplaceAllString(text, "") {
+			if r >= 0x4E00 && r <= 0x9FFF {
+				width += 2
+			} else {
+				width++
+			}
+		}
+		return width
+	}
+
+	got := sgrPattern.ReplaceAllString(renderer.Render(doc), "")
+	if !strings.Contains(got, "│ k    │ v │\n") || !strings.Contains(got, "│ 名前 │ x │\n") {
+		t.Errorf("Expected columns sized in cells, got:\n%s", got)
+	}
+}
+
+func TestTerminalRenderer_Reflow(t *testing.T) {
+	source := strings.Join([]string{
+		"Nanodoc bundles plain text",
+		"files into one document,",
+		"with headers and line numbers.",
+		"",
+		"- a list item",
+		"  stays as written",
+		"",
+		"> a quote",
+		"> stays too",
+		"",
+		"    code  keeps",
+		"    its lines",
+	}, "\n")
+	doc, err := NewParser().Parse([]byte(source))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	renderer := NewTerminalRenderer()
+	renderer.ReflowWidth = 50
+	got := renderer.Render(doc)
+
+	want := "Nanodoc bundles plain text files into one\ndocument, with headers and line numbers.\n\n" +
+		"• a list item\n    stays as written\n\n" +
+		"│ a quote\n│ stays too\n\n" +
+		"    code  keeps\n    its lines\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package nanodoc
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -16,6 +17,16 @@ type BannerStyle interface {
 	Description() string
 }
 
+// GlyphBannerStyle is implemented by banner styles whose line character can
+// be replaced, e.g. by the active theme
+type GlyphBannerStyle interface {
+	BannerStyle
+	// DefaultGlyph returns the character used when nothing overrides it
+	DefaultGlyph() string
+	// ApplyGlyph formats the filename drawing its lines with glyph
+	ApplyGlyph(filename, glyph string, opts *FormattingOptions) string
+}
+
 // BannerRegistry manages banner style implementations
 type BannerRegistry struct {
 	mu     sync.RWMutex
@@ -59,6 +70,7 @@ func (r *BannerRegistry) List() []string {
 	for name := range r.styles {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
@@ -94,9 +106,10 @@ func GetBannerStyleDescriptions() map[string]string {
 	return globalBannerRegistry.GetDescriptions()
 }
 
-// applyAlignment applies text alignment within the given width
+// applyAlignment applies text alignment within the given width, measured in
+// terminal cells so non-Latin text and bidi marks line up
 func applyAlignment(text, alignment string, width int) string {
-	textLen := len(text)
+	textLen := displayWidth(text)
 	if textLen >= width {
 		return text
 	}
@@ -115,6 +128,41 @@ func applyAlignment(text, alignment string, width int) string {
 	}
 }
 
+// applyBannerStyle renders headerText with the configured banner style,
+// drawing its lines with the theme's glyph and color when the theme sets them
+func applyBannerStyle(headerText string, opts *FormattingOptions, theme *Theme) string {
+	style, exists := GetBannerStyle(opts.HeaderStyle)
+	if !exists {
+		// Fallback to none style if not found
+		style, _ = GetBannerStyle("none")
+	}
+
+	// --header-char takes precedence over the theme's glyph
+	banner := style.Apply(headerText, opts)
+	if glyphStyle, ok := style.(GlyphBannerStyle); ok && opts.HeaderChar == "" {
+		if glyph := theme.BannerGlyph(style.Name()); glyph != "" {
+			banner = glyphStyle.ApplyGlyph(headerText, glyph, opts)
+		}
+	}
+
+	if sgr := theme.BannerSGR(); sgr != "" {
+		lines := strings.Split(banner, "\n")
+		for i, line := range lines {
+			lines[i] = sgr + line + ansiReset
+		}
+		banner = strings.Join(lines, "\n")
+	}
+	return banner
+}
+
+// headerGlyph returns the --header-char override, or def when none is set
+func headerGlyph(opts *FormattingOptions, def string) string {
+	if opts.HeaderChar != "" {
+		return opts.HeaderChar
+	}
+	return def
+}
+
 // Built-in banner style implementations
 
 // NoneBannerStyle displays just the filename with optional alignment
@@ -134,22 +182,14 @@ type DashedBannerStyle struct{}
 func (d DashedBannerStyle) Name() string        { return "dashed" }
 func (d DashedBannerStyle) Description() string { return "Dashed lines above and below" }
 
+func (d DashedBannerStyle) DefaultGlyph() string { return "-" }
+
 func (d DashedBannerStyle) Apply(filename string, opts *FormattingOptions) string {
-	// For dashed/solid styles, we keep the line length matching the text
-	// but apply alignment to the whole block
-	line := strings.Repeat("-", len(filename))
-	block := fmt.Sprintf("%s\n%s\n%s", line, filename, line)
-	
-	// For non-left alignment, we need to align each line
-	if opts.HeaderAlignment != "left" && opts.HeaderAlignment != "" {
-		lines := strings.Split(block, "\n")
-		for i, l := range lines {
-			lines[i] = applyAlignment(l, opts.HeaderAlignment, opts.PageWidth)
-		}
-		return strings.Join(lines, "\n")
-	}
-	
-	return block
+	return d.ApplyGlyph(filename, headerGlyph(opts, d.DefaultGlyph()), opts)
+}
+
+func (d DashedBannerStyle) ApplyGlyph(filename, glyph string, opts *FormattingOptions) string {
+	return ruledBlock(filename, glyph, opts)
 }
 
 // SolidBannerStyle uses solid lines above and below
@@ -158,10 +198,22 @@ type SolidBannerStyle struct{}
 func (s SolidBannerStyle) Name() string        { return "solid" }
 func (s SolidBannerStyle) Description() string { return "Solid lines above and below" }
 
+func (s SolidBannerStyle) DefaultGlyph() string { return "=" }
+
 func (s SolidBannerStyle) Apply(filename string, opts *FormattingOptions) string {
+	return s.ApplyGlyph(filename, headerGlyph(opts, s.DefaultGlyph()), opts)
+}
+
+func (s SolidBannerStyle) ApplyGlyph(filename, glyph string, opts *FormattingOptions) string {
+	return ruledBlock(filename, glyph, opts)
+}
+
+// ruledBlock draws glyph lines above and below the filename for the
+// dashed/solid styles
+func ruledBlock(filename, glyph string, opts *FormattingOptions) string {
 	// For dashed/solid styles, we keep the line length matching the text
 	// but apply alignment to the whole block
-	line := strings.Repeat("=", len(filename))
+	line := strings.Repeat(glyph, displayWidth(filename))
 	block := fmt.Sprintf("%s\n%s\n%s", line, filename, line)
 	
 	// For non-left alignment, we need to align each line
@@ -179,44 +231,101 @@ func (s SolidBannerStyle) Apply(filename string, opts *FormattingOptions) string
 // BoxedBannerStyle creates a box around the filename
 type BoxedBannerStyle struct{}
 
+const (
+	// boxedFrameWidth is the width of the "### " and " ###" edges
+	boxedFrameWidth = 8
+	// boxedMinInnerWidth keeps narrow pages from wrapping text into slivers
+	boxedMinInnerWidth = 10
+)
+
 func (b BoxedBannerStyle) Name() string        { return "boxed" }
 func (b BoxedBannerStyle) Description() string { return "Box with hash characters" }
 
+func (b BoxedBannerStyle) DefaultGlyph() string { return "#" }
+
 func (b BoxedBannerStyle) Apply(filename string, opts *FormattingOptions) string {
-	// Calculate padding for boxed style
-	borderChar := "#"
-	borderLength := opts.PageWidth
-	if borderLength < len(filename)+8 { // Minimum space for "### text ###"
-		borderLength = len(filename) + 8
+	return b.ApplyGlyph(filename, headerGlyph(opts, b.DefaultGlyph()), opts)
+}
+
+func (b BoxedBannerStyle) ApplyGlyph(filename, borderChar string, opts *FormattingOptions) string {
+	edge := strings.Repeat(borderChar, 3)
+
+	// The box spans the page; text too long for it wraps onto more boxed lines
+	innerWidth := opts.PageWidth - boxedFrameWidth
+	if opts.PageWidth <= 0 {
+		innerWidth = displayWidth(filename)
+	} else if innerWidth < boxedMinInnerWidth {
+		innerWidth = boxedMinInnerWidth
 	}
-	
-	topBottom := strings.Repeat(borderChar, borderLength)
-	
-	// Calculate padding based on alignment
-	innerWidth := borderLength - 6 // Account for "### " and " ###"
-	var middleLine string
-	
+
+	topBottom := strings.Repeat(borderChar, innerWidth+boxedFrameWidth)
+	lines := []string{topBottom}
+	for _, text := range wrapBoxedText(filename, innerWidth) {
+		lines = append(lines, fmt.Sprintf("%s %s %s", edge, applyAlignment(text, opts.HeaderAlignment, innerWidth), edge))
+	}
+	lines = append(lines, topBottom)
+
+	return strings.Join(lines, "\n")
+}
+
+// wrapBoxedText splits text into lines of at most width cells, breaking after
+// a path separator or space when the line has one
+func wrapBoxedText(text string, width int) []string {
+	var lines []string
+	for displayWidth(text) > width {
+		head, _ := splitAtWidth(text, width)
+		if head == "" {
+			break
+		}
+		if i := strings.LastIndexAny(head, "/ "); i > 0 {
+			head = head[:i+1]
+		}
+		lines = append(lines, strings.TrimRight(head, " "))
+		text = strings.TrimLeft(text[len(head):], " ")
+	}
+	return append(lines, text)
+}
+
+// RuleBannerStyle renders the header inline within a single horizontal rule
+type RuleBannerStyle struct{}
+
+func (r RuleBannerStyle) Name() string        { return "rule" }
+func (r RuleBannerStyle) Description() string { return "Single line rule with the header inline" }
+
+func (r RuleBannerStyle) Apply(filename string, opts *FormattingOptions) string {
+	const ruleChar = "─"
+	const lead = 2 // Rule characters kept on the short side of the text
+
+	text := " " + filename + " "
+	fill := opts.PageWidth - displayWidth(text) - lead
+	if fill < lead {
+		fill = lead
+	}
+
 	switch opts.HeaderAlignment {
 	case "center":
-		leftPadding := (innerWidth - len(filename)) / 2
-		rightPadding := innerWidth - len(filename) - leftPadding
-		middleLine = fmt.Sprintf("### %s%s%s ###", 
-			strings.Repeat(" ", leftPadding),
-			filename,
-			strings.Repeat(" ", rightPadding))
+		total := fill + lead
+		left := total / 2
+		return strings.Repeat(ruleChar, left) + text + strings.Repeat(ruleChar, total-left)
 	case "right":
-		leftPadding := innerWidth - len(filename)
-		middleLine = fmt.Sprintf("### %s%s ###", 
-			strings.Repeat(" ", leftPadding),
-			filename)
+		return strings.Repeat(ruleChar, fill) + text + strings.Repeat(ruleChar, lead)
 	default: // left
-		rightPadding := innerWidth - len(filename)
-		middleLine = fmt.Sprintf("### %s%s ###", 
-			filename,
-			strings.Repeat(" ", rightPadding))
+		return strings.Repeat(ruleChar, lead) + text + strings.Repeat(ruleChar, fill)
 	}
-	
-	return fmt.Sprintf("%s\n%s\n%s", topBottom, middleLine, topBottom)
+}
+
+// InlineHeaderStyle is the name of the inline banner style, which the
+// renderer also spaces more tightly
+const InlineHeaderStyle = "inline"
+
+// InlineBannerStyle renders the header as a "name:" prefix line
+type InlineBannerStyle struct{}
+
+func (i InlineBannerStyle) Name() string        { return InlineHeaderStyle }
+func (i InlineBannerStyle) Description() string { return "The header as a \"name:\" line directly above the content" }
+
+func (i InlineBannerStyle) Apply(filename string, opts *FormattingOptions) string {
+	return filename + ":"
 }
 
 // Initialize built-in banner styles
@@ -226,4 +335,6 @@ func init() {
 	_ = RegisterBannerStyle(DashedBannerStyle{})
 	_ = RegisterBannerStyle(SolidBannerStyle{})
 	_ = RegisterBannerStyle(BoxedBannerStyle{})
+	_ = RegisterBannerStyle(RuleBannerStyle{})
+	_ = RegisterBannerStyle(InlineBannerStyle{})
 }
\ No newline at end of file
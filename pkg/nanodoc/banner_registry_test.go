@@ -3,12 +3,13 @@ package nanodoc
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestBannerRegistry(t *testing.T) {
 	// Test that built-in styles are registered
 	t.Run("built_in_styles_registered", func(t *testing.T) {
-		expectedStyles := []string{"none", "dashed", "solid", "boxed"}
+		expectedStyles := []string{"none", "dashed", "solid", "boxed", "rule", "inline"}
 		
 		registeredStyles := GetBannerStyleNames()
 		
@@ -209,4 +210,152 @@ func TestCustomBannerStyle(t *testing.T) {
 	if result != expected {
 		t.Errorf("Expected %q, got %q", expected, result)
 	}
-}
\ No newline at end of file
+}
+
+func TestRuleBannerStyle(t *testing.T) {
+	style, exists := GetBannerStyle("rule")
+	if !exists {
+		t.Fatal("Expected rule style to exist")
+	}
+
+	tests := []struct {
+		alignment string
+		check     func(t *testing.T, result string)
+	}{
+		{
+			alignment: "left",
+			check: func(t *testing.T, result string) {
+				if !strings.HasPrefix(result, "── intro.txt ─") {
+					t.Errorf("Expected rule after text for left alignment, got %q", result)
+				}
+			},
+		},
+		{
+			alignment: "center",
+			check: func(t *testing.T, result string) {
+				parts := strings.Split(result, " intro.txt ")
+				if len(parts) != 2 {
+					t.Fatalf("Expected text surrounded by rule, got %q", result)
+				}
+				left, right := utf8.RuneCountInString(parts[0]), utf8.RuneCountInString(parts[1])
+				if left < 2 || right < 2 || left-right > 1 || right-left > 1 {
+					t.Errorf("Expected balanced rule around text, got %d/%d in %q", left, right, result)
+				}
+			},
+		},
+		{
+			alignment: "right",
+			check: func(t *testing.T, result string) {
+				if !strings.HasSuffix(result, "─ intro.txt ──") {
+					t.Errorf("Expected rule before text for right alignment, got %q", result)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alignment, func(t *testing.T) {
+			opts := &FormattingOptions{
+				HeaderAlignment: tt.alignment,
+				PageWidth:       40,
+			}
+
+			result := style.Apply("intro.txt", opts)
+
+			if strings.Contains(result, "\n") {
+				t.Errorf("Expected a single line, got %q", result)
+			}
+			if width := utf8.RuneCountInString(result); width != 40 {
+				t.Errorf("Expected rule to fill page width 40, got %d: %q", width, result)
+			}
+			tt.check(t, result)
+		})
+	}
+}
+
+func TestBoxedBannerStyleWrapsLongText(t *testing.T) {
+	const pageWidth = 40
+	longPath := "/home/user/projects/nanodoc/docs/reference/configuration-options.md"
+
+	for _, alignment := range []string{"left", "center", "right"} {
+		t.Run(alignment, func(t *testing.T) {
+			opts := &FormattingOptions{HeaderAlignment: alignment, PageWidth: pageWidth}
+			lines := strings.Split(BoxedBannerStyle{}.Apply(longPath, opts), "\n")
+
+			if len(lines) < 4 {
+				t.Fatalf("Expected the path to wrap over several boxed lines, got:\n%s", strings.Join(lines, "\n"))
+			}
+			var text []string
+			for i, line := range lines {
+				if len(line) != pageWidth {
+					t.Errorf("Line %d is %d wide, want %d: %q", i, len(line), pageWidth, line)
+				}
+				if i == 0 || i == len(lines)-1 {
+					if line != strings.Repeat("#", pageWidth) {
+						t.Errorf("Expected a full border on line %d, got %q", i, line)
+					}
+					continue
+				}
+				if !strings.HasPrefix(line, "### ") || !strings.HasSuffix(line, " ###") {
+					t.Errorf("Expected boxed edges on line %d, got %q", i, line)
+				}
+				inner := line[4 : len(line)-4]
+				switch alignment {
+				case "left":
+					if strings.HasPrefix(inner, " ") {
+						t.Errorf("Expected left-aligned text, got %q", inner)
+					}
+				case "right":
+					if strings.HasSuffix(inner, " ") {
+						t.Errorf("Expected right-aligned text, got %q", inner)
+					}
+				}
+				text = append(text, strings.TrimSpace(inner))
+			}
+
+			// Lines break after path separators and rejoin to the original
+			if joined := strings.Join(text, ""); joined != longPath {
+				t.Errorf("Wrapped text = %q, want %q", joined, longPath)
+			}
+			if !strings.HasSuffix(text[0], "/") {
+				t.Errorf("Expected the first line to break after a separator, got %q", text[0])
+			}
+		})
+	}
+}
+
+func TestHeaderChar(t *testing.T) {
+	opts := &FormattingOptions{HeaderAlignment: "left", PageWidth: 12, HeaderChar: "*"}
+
+	if got := (DashedBannerStyle{}).Apply("a.txt", opts); got != "*****\na.txt\n*****" {
+		t.Errorf("dashed = %q", got)
+	}
+	if got := (SolidBannerStyle{}).Apply("a.txt", opts); got != "*****\na.txt\n*****" {
+		t.Errorf("solid = %q", got)
+	}
+	if got := (BoxedBannerStyle{}).Apply("a.txt", opts); got != "******************\n*** a.txt      ***\n******************" {
+		t.Errorf("boxed = %q", got)
+	}
+
+	// The flag wins over the theme's glyph
+	opts.HeaderStyle = "solid"
+	theme := &Theme{Name: "test", Styles: map[string]string{"banner.solid.char": "~"}}
+	if got := applyBannerStyle("a.txt", opts, theme); got != "*****\na.txt\n*****" {
+		t.Errorf("Expected --header-char over the theme glyph, got %q", got)
+	}
+
+	// Without an override the styles keep their own characters
+	opts.HeaderChar = ""
+	if got := (DashedBannerStyle{}).Apply("a.txt", opts); got != "-----\na.txt\n-----" {
+		t.Errorf("dashed without override = %q", got)
+	}
+
+	for _, char := range []string{"**", "ab"} {
+		if err := (FormattingOptions{HeaderChar: char}).Validate(); err == nil {
+			t.Errorf("Expected --header-char %q to be rejected", char)
+		}
+	}
+	if err := (FormattingOptions{HeaderChar: "═"}).Validate(); err != nil {
+		t.Errorf("Expected a single multi-byte character to be accepted, got %v", err)
+	}
+}
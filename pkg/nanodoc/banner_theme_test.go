@@ -0,0 +1,94 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThemeBannerGlyphs(t *testing.T) {
+	themePath := filepath.Join(t.TempDir(), "heavy.yaml")
+	themeYAML := "heading: \"blue bold\"\nbanner.solid.char: \"━\"\nbanner.boxed.char: \"*\"\n"
+	if err := os.WriteFile(themePath, []byte(themeYAML), 0644); err != nil {
+		t.Fatalf("Failed to write theme: %v", err)
+	}
+	theme, err := LoadCustomTheme(themePath)
+	if err != nil {
+		t.Fatalf("LoadCustomTheme() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		style    string
+		expected string
+	}{
+		{
+			name:     "solid uses theme glyph",
+			style:    "solid",
+			expected: "━━━━━━━━━\nintro.txt\n━━━━━━━━━",
+		},
+		{
+			name:     "dashed keeps default glyph",
+			style:    "dashed",
+			expected: "---------\nintro.txt\n---------",
+		},
+		{
+			name:     "boxed uses theme glyph for border and edges",
+			style:    "boxed",
+			expected: "******************\n*** intro.txt  ***\n******************",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &FormattingOptions{HeaderStyle: tt.style, HeaderAlignment: "left", PageWidth: 10}
+			if got := applyBannerStyle("intro.txt", opts, theme); got != tt.expected {
+				t.Errorf("applyBannerStyle() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestThemeBannerColor(t *testing.T) {
+	theme := &Theme{Name: "test", Styles: map[string]string{"banner.color": "bright_cyan bold on black"}}
+	opts := &FormattingOptions{HeaderStyle: "dashed", HeaderAlignment: "left", PageWidth: 80}
+
+	got := applyBannerStyle("a.txt", opts, theme)
+	for _, line := range strings.Split(got, "\n") {
+		if !strings.HasPrefix(line, "\x1b[96;1;40m") || !strings.HasSuffix(line, ansiReset) {
+			t.Errorf("Expected each banner line colored, got %q", line)
+		}
+	}
+
+	// Without a theme the banner is unchanged
+	if got := applyBannerStyle("a.txt", opts, nil); got != "-----\na.txt\n-----" {
+		t.Errorf("Expected plain banner without a theme, got %q", got)
+	}
+}
+
+func TestThemeBannerRendering(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/intro.txt", Content: "Welcome"}},
+		FormattingOptions: FormattingOptions{
+			ShowFilenames:   true,
+			HeaderFormat:    HeaderFormatFilename,
+			HeaderStyle:     "solid",
+			HeaderAlignment: "left",
+			PageWidth:       80,
+		},
+	}
+	ctx := &FormattingContext{
+		Theme:         &Theme{Name: "test", Styles: map[string]string{"banner.solid.char": "~"}},
+		ShowFilenames: true,
+		HeaderFormat:  HeaderFormatFilename,
+	}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if !strings.Contains(result, "~~~~~~~~~~~~\n1. intro.txt\n~~~~~~~~~~~~") {
+		t.Errorf("Expected banner drawn with the theme glyph, got:\n%s", result)
+	}
+}
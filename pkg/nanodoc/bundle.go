@@ -2,11 +2,29 @@ package nanodoc
 
 import (
 	"bufio"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 )
 
+// bundleVarPattern matches ${VAR} placeholders in bundle lines
+var bundleVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// bundleVarNamePattern matches a valid bundle variable name
+var bundleVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// bundleSectionPattern matches a decorated bundle comment such as
+// "# --- Chapter 1 ---", capturing its title
+var bundleSectionPattern = regexp.MustCompile(`^#+\s*[-=]{3,}\s*(.*?)\s*[-=]{3,}$`)
+
+// bundleTitlePattern matches a content line with a header title such as
+// "intro.txt | Getting Started". The "|" needs whitespace on both sides, so
+// it is never taken from a path.
+var bundleTitlePattern = regexp.MustCompile(`^(.*?)\s+\|\s+(.*)$`)
 
 // BundleResult holds both the raw option lines and file paths from a bundle file
 type BundleResult struct {
@@ -14,6 +32,8 @@ type BundleResult struct {
 	Paths []string
 	// Raw option lines from the bundle (unparsed)
 	OptionLines []string
+	// Inline content blocks keyed by the synthetic path used for them in Paths
+	InlineBlocks map[string]string
 }
 
 
@@ -23,6 +43,19 @@ type BundleProcessor struct {
 	visitedBundles map[string]bool
 	// Track the current path for circular dependency error reporting
 	bundlePath []string
+	// Inline content blocks found in processed bundles, keyed by synthetic path
+	inlineBlocks map[string]FileContent
+	// Values substituted for ${VAR} placeholders in bundle lines
+	vars map[string]string
+	// Value used for undefined variables; nil makes them an error
+	varDefault *string
+	// Record decorated comments as section headers among the paths
+	commentsAsSections bool
+	// Sort the files expanded from each bundle instead of keeping their order
+	sortBundles bool
+	// Header titles given in bundle lines, keyed by the resolved path, one per
+	// line listing that path in declaration order ("" for untitled lines)
+	titles map[string][]string
 }
 
 // NewBundleProcessor creates a new bundle processor
@@ -30,9 +63,22 @@ func NewBundleProcessor() *BundleProcessor {
 	return &BundleProcessor{
 		visitedBundles: make(map[string]bool),
 		bundlePath:     make([]string, 0),
+		inlineBlocks:   make(map[string]FileContent),
+		titles:         make(map[string][]string),
 	}
 }
 
+// newBundleProcessorForOptions creates a bundle processor using the bundle
+// variables from options
+func newBundleProcessorForOptions(options FormattingOptions) *BundleProcessor {
+	bp := NewBundleProcessor()
+	bp.vars = options.BundleVars
+	bp.varDefault = options.BundleVarDefault
+	bp.commentsAsSections = options.BundleCommentsAsSections
+	bp.sortBundles = options.SortBundle
+	return bp
+}
+
 // ProcessBundleFile reads and processes a bundle file, returning the list of paths it contains
 func (bp *BundleProcessor) ProcessBundleFile(bundlePath string) ([]string, error) {
 	result, err := bp.ProcessBundleFileWithOptions(bundlePath)
@@ -77,28 +123,86 @@ func (bp *BundleProcessor) ProcessBundleFileWithOptions(bundlePath string) (*Bun
 
 	var paths []string
 	var optionLines []string
+	inlineBlocks := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 
+	// State for an inline block being read, delimited by <<<DELIM ... DELIM
+	var inlineDelim string
+	var inlineLines []string
+	lineNum := 0
+
 	for scanner.Scan() {
+		lineNum++
 		line := strings.TrimSpace(scanner.Text())
 
-		// Skip empty lines and comments
+		// Inside an inline block, keep lines verbatim until the delimiter
+		if inlineDelim != "" {
+			if line != inlineDelim {
+				inlineLines = append(inlineLines, scanner.Text())
+				continue
+			}
+
+			name := fmt.Sprintf("%s#inline-%d", absBundlePath, len(inlineBlocks)+1)
+			content := strings.Join(inlineLines, "\n")
+			inlineBlocks[name] = content
+			bp.inlineBlocks[name] = FileContent{
+				Filepath:       name,
+				Content:        content,
+				OriginalSource: absBundlePath,
+			}
+			paths = append(paths, name)
+			inlineDelim = ""
+			inlineLines = nil
+			continue
+		}
+
+		// Start of an inline block
+		if strings.HasPrefix(line, InlineBlockPrefix) {
+			inlineDelim = strings.TrimSpace(strings.TrimPrefix(line, InlineBlockPrefix))
+			if inlineDelim == "" {
+				return nil, &FileError{Path: bundlePath, Err: fmt.Errorf("inline block is missing a delimiter after %s", InlineBlockPrefix)}
+			}
+			continue
+		}
+
+		// Skip empty lines and comments, keeping section comments if requested
 		if line == "" || strings.HasPrefix(line, "#") {
+			if match := bundleSectionPattern.FindStringSubmatch(line); match != nil && match[1] != "" && bp.commentsAsSections {
+				name := fmt.Sprintf("%s#section-%d", absBundlePath, lineNum)
+				bp.inlineBlocks[name] = FileContent{
+					Filepath:       name,
+					OriginalSource: absBundlePath,
+					SectionTitle:   match[1],
+				}
+				paths = append(paths, name)
+			}
 			continue
 		}
 
+		line, err = bp.substituteVars(line)
+		if err != nil {
+			return nil, &FileError{Path: bundlePath, Err: fmt.Errorf("line %d: %w", lineNum, err)}
+		}
+
 		// Check if this line is a command-line option
 		if strings.HasPrefix(line, "-") {
 			optionLines = append(optionLines, line)
 			continue
 		}
 
+		// A " | Title" suffix replaces the file's derived header name
+		var title string
+		if match := bundleTitlePattern.FindStringSubmatch(line); match != nil {
+			line, title = match[1], match[2]
+		}
+
 		// Handle file paths - make them relative to the bundle file's directory
 		resolvedPath := line
 		if !filepath.IsAbs(line) {
 			bundleDir := filepath.Dir(bundlePath)
 			resolvedPath = filepath.Join(bundleDir, line)
 		}
+		bp.titles[resolvedPath] = append(bp.titles[resolvedPath], title)
 
 		paths = append(paths, resolvedPath)
 	}
@@ -106,19 +210,80 @@ func (bp *BundleProcessor) ProcessBundleFileWithOptions(bundlePath string) (*Bun
 	if err := scanner.Err(); err != nil {
 		return nil, &FileError{Path: bundlePath, Err: err}
 	}
+	if inlineDelim != "" {
+		return nil, &FileError{Path: bundlePath, Err: fmt.Errorf("unterminated inline block: missing closing %s", inlineDelim)}
+	}
 
 	return &BundleResult{
-		Paths:       paths,
-		OptionLines: optionLines,
+		Paths:        bp.dropEmptySections(paths),
+		OptionLines:  optionLines,
+		InlineBlocks: inlineBlocks,
 	}, nil
 }
 
+// nextTitle returns the bundle title of the next listing of path and moves
+// past it, so a file listed twice keeps the title given on each line
+func (bp *BundleProcessor) nextTitle(path string) string {
+	titles := bp.titles[path]
+	if len(titles) == 0 {
+		return ""
+	}
+	bp.titles[path] = titles[1:]
+	return titles[0]
+}
+
+// isSection reports whether path stands for a bundle comment section
+func (bp *BundleProcessor) isSection(path string) bool {
+	return bp.inlineBlocks[path].SectionTitle != ""
+}
+
+// dropEmptySections removes sections with no paths before the next section or
+// the end of the bundle, such as an "Options" heading above option lines
+func (bp *BundleProcessor) dropEmptySections(paths []string) []string {
+	result := make([]string, 0, len(paths))
+	for i, path := range paths {
+		if bp.isSection(path) && (i+1 == len(paths) || bp.isSection(paths[i+1])) {
+			continue
+		}
+		result = append(result, path)
+	}
+	return result
+}
+
+// substituteVars replaces ${VAR} placeholders in a bundle line
+func (bp *BundleProcessor) substituteVars(line string) (string, error) {
+	var undefined string
+	result := bundleVarPattern.ReplaceAllStringFunc(line, func(match string) string {
+		name := bundleVarPattern.FindStringSubmatch(match)[1]
+		if value, ok := bp.vars[name]; ok {
+			return value
+		}
+		if bp.varDefault != nil {
+			return *bp.varDefault
+		}
+		if undefined == "" {
+			undefined = name
+		}
+		return match
+	})
+
+	if undefined != "" {
+		return "", fmt.Errorf("%w: ${%s} (set it with --bundle-var %s=VALUE)", ErrUndefinedVariable, undefined, undefined)
+	}
+	return result, nil
+}
 
 // ProcessPaths takes a list of paths and expands any bundle files recursively
 func (bp *BundleProcessor) ProcessPaths(paths []string) ([]string, error) {
 	var expandedPaths []string
 
 	for _, path := range paths {
+		// Inline blocks are synthetic paths and must not be expanded again
+		if _, isInline := bp.inlineBlocks[path]; isInline {
+			expandedPaths = append(expandedPaths, path)
+			continue
+		}
+
 		// Check if it's a bundle file
 		if isBundleFile(path) {
 			// Process the bundle file recursively
@@ -132,6 +297,9 @@ func (bp *BundleProcessor) ProcessPaths(paths []string) ([]string, error) {
 			if err != nil {
 				return nil, err
 			}
+			if bp.sortBundles {
+				bp.sortFileRuns(expandedBundlePaths)
+			}
 
 			expandedPaths = append(expandedPaths, expandedBundlePaths...)
 		} else {
@@ -143,6 +311,113 @@ func (bp *BundleProcessor) ProcessPaths(paths []string) ([]string, error) {
 	return expandedPaths, nil
 }
 
+// sortFileRuns sorts paths in place alphabetically, one run of files at a
+// time: inline blocks and comment sections stay where they were declared, so
+// each section keeps its own files
+func (bp *BundleProcessor) sortFileRuns(paths []string) {
+	start := 0
+	for i := 0; i <= len(paths); i++ {
+		if i < len(paths) {
+			if _, isInline := bp.inlineBlocks[paths[i]]; !isInline {
+				continue
+			}
+		}
+		sortPaths(paths[start:i])
+		start = i + 1
+	}
+}
+
+// uniquePaths drops paths that duplicate an earlier one according to mode:
+// UniqueByBasename keeps the first file with a given name, UniqueByDir keeps
+// the alphabetically first file of each directory. Inline blocks are kept.
+func (bp *BundleProcessor) uniquePaths(paths []string, mode string) []string {
+	if mode == "" || mode == UniqueByNone {
+		return paths
+	}
+
+	keyFor := func(path string) string {
+		filePath, _ := parsePathWithRange(path)
+		if mode == UniqueByDir {
+			return filepath.Dir(filePath)
+		}
+		return filepath.Base(filePath)
+	}
+
+	// For directories, pick the alphabetically first file up front
+	firstInDir := make(map[string]string)
+	if mode == UniqueByDir {
+		for _, path := range paths {
+			if _, isInline := bp.inlineBlocks[path]; isInline {
+				continue
+			}
+			dir := keyFor(path)
+			if current, ok := firstInDir[dir]; !ok || path < current {
+				firstInDir[dir] = path
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, path := range paths {
+		if _, isInline := bp.inlineBlocks[path]; isInline {
+			result = append(result, path)
+			continue
+		}
+
+		key := keyFor(path)
+		if seen[key] {
+			slog.Debug("Skipping duplicate file", "path", path, "unique-by", mode)
+			continue
+		}
+		if mode == UniqueByDir && firstInDir[key] != path {
+			slog.Debug("Skipping duplicate file", "path", path, "unique-by", mode)
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, path)
+	}
+	return result
+}
+
+// skipDuplicatePaths handles paths that repeat an earlier one, by absolute
+// path and range spec, according to mode: DuplicateSkip drops them and
+// DuplicateError returns a FileError wrapping ErrDuplicateFile. The same file
+// with different ranges is not a duplicate. Inline blocks are kept.
+func (bp *BundleProcessor) skipDuplicatePaths(paths []string, mode string) ([]string, error) {
+	if mode == "" || mode == DuplicateKeep {
+		return paths, nil
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, path := range paths {
+		if _, isInline := bp.inlineBlocks[path]; isInline {
+			result = append(result, path)
+			continue
+		}
+
+		filePath, rangeSpec := parsePathWithRange(path)
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return nil, &FileError{Path: path, Err: err}
+		}
+		key := absPath + ":" + rangeSpec
+		if seen[key] {
+			if mode == DuplicateError {
+				return nil, &FileError{Path: path, Err: ErrDuplicateFile}
+			}
+			slog.Warn("Skipping duplicate file", "path", path)
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, path)
+	}
+	return result, nil
+}
+
 // BuildDocument creates a Document from resolved paths
 // Note: Bundle option processing has been moved to the CLI layer
 func BuildDocument(pathInfos []PathInfo, options FormattingOptions) (*Document, error) {
@@ -158,7 +433,7 @@ func BuildDocumentWithExplicitFlags(pathInfos []PathInfo, options FormattingOpti
 
 // BuildDocumentWithOptions creates a Document from resolved paths with already-merged options
 func BuildDocumentWithOptions(pathInfos []PathInfo, options FormattingOptions) (*Document, error) {
-	bp := NewBundleProcessor()
+	bp := newBundleProcessorForOptions(options)
 	var allPaths []string
 
 	// First, collect all paths from PathInfo
@@ -167,14 +442,35 @@ func BuildDocumentWithOptions(pathInfos []PathInfo, options FormattingOptions) (
 		case "file":
 			allPaths = append(allPaths, info.Original)
 		case "directory", "glob":
-			allPaths = append(allPaths, info.Files...)
+			allPaths = append(allPaths, pathInfoFiles(info)...)
 		case "bundle":
 			allPaths = append(allPaths, info.Absolute)
 		}
 	}
 
-	// Process paths to expand bundles
-	expandedPaths, err := bp.ProcessPaths(allPaths)
+	// Process paths to expand bundles, remembering which bundle each came from
+	var expandedPaths []string
+	sourceGroups := make(map[string]string)
+	for _, path := range allPaths {
+		paths, err := bp.ProcessPaths([]string{path})
+		if err != nil {
+			return nil, err
+		}
+		if isBundleFile(path) {
+			group, err := filepath.Abs(path)
+			if err != nil {
+				return nil, &FileError{Path: path, Err: err}
+			}
+			for _, p := range paths {
+				if _, ok := sourceGroups[p]; !ok {
+					sourceGroups[p] = group
+				}
+			}
+		}
+		expandedPaths = append(expandedPaths, paths...)
+	}
+	expandedPaths = bp.uniquePaths(expandedPaths, options.UniqueBy)
+	expandedPaths, err := bp.skipDuplicatePaths(expandedPaths, options.OnDuplicate)
 	if err != nil {
 		return nil, err
 	}
@@ -182,6 +478,11 @@ func BuildDocumentWithOptions(pathInfos []PathInfo, options FormattingOptions) (
 	// Create PathInfo objects for expanded paths, treating them all as files
 	var resolvedInfos []PathInfo
 	for _, path := range expandedPaths {
+		// Inline blocks already carry their content
+		if _, isInline := bp.inlineBlocks[path]; isInline {
+			continue
+		}
+
 		absPath, err := filepath.Abs(path)
 		if err != nil {
 			return nil, &FileError{Path: path, Err: err}
@@ -192,31 +493,76 @@ func BuildDocumentWithOptions(pathInfos []PathInfo, options FormattingOptions) (
 			Original: path,
 			Absolute: absPath,
 			Type:     "file",
+			Title:    bp.nextTitle(path),
 		})
 	}
 
-	// Extract content from all files
-	contents, err := ResolveAndExtractFiles(resolvedInfos, options.AdditionalExtensions)
+	// Extract content from all files; oversized ones may be skipped
+	extracted, skipped, err := extractFiles(resolvedInfos, &options)
 	if err != nil {
 		return nil, err
 	}
 
+	// Interleave inline blocks with the extracted files in declaration order
+	contents := make([]FileContent, 0, len(expandedPaths))
+	next := 0
+	for _, path := range expandedPaths {
+		content, isInline := bp.inlineBlocks[path]
+		if !isInline {
+			file, title := extracted[next], resolvedInfos[next].Title
+			next++
+			if file == nil {
+				continue
+			}
+			content = *file
+			content.HeaderTitle = title
+		}
+		content.SourceGroup = sourceGroups[path]
+		contents = append(contents, content)
+	}
+
+	if options.StripFrontMatter {
+		for i := range contents {
+			if contents[i].OriginalSource == "" && isMarkdownFile(contents[i].Filepath) {
+				contents[i].Content, contents[i].FrontMatterTitle = stripFrontMatter(contents[i].Content)
+			}
+		}
+	}
+
+	if len(options.Sections) > 0 {
+		contents = selectSections(contents, options.Sections, options.SectionOnly)
+	}
+
 	// Create the document
 	doc := NewDocument()
 	doc.ContentItems = contents
-	doc.FormattingOptions = options
+	doc.Skipped = skipped
+	doc.CommonRoot = commonRoot(contents)
+	// Copy the options so documents built from one value never share state
+	doc.FormattingOptions = options.Clone()
 
 	// Process live bundles - integrate both approaches
 	if err := ProcessLiveBundles(doc); err != nil {
 		return nil, err
 	}
 
+	// Lines are dropped once includes are expanded, so included copies count
+	if options.DedupeLines {
+		dedupeLines(doc.ContentItems, options.DedupeLinesScope)
+	}
+
 	return doc, nil
 }
 
 // ExtractBundleOptionLines extracts raw option lines from all bundle files
 func ExtractBundleOptionLines(pathInfos []PathInfo) ([]string, error) {
-	bp := NewBundleProcessor()
+	return ExtractBundleOptionLinesWithOptions(pathInfos, FormattingOptions{})
+}
+
+// ExtractBundleOptionLinesWithOptions extracts raw option lines from all bundle
+// files, substituting bundle variables from options
+func ExtractBundleOptionLinesWithOptions(pathInfos []PathInfo, options FormattingOptions) ([]string, error) {
+	bp := newBundleProcessorForOptions(options)
 	var allOptionLines []string
 
 	// Extract option lines from all bundle files
@@ -241,12 +587,13 @@ func ExtractBundleOptionLines(pathInfos []PathInfo) ([]string, error) {
 func ProcessLiveBundles(doc *Document) error {
 	for i := range doc.ContentItems {
 		// Skip processing for common documentation files to avoid processing
-		// [[file:]] examples as actual directives
-		if shouldSkipLiveBundleProcessing(doc.ContentItems[i].Filepath) {
+		// [[file:]] examples as actual directives, unless opted in
+		path := doc.ContentItems[i].Filepath
+		if shouldSkipLiveBundleProcessing(path) && !matchesAnyPattern(path, doc.FormattingOptions.ProcessIncludesIn) {
 			continue
 		}
 		
-		processedContent, err := ProcessLiveBundle(doc.ContentItems[i].Content)
+		processedContent, err := processLiveBundleRecursive(doc.ContentItems[i].Content, path, 0, make(map[string]bool), doc.FormattingOptions.Strict)
 		if err != nil {
 			return err
 		}
@@ -268,12 +615,39 @@ func shouldSkipLiveBundleProcessing(filepath string) bool {
 
 // ProcessLiveBundle handles inline bundle processing
 // It looks for directives like [[file:path/to/file.txt]] or [[file:path/to/file.txt:L10-20]]
-// and replaces them with the actual file content
+// and replaces them with the actual file content. [[glob:docs/*.md]] is replaced
+// with the content of every matching file, in sorted order
 func ProcessLiveBundle(content string) (string, error) {
-	return processLiveBundleRecursive(content, 0, make(map[string]bool))
+	return processLiveBundleRecursive(content, "", 0, make(map[string]bool), false)
+}
+
+// Live bundle directive prefixes; both have the same length
+const (
+	liveFilePrefix = "[[file:"
+	liveGlobPrefix = "[[glob:"
+)
+
+// nextLiveDirective returns the position and prefix of the first directive in
+// s, or -1 when there is none
+func nextLiveDirective(s string) (int, string) {
+	fileLoc := strings.Index(s, liveFilePrefix)
+	globLoc := strings.Index(s, liveGlobPrefix)
+	if globLoc != -1 && (fileLoc == -1 || globLoc < fileLoc) {
+		return globLoc, liveGlobPrefix
+	}
+	return fileLoc, liveFilePrefix
 }
 
-func processLiveBundleRecursive(content string, depth int, visited map[string]bool) (string, error) {
+// processLiveBundleRecursive expands the directives in content. source is the
+// file the content came from; file paths and glob patterns are relative to its
+// directory (the working directory when empty). In strict mode a directive that names no
+// file is an error instead of being left as-is.
+func processLiveBundleRecursive(content, source string, depth int, visited map[string]bool, strict bool) (string, error) {
+	dir := ""
+	if source != "" {
+		dir = filepath.Dir(source)
+	}
+
 	// Prevent infinite recursion
 	const maxDepth = 10
 	if depth > maxDepth {
@@ -289,7 +663,7 @@ func processLiveBundleRecursive(content string, depth int, visited map[string]bo
 	
 	for {
 		// Find the next directive
-		loc := strings.Index(result[startPos:], "[[file:")
+		loc, prefix := nextLiveDirective(result[startPos:])
 		if loc == -1 {
 			break
 		}
@@ -301,39 +675,45 @@ func processLiveBundleRecursive(content string, depth int, visited map[string]bo
 		endLoc := strings.Index(result[loc:], "]]")
 		if endLoc == -1 {
 			// Malformed directive, skip it
-			startPos = loc + 7 // len("[[file:")
+			startPos = loc + len(prefix)
 			continue
 		}
 		endLoc += loc + 2 // Include the ]]
 		
-		// Parse the file path (and optional range)
-		pathStart := loc + 7 // len("[[file:")
+		// Parse the file path (and optional range) or glob pattern
+		pathStart := loc + len(prefix)
 		pathEnd := endLoc - 2 // Before ]]
 		pathWithRange := result[pathStart:pathEnd]
-		
-		// Check for circular references
-		if visited[pathWithRange] {
-			return "", &CircularDependencyError{
-				Path:  pathWithRange,
-				Chain: mapKeysToSlice(visited),
+
+		var processedContent string
+		if prefix == liveGlobPrefix {
+			expanded, ok, err := expandLiveGlob(pathWithRange, dir, depth, visited, strict)
+			if err != nil {
+				return "", err
 			}
-		}
-		
-		// Mark as visited
-		visited[pathWithRange] = true
-		
-		// Extract the file content
-		fileContent, err := ExtractFileContent(pathWithRange)
-		if err != nil {
-			// On error, leave the directive as-is and continue
-			startPos = endLoc
-			continue
-		}
-		
-		// Process nested directives in the included content
-		processedContent, err := processLiveBundleRecursive(fileContent.Content, depth+1, visited)
-		if err != nil {
-			return "", err
+			if !ok {
+				if strict {
+					return "", unresolvedDirectiveError(source, result[loc:endLoc], fmt.Errorf("no files match"))
+				}
+				// Nothing matched, leave the directive as-is and continue
+				startPos = endLoc
+				continue
+			}
+			processedContent = expanded
+		} else {
+			included, err := includeLiveFile(pathWithRange, dir, depth, visited, strict)
+			if missing, ok := err.(*liveFileError); ok {
+				if strict {
+					return "", unresolvedDirectiveError(source, result[loc:endLoc], missing.err)
+				}
+				// On error, leave the directive as-is and continue
+				startPos = endLoc
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			processedContent = included
 		}
 		
 		// Replace the directive with the content
@@ -341,19 +721,130 @@ func processLiveBundleRecursive(content string, depth int, visited map[string]bo
 		
 		// Update start position
 		startPos = loc + len(processedContent)
-		
-		// Remove from visited after processing
-		delete(visited, pathWithRange)
 	}
 	
 	return result, nil
 }
 
-// Helper function to convert map keys to slice
+// liveFileError reports a [[file:]] target that cannot be read
+type liveFileError struct {
+	err error
+}
+
+func (e *liveFileError) Error() string {
+	return e.err.Error()
+}
+
+// unresolvedDirectiveError reports a live bundle directive that names no file
+func unresolvedDirectiveError(source, directive string, err error) error {
+	if source == "" {
+		source = "live bundle"
+	}
+	return &FileError{Path: source, Err: fmt.Errorf("unresolved directive %s: %w", directive, err)}
+}
+
+// includeLiveFile returns the processed content of a [[file:]] directive, or a
+// *liveFileError when the file cannot be read. Relative paths are resolved
+// against dir, the directory of the including file.
+func includeLiveFile(pathWithRange, dir string, depth int, visited map[string]bool, strict bool) (string, error) {
+	path, rangeSpec := parsePathWithRange(pathWithRange)
+	if dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+		pathWithRange = path
+		if rangeSpec != "" {
+			pathWithRange += ":" + rangeSpec
+		}
+	}
+
+	// Check for circular references
+	key := liveVisitKey(path)
+	if visited[key] {
+		return "", &CircularDependencyError{
+			Path:  path,
+			Chain: mapKeysToSlice(visited),
+		}
+	}
+
+	// Mark as visited, and remove it again after processing
+	visited[key] = true
+	defer delete(visited, key)
+
+	// Extract the file content
+	fileContent, err := ExtractFileContent(pathWithRange)
+	if err != nil {
+		return "", &liveFileError{err: err}
+	}
+
+	// Process nested directives in the included content
+	return processLiveBundleRecursive(fileContent.Content, path, depth+1, visited, strict)
+}
+
+// liveVisitKey returns the key of path in the set of files being included:
+// its cleaned absolute path, so [[file:]] and [[glob:]] directives naming the
+// same file meet
+func liveVisitKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// expandLiveGlob returns the processed contents of the files matching a
+// [[glob:]] pattern in sorted order, one after another on their own lines.
+// ok is false when nothing matches
+func expandLiveGlob(pattern, dir string, depth int, visited map[string]bool, strict bool) (content string, ok bool, err error) {
+	if dir != "" && !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", false, nil
+	}
+
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		return "", false, nil
+	}
+	sortPaths(files)
+
+	parts := make([]string, 0, len(files))
+	for _, file := range files {
+		// Each match is checked for cycles on its own, like a [[file:]] directive
+		key := liveVisitKey(file)
+		if visited[key] {
+			return "", false, &CircularDependencyError{
+				Path:  file,
+				Chain: mapKeysToSlice(visited),
+			}
+		}
+		visited[key] = true
+
+		fileContent, err := ExtractFileContent(file)
+		if err != nil {
+			delete(visited, key)
+			return "", false, err
+		}
+		processed, err := processLiveBundleRecursive(fileContent.Content, file, depth+1, visited, strict)
+		delete(visited, key)
+		if err != nil {
+			return "", false, err
+		}
+		parts = append(parts, processed)
+	}
+	return strings.Join(parts, "\n"), true, nil
+}
+
+// Helper function to convert map keys to a sorted slice
 func mapKeysToSlice(m map[string]bool) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	return keys
 }
\ No newline at end of file
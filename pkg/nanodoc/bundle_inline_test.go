@@ -0,0 +1,159 @@
+package nanodoc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleInlineBlocks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "first.txt"), []byte("First file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "second.txt"), []byte("Second file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundleFile := filepath.Join(tempDir, "doc.bundle.txt")
+	bundleContent := strings.Join([]string{
+		"first.txt",
+		"<<<TEXT",
+		"Some prose between the files.",
+		"# not a comment",
+		"--not-an-option",
+		"TEXT",
+		"second.txt",
+	}, "\n")
+	if err := os.WriteFile(bundleFile, []byte(bundleContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("bundle_result", func(t *testing.T) {
+		bp := NewBundleProcessor()
+		result, err := bp.ProcessBundleFileWithOptions(bundleFile)
+		if err != nil {
+			t.Fatalf("ProcessBundleFileWithOptions() error = %v", err)
+		}
+
+		if len(result.Paths) != 3 {
+			t.Fatalf("Expected 3 paths, got %v", result.Paths)
+		}
+		if len(result.OptionLines) != 0 {
+			t.Errorf("Expected no option lines, got %v", result.OptionLines)
+		}
+
+		block, ok := result.InlineBlocks[result.Paths[1]]
+		if !ok {
+			t.Fatalf("Expected second path to be an inline block, got %v", result.Paths)
+		}
+		expected := "Some prose between the files.\n# not a comment\n--not-an-option"
+		if block != expected {
+			t.Errorf("Expected inline content %q, got %q", expected, block)
+		}
+	})
+
+	t.Run("renders_in_order", func(t *testing.T) {
+		pathInfos := []PathInfo{{Original: bundleFile, Absolute: bundleFile, Type: "bundle"}}
+		opts := FormattingOptions{
+			ShowFilenames: true,
+			HeaderFormat:  HeaderFormatFilename,
+			SequenceStyle: SequenceNumerical,
+		}
+
+		doc, err := BuildDocument(pathInfos, opts)
+		if err != nil {
+			t.Fatalf("BuildDocument() error = %v", err)
+		}
+		if len(doc.ContentItems) != 3 {
+			t.Fatalf("Expected 3 content items, got %d", len(doc.ContentItems))
+		}
+
+		ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename}
+		output, err := RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+
+		firstIdx := strings.Index(output, "First file")
+		proseIdx := strings.Index(output, "Some prose between the files.")
+		secondIdx := strings.Index(output, "Second file")
+		if firstIdx == -1 || proseIdx == -1 || secondIdx == -1 || !(firstIdx < proseIdx && proseIdx < secondIdx) {
+			t.Errorf("Expected inline block between the two files, got:\n%s", output)
+		}
+
+		// The inline block does not get a file header of its own
+		if strings.Contains(output, "inline-1") {
+			t.Errorf("Expected no header for the inline block, got:\n%s", output)
+		}
+		if !strings.Contains(output, "2. second.txt") {
+			t.Errorf("Expected second file to be numbered 2, got:\n%s", output)
+		}
+
+		// It is set apart from the file before it like a file would be
+		if !strings.Contains(output, "First file\n\nSome prose") {
+			t.Errorf("Expected a blank line between the file and the inline block, got:\n%s", output)
+		}
+		ctx.LineNumbers = LineNumberFile
+		output, err = RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		if !strings.Contains(output, "1 | First file\n\n1 | Some prose") {
+			t.Errorf("Expected a blank line before the numbered inline block, got:\n%s", output)
+		}
+
+		// Plain headers name files only
+		doc.FormattingOptions.OutputFormat = "plain"
+		doc.FormattingOptions.PlainHeaders = true
+		output, err = RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		if !strings.Contains(output, "First file\n\nSome prose") || strings.Contains(output, "inline-1") {
+			t.Errorf("Expected the inline block set apart without a header, got:\n%s", output)
+		}
+	})
+
+	t.Run("json_title", func(t *testing.T) {
+		pathInfos := []PathInfo{{Original: bundleFile, Absolute: bundleFile, Type: "bundle"}}
+		doc, err := BuildDocument(pathInfos, FormattingOptions{OutputFormat: "json", SequenceStyle: SequenceNumerical})
+		if err != nil {
+			t.Fatalf("BuildDocument() error = %v", err)
+		}
+		output, err := RenderDocument(doc, &FormattingContext{})
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+
+		var got jsonDocument
+		if err := json.Unmarshal([]byte(output), &got); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		if len(got.Files) != 3 {
+			t.Fatalf("Expected 3 files, got %+v", got.Files)
+		}
+		if block := got.Files[1]; block.Title != "" || block.Sequence != "" {
+			t.Errorf("Expected the inline block without title or sequence, got %+v", block)
+		}
+		if got.Files[2].Title != "Second" {
+			t.Errorf("Expected the second file's title, got %q", got.Files[2].Title)
+		}
+	})
+
+	t.Run("unterminated_block", func(t *testing.T) {
+		badBundle := filepath.Join(tempDir, "bad.bundle.txt")
+		if err := os.WriteFile(badBundle, []byte("<<<END\nno closing delimiter"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		bp := NewBundleProcessor()
+		_, err := bp.ProcessBundleFileWithOptions(badBundle)
+		if err == nil || !strings.Contains(err.Error(), "unterminated inline block") {
+			t.Errorf("Expected unterminated inline block error, got %v", err)
+		}
+	})
+}
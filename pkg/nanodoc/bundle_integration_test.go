@@ -587,6 +587,20 @@ func TestParseBundleOptions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "upper_case_numbering",
+			optionLines: []string{
+				"--file-numbering roman-upper",
+			},
+			expectedOpts: FormattingOptions{
+				Theme:         "classic",
+				LineNumbers:   LineNumberNone,
+				ShowFilenames:   true,
+				HeaderFormat:   HeaderFormatNice,
+				SequenceStyle: SequenceRomanUpper,
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid_option",
 			optionLines: []string{
@@ -0,0 +1,121 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLiveGlobDirective(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"docs/b.md":        "B\n",
+		"docs/a.md":        "A [[glob:parts/*.txt]]\n",
+		"docs/parts/1.txt": "one",
+		"docs/c.txt":       "not matched\n",
+		"loop/self.md":     "[[glob:*.md]]",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Patterns are relative to the file holding the directive
+	source := filepath.Join(tempDir, "index.md")
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			// Matches are sorted, and nested patterns are relative to the match
+			name:     "expands sorted matches",
+			content:  "Start\n[[glob:docs/*.md]]\nEnd",
+			expected: "Start\nA one\nB\nEnd",
+		},
+		{
+			name:     "no match keeps the directive",
+			content:  "Start [[glob:docs/*.rst]] End",
+			expected: "Start [[glob:docs/*.rst]] End",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processLiveBundleRecursive(tt.content, source, 0, make(map[string]bool), false)
+			if err != nil {
+				t.Fatalf("processLiveBundleRecursive() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("a match including itself is circular", func(t *testing.T) {
+		_, err := processLiveBundleRecursive("[[glob:loop/*.md]]", source, 0, make(map[string]bool), false)
+		var circularErr *CircularDependencyError
+		if !errors.As(err, &circularErr) {
+			t.Fatalf("Expected a CircularDependencyError, got %v", err)
+		}
+	})
+}
+
+func TestLiveDirectivesMixed(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"docs/intro.md":      "Intro [[file:parts/one.txt:L1]]",
+		"docs/parts/one.txt": "one\ntwo",
+		"docs/twice.md":      "[[file:parts/one.txt]] [[glob:parts/*.txt]]",
+		"loop/a.md":          "A [[glob:b.md]]",
+		"loop/b.md":          "B [[file:a.md]]",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := filepath.Join(tempDir, "index.md")
+
+	t.Run("file paths are relative to the including file", func(t *testing.T) {
+		got, err := processLiveBundleRecursive("[[file:docs/intro.md]]", source, 0, make(map[string]bool), true)
+		if err != nil {
+			t.Fatalf("processLiveBundleRecursive() error = %v", err)
+		}
+		if got != "Intro one" {
+			t.Errorf("Expected %q, got %q", "Intro one", got)
+		}
+	})
+
+	t.Run("siblings may include the same file", func(t *testing.T) {
+		got, err := processLiveBundleRecursive("[[file:docs/twice.md]]", source, 0, make(map[string]bool), true)
+		if err != nil {
+			t.Fatalf("processLiveBundleRecursive() error = %v", err)
+		}
+		if got != "one\ntwo one\ntwo" {
+			t.Errorf("Expected the file twice, got %q", got)
+		}
+	})
+
+	t.Run("a cycle through both directives is circular", func(t *testing.T) {
+		_, err := processLiveBundleRecursive("[[file:loop/a.md]]", source, 0, make(map[string]bool), true)
+		var circularErr *CircularDependencyError
+		if !errors.As(err, &circularErr) {
+			t.Fatalf("Expected a CircularDependencyError, got %v", err)
+		}
+		if circularErr.Path != filepath.Join(tempDir, "loop", "a.md") {
+			t.Errorf("Expected the cycle to be found at a.md, got %q", circularErr.Path)
+		}
+	})
+}
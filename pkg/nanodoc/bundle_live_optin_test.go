@@ -0,0 +1,43 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessIncludesInReadme(t *testing.T) {
+	tempDir := t.TempDir()
+	snippet := filepath.Join(tempDir, "snippet.txt")
+	if err := os.WriteFile(snippet, []byte("included text"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(tempDir, "README.md")
+	if err := os.WriteFile(readme, []byte("Usage: [[file:"+snippet+"]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected string
+	}{
+		{"skipped by default", nil, "Usage: [[file:" + snippet + "]]"},
+		{"forced by file name", []string{"README.md"}, "Usage: included text"},
+		{"forced by path pattern", []string{"**/" + filepath.Base(tempDir) + "/README*"}, "Usage: included text"},
+		{"other patterns keep the skip", []string{"CHANGELOG.md"}, "Usage: [[file:" + snippet + "]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BuildDocument([]PathInfo{{Original: readme, Absolute: readme, Type: "file"}}, FormattingOptions{ProcessIncludesIn: tt.patterns})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+			if got := strings.TrimSpace(doc.ContentItems[0].Content); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
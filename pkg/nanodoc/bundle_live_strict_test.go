@@ -0,0 +1,45 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLiveBundleStrict(t *testing.T) {
+	tempDir := t.TempDir()
+	doc := filepath.Join(tempDir, "doc.txt")
+	if err := os.WriteFile(doc, []byte("See [[file:missing.txt]] and [[glob:*.rst]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pathInfos := []PathInfo{{Original: doc, Absolute: doc, Type: "file"}}
+
+	// Without --strict the directives are left as they are
+	built, err := BuildDocument(pathInfos, FormattingOptions{})
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+	if got := built.ContentItems[0].Content; !strings.Contains(got, "[[file:missing.txt]]") {
+		t.Errorf("Expected the directive to be kept, got %q", got)
+	}
+
+	_, err = BuildDocument(pathInfos, FormattingOptions{Strict: true})
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) {
+		t.Fatalf("Expected a FileError with --strict, got %v", err)
+	}
+	if fileErr.Path != doc || !strings.Contains(err.Error(), "[[file:missing.txt]]") {
+		t.Errorf("Expected the error to name %s and the directive, got %q", doc, err)
+	}
+
+	// A pattern matching nothing is unresolved too
+	if err := os.WriteFile(doc, []byte("See [[glob:*.rst]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = BuildDocument(pathInfos, FormattingOptions{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "[[glob:*.rst]]: no files match") {
+		t.Errorf("Expected an error for the unmatched glob, got %v", err)
+	}
+}
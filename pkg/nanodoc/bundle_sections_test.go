@@ -0,0 +1,173 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleCommentsAsSections(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"intro.txt":   "intro text",
+		"install.txt": "install text",
+		"project.bundle.txt": strings.Join([]string{
+			"# Project docs",
+			"# --- Options ---",
+			"--header-format filename",
+			"# --- Getting Started ---",
+			"intro.txt",
+			"# a plain note",
+			"# === Setup ===",
+			"install.txt",
+			"# --- Empty at the end ---",
+		}, "\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathInfos, err := ResolvePaths([]string{filepath.Join(tempDir, "project.bundle.txt")})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected []string
+	}{
+		{
+			name:     "comments ignored by default",
+			enabled:  false,
+			expected: []string{"intro text", "install text"},
+		},
+		{
+			name:     "decorated comments become sections",
+			enabled:  true,
+			expected: []string{"[Getting Started]", "intro text", "[Setup]", "install text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BuildDocument(pathInfos, FormattingOptions{BundleCommentsAsSections: tt.enabled})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+
+			var got []string
+			for _, item := range doc.ContentItems {
+				if item.SectionTitle != "" {
+					got = append(got, "["+item.SectionTitle+"]")
+				} else {
+					got = append(got, item.Content)
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected items %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderBundleCommentSection(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/project.bundle.txt#section-1", OriginalSource: "/docs/project.bundle.txt", SectionTitle: "Chapter 1"},
+			{Filepath: "/docs/intro.txt", Content: "intro text"},
+			{Filepath: "/docs/install.txt", Content: "install text"},
+		},
+		FormattingOptions: FormattingOptions{
+			ShowFilenames:   true,
+			HeaderFormat:    HeaderFormatFilename,
+			SequenceStyle:   SequenceNumerical,
+			HeaderAlignment: "left",
+			ShowFileIndex:   true,
+		},
+	}
+
+	tests := []struct {
+		outputFormat string
+		expected     string
+		unexpected   string
+	}{
+		{
+			outputFormat: "term",
+			expected:     "=========\nChapter 1\n=========\n\n1. intro.txt\n\nintro text\n\n2. install.txt",
+		},
+		{
+			outputFormat: "markdown",
+			expected:     "## Chapter 1",
+		},
+		{
+			outputFormat: "plain",
+			unexpected:   "Chapter 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outputFormat, func(t *testing.T) {
+			doc.FormattingOptions.OutputFormat = tt.outputFormat
+			ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+			result, err := RenderDocument(doc, ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if tt.expected != "" && !strings.Contains(result, tt.expected) {
+				t.Errorf("Expected %q in output, got:\n%s", tt.expected, result)
+			}
+			if tt.unexpected != "" && strings.Contains(result, tt.unexpected) {
+				t.Errorf("Did not expect %q in output, got:\n%s", tt.unexpected, result)
+			}
+			// Sections are not files, so the file index leaves them out
+			if strings.Contains(result, "project.bundle.txt") {
+				t.Errorf("Expected the section to stay out of the file index, got:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestPlainOutputSkipsBundleSections(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"intro.txt":   "intro text",
+		"install.txt": "install text",
+		"project.bundle.txt": strings.Join([]string{
+			"# --- Getting Started ---",
+			"intro.txt",
+			"# --- Setup ---",
+			"install.txt",
+		}, "\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathInfos, err := ResolvePaths([]string{filepath.Join(tempDir, "project.bundle.txt")})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	doc, err := BuildDocument(pathInfos, FormattingOptions{OutputFormat: "plain", BundleCommentsAsSections: true})
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+
+	result, err := RenderDocument(doc, &FormattingContext{})
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	// Sections add neither text nor blank lines to plain output
+	expected := "intro text\ninstall text\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
@@ -0,0 +1,107 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSortBundle(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"a.txt":              "a",
+		"b.txt":              "b",
+		"c.txt":              "c",
+		"first.txt":          "first",
+		"last.txt":           "last",
+		"reverse.bundle.txt": "c.txt\nb.txt\na.txt\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The bundle sits between two plain files, which must keep their place
+	pathInfos, err := ResolvePaths([]string{
+		filepath.Join(tempDir, "last.txt"),
+		filepath.Join(tempDir, "reverse.bundle.txt"),
+		filepath.Join(tempDir, "first.txt"),
+	})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		sort     bool
+		expected string
+	}{
+		{"declaration order by default", false, "last|c|b|a|first"},
+		{"sorted within the bundle", true, "last|a|b|c|first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BuildDocument(pathInfos, FormattingOptions{SortBundle: tt.sort})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+
+			var got []string
+			for _, item := range doc.ContentItems {
+				got = append(got, item.Content)
+			}
+			if strings.Join(got, "|") != tt.expected {
+				t.Errorf("Expected order %s, got %s", tt.expected, strings.Join(got, "|"))
+			}
+		})
+	}
+}
+
+func TestSortBundleKeepsSections(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"a.txt": "a",
+		"b.txt": "b",
+		"y.txt": "y",
+		"z.txt": "z",
+		"book.bundle.txt": strings.Join([]string{
+			"# --- Part 1 ---",
+			"z.txt",
+			"y.txt",
+			"# --- Part 2 ---",
+			"b.txt",
+			"a.txt",
+		}, "\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathInfos, err := ResolvePaths([]string{filepath.Join(tempDir, "book.bundle.txt")})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	doc, err := BuildDocument(pathInfos, FormattingOptions{SortBundle: true, BundleCommentsAsSections: true})
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+
+	var got []string
+	for _, item := range doc.ContentItems {
+		if item.SectionTitle != "" {
+			got = append(got, "["+item.SectionTitle+"]")
+		} else {
+			got = append(got, item.Content)
+		}
+	}
+	if want := "[Part 1]|y|z|[Part 2]|a|b"; strings.Join(got, "|") != want {
+		t.Errorf("Expected items %s, got %s", want, strings.Join(got, "|"))
+	}
+}
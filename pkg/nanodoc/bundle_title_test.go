@@ -0,0 +1,136 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleHeaderTitles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"intro.txt":   "intro text",
+		"a|b.txt":     "piped text",
+		"install.txt": "install text",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundleFile := filepath.Join(tempDir, "guide.bundle.txt")
+	bundleContent := strings.Join([]string{
+		"intro.txt | Getting Started",
+		"install.txt:L1 |   Setup  ",
+		"a|b.txt",
+	}, "\n")
+	if err := os.WriteFile(bundleFile, []byte(bundleContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("parses_titles", func(t *testing.T) {
+		bp := NewBundleProcessor()
+		result, err := bp.ProcessBundleFileWithOptions(bundleFile)
+		if err != nil {
+			t.Fatalf("ProcessBundleFileWithOptions() error = %v", err)
+		}
+
+		expectedPaths := []string{
+			filepath.Join(tempDir, "intro.txt"),
+			filepath.Join(tempDir, "install.txt:L1"),
+			filepath.Join(tempDir, "a|b.txt"),
+		}
+		if strings.Join(result.Paths, "\n") != strings.Join(expectedPaths, "\n") {
+			t.Fatalf("Expected paths %v, got %v", expectedPaths, result.Paths)
+		}
+		if got := bp.nextTitle(expectedPaths[0]); got != "Getting Started" {
+			t.Errorf("Expected title %q, got %q", "Getting Started", got)
+		}
+		if got := bp.nextTitle(expectedPaths[1]); got != "Setup" {
+			t.Errorf("Expected title %q, got %q", "Setup", got)
+		}
+		if got := bp.nextTitle(expectedPaths[2]); got != "" {
+			t.Errorf("Expected no title for a path with an unspaced |, got %q", got)
+		}
+	})
+
+	t.Run("headers_use_titles", func(t *testing.T) {
+		pathInfos, err := ResolvePaths([]string{bundleFile})
+		if err != nil {
+			t.Fatalf("ResolvePaths() error = %v", err)
+		}
+		options := FormattingOptions{
+			ShowFilenames: true,
+			HeaderFormat:  HeaderFormatFilename,
+			SequenceStyle: SequenceNumerical,
+		}
+		doc, err := BuildDocumentWithOptions(pathInfos, options)
+		if err != nil {
+			t.Fatalf("BuildDocumentWithOptions() error = %v", err)
+		}
+		ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+		result, err := RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		for _, want := range []string{"1. Getting Started\n", "2. Setup\n", "3. a|b.txt\n"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in output, got:\n%s", want, result)
+			}
+		}
+		if strings.Contains(result, "intro.txt") || strings.Contains(result, "install.txt") {
+			t.Errorf("Expected titles to replace the file names, got:\n%s", result)
+		}
+	})
+}
+
+func TestBundleHeaderTitlesForRepeatedFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for name, content := range map[string]string{"a.txt": "a text", "b.txt": "b text"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundleFile := filepath.Join(tempDir, "guide.bundle.txt")
+	bundleContent := strings.Join([]string{
+		"a.txt | First Look",
+		"b.txt",
+		"a.txt | Second Look",
+		"b.txt",
+		"a.txt",
+	}, "\n")
+	if err := os.WriteFile(bundleFile, []byte(bundleContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pathInfos, err := ResolvePaths([]string{bundleFile})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	options := FormattingOptions{
+		ShowFilenames: true,
+		HeaderFormat:  HeaderFormatFilename,
+		SequenceStyle: SequenceNumerical,
+	}
+	doc, err := BuildDocumentWithOptions(pathInfos, options)
+	if err != nil {
+		t.Fatalf("BuildDocumentWithOptions() error = %v", err)
+	}
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	for _, want := range []string{"1. First Look\n", "2. b.txt\n", "3. Second Look\n", "4. b.txt\n", "5. a.txt\n"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, result)
+		}
+	}
+}
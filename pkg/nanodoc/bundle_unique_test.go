@@ -0,0 +1,139 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildDocumentUniqueBy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"main/README.md":   "main readme",
+		"main/guide.md":    "main guide",
+		"vendor/README.md": "vendor readme",
+		"vendor/api.md":    "vendor api",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathInfos, err := ResolvePaths([]string{filepath.Join(tempDir, "main"), filepath.Join(tempDir, "vendor")})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		uniqueBy string
+		expected []string
+	}{
+		{
+			name:     "none",
+			uniqueBy: UniqueByNone,
+			expected: []string{"main readme", "main guide", "vendor readme", "vendor api"},
+		},
+		{
+			name:     "basename",
+			uniqueBy: UniqueByBasename,
+			expected: []string{"main readme", "main guide", "vendor api"},
+		},
+		{
+			name:     "dir",
+			uniqueBy: UniqueByDir,
+			expected: []string{"main readme", "vendor readme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BuildDocument(pathInfos, FormattingOptions{UniqueBy: tt.uniqueBy})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+
+			if len(doc.ContentItems) != len(tt.expected) {
+				t.Fatalf("Expected %d items, got %d", len(tt.expected), len(doc.ContentItems))
+			}
+			for i, want := range tt.expected {
+				if doc.ContentItems[i].Content != want {
+					t.Errorf("Item %d: expected %q, got %q", i, want, doc.ContentItems[i].Content)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDocumentOnDuplicate(t *testing.T) {
+	tempDir := t.TempDir()
+	docsDir := filepath.Join(tempDir, "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.md": "alpha\nmore alpha",
+		"b.md": "beta",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(docsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a.md directly, again through its directory, and once with a range
+	aPath := filepath.Join(docsDir, "a.md")
+	pathInfos, err := ResolvePaths([]string{aPath, docsDir, aPath + ":L1"})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		onDuplicate string
+		expected    []string
+	}{
+		{
+			name:        "keep",
+			onDuplicate: DuplicateKeep,
+			expected:    []string{"alpha\nmore alpha", "alpha\nmore alpha", "beta", "alpha"},
+		},
+		{
+			name:        "skip keeps the first copy and other ranges",
+			onDuplicate: DuplicateSkip,
+			expected:    []string{"alpha\nmore alpha", "beta", "alpha"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BuildDocument(pathInfos, FormattingOptions{OnDuplicate: tt.onDuplicate})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+
+			if len(doc.ContentItems) != len(tt.expected) {
+				t.Fatalf("Expected %d items, got %d", len(tt.expected), len(doc.ContentItems))
+			}
+			for i, want := range tt.expected {
+				if doc.ContentItems[i].Content != want {
+					t.Errorf("Item %d: expected %q, got %q", i, want, doc.ContentItems[i].Content)
+				}
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, err := BuildDocument(pathInfos, FormattingOptions{OnDuplicate: DuplicateError})
+		if !errors.Is(err, ErrDuplicateFile) {
+			t.Errorf("Expected ErrDuplicateFile, got %v", err)
+		}
+	})
+}
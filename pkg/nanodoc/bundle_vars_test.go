@@ -0,0 +1,120 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleVarSubstitution(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for _, dir := range []string{"v1", "v2"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"intro.txt", "usage.txt"} {
+			content := dir + " " + name
+			if err := os.WriteFile(filepath.Join(tempDir, dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	bundleFile := filepath.Join(tempDir, "release.bundle.txt")
+	bundleContent := strings.Join([]string{
+		"--file-numbering=${STYLE}",
+		"${VERSION}/intro.txt",
+		"${VERSION}/usage.txt",
+	}, "\n")
+	if err := os.WriteFile(bundleFile, []byte(bundleContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("substitutes_paths_and_options", func(t *testing.T) {
+		bp := NewBundleProcessor()
+		bp.vars = map[string]string{"VERSION": "v2", "STYLE": "roman"}
+
+		result, err := bp.ProcessBundleFileWithOptions(bundleFile)
+		if err != nil {
+			t.Fatalf("ProcessBundleFileWithOptions() error = %v", err)
+		}
+
+		expectedPaths := []string{
+			filepath.Join(tempDir, "v2", "intro.txt"),
+			filepath.Join(tempDir, "v2", "usage.txt"),
+		}
+		if len(result.Paths) != len(expectedPaths) {
+			t.Fatalf("Expected paths %v, got %v", expectedPaths, result.Paths)
+		}
+		for i := range expectedPaths {
+			if result.Paths[i] != expectedPaths[i] {
+				t.Errorf("Path %d: expected %s, got %s", i, expectedPaths[i], result.Paths[i])
+			}
+		}
+		if len(result.OptionLines) != 1 || result.OptionLines[0] != "--file-numbering=roman" {
+			t.Errorf("Expected substituted option line, got %v", result.OptionLines)
+		}
+	})
+
+	t.Run("builds_document", func(t *testing.T) {
+		pathInfos := []PathInfo{{Original: bundleFile, Absolute: bundleFile, Type: "bundle"}}
+		opts := FormattingOptions{BundleVars: map[string]string{"VERSION": "v1", "STYLE": "numerical"}}
+
+		doc, err := BuildDocument(pathInfos, opts)
+		if err != nil {
+			t.Fatalf("BuildDocument() error = %v", err)
+		}
+		if len(doc.ContentItems) != 2 {
+			t.Fatalf("Expected 2 content items, got %d", len(doc.ContentItems))
+		}
+		if doc.ContentItems[0].Content != "v1 intro.txt" || doc.ContentItems[1].Content != "v1 usage.txt" {
+			t.Errorf("Unexpected content: %q, %q", doc.ContentItems[0].Content, doc.ContentItems[1].Content)
+		}
+	})
+
+	t.Run("undefined_variable", func(t *testing.T) {
+		bp := NewBundleProcessor()
+		bp.vars = map[string]string{"STYLE": "roman"}
+
+		_, err := bp.ProcessBundleFileWithOptions(bundleFile)
+		if !errors.Is(err, ErrUndefinedVariable) {
+			t.Fatalf("Expected ErrUndefinedVariable, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "${VERSION}") || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("Expected error to name the variable and line, got %v", err)
+		}
+	})
+
+	t.Run("default_value", func(t *testing.T) {
+		defaultValue := "v1"
+		bp := NewBundleProcessor()
+		bp.varDefault = &defaultValue
+
+		result, err := bp.ProcessBundleFileWithOptions(bundleFile)
+		if err != nil {
+			t.Fatalf("ProcessBundleFileWithOptions() error = %v", err)
+		}
+		if result.Paths[0] != filepath.Join(tempDir, "v1", "intro.txt") {
+			t.Errorf("Expected default value to be used, got %v", result.Paths)
+		}
+	})
+}
+
+func TestParseBundleVars(t *testing.T) {
+	vars, err := ParseBundleVars([]string{"DIR=docs/v2", "EMPTY=", "EQ=a=b"})
+	if err != nil {
+		t.Fatalf("ParseBundleVars() error = %v", err)
+	}
+	if vars["DIR"] != "docs/v2" || vars["EMPTY"] != "" || vars["EQ"] != "a=b" {
+		t.Errorf("Unexpected vars: %v", vars)
+	}
+
+	for _, invalid := range []string{"NOVALUE", "=value", "1BAD=x", "with space=x"} {
+		if _, err := ParseBundleVars([]string{invalid}); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
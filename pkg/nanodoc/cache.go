@@ -0,0 +1,108 @@
+package nanodoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// fragmentCacheVersion is part of every cache key, so changing how fragments
+// are rendered invalidates the entries written by older versions
+const fragmentCacheVersion = "1"
+
+// fragmentRendered is called each time a fragment is rendered instead of read
+// from the cache; tests replace it to count cache misses
+var fragmentRendered = func(path string) {}
+
+// fragmentCache stores rendered term fragments on disk, one file per key.
+// A nil cache is disabled: it never hits and ignores writes.
+type fragmentCache struct {
+	dir string
+}
+
+// newFragmentCache opens the cache in dir, creating it if needed. An empty
+// dir returns a nil, disabled cache.
+func newFragmentCache(dir string) (*fragmentCache, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	return &fragmentCache{dir: dir}, nil
+}
+
+// fragmentKey hashes everything renderFragment depends on: the content, the
+// ranges it was extracted with, the file type and the options that shape it
+func fragmentKey(item FileContent, opts *FormattingOptions, syntax *syntaxStyles, plain bool) string {
+	// The page width only matters when paragraphs are rewrapped to it
+	reflowWidth := 0
+	if opts.Reflow {
+		reflowWidth = opts.PageWidth
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		fragmentCacheVersion,
+		strconv.FormatBool(isMarkdownFile(item.Filepath)),
+		FormatRanges(item.Ranges),
+		strconv.FormatBool(opts.RenderMarkdown),
+		strconv.FormatBool(opts.RenderMarkdownTables),
+		strconv.FormatBool(plain),
+		strconv.Itoa(reflowWidth),
+		strconv.Itoa(opts.WrapWidth),
+		opts.HighlightLines,
+		languageForFile(item.Filepath),
+		syntax.cacheKey(),
+		item.Content,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *fragmentCache) get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// put stores a fragment; failures only cost a cache miss on the next run.
+// Entries are renamed into place so a reader never sees a partial one.
+func (c *fragmentCache) put(key, fragment string) {
+	if c == nil {
+		return
+	}
+	if err := c.write(key, fragment); err != nil {
+		slog.Debug("Failed to write cache entry", "key", key, "error", err)
+	}
+}
+
+func (c *fragmentCache) write(key, fragment string) error {
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.WriteString(fragment); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, key))
+}
@@ -0,0 +1,72 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderFragmentCache(t *testing.T) {
+	rendered := 0
+	fragmentRendered = func(path string) { rendered++ }
+	defer func() { fragmentRendered = func(path string) {} }()
+
+	cacheDir := t.TempDir()
+	newDoc := func(guide, highlight string) *Document {
+		return &Document{
+			ContentItems: []FileContent{
+				{Filepath: "/docs/guide.md", Content: guide},
+				{Filepath: "/docs/notes.txt", Content: "one\ntwo"},
+			},
+			FormattingOptions: FormattingOptions{
+				OutputFormat:   "term",
+				RenderMarkdown: true,
+				HighlightLines: highlight,
+				CacheDir:       cacheDir,
+			},
+		}
+	}
+	render := func(doc *Document) string {
+		t.Helper()
+		rendered = 0
+		result, err := RenderDocument(doc, &FormattingContext{LineNumbers: LineNumberGlobal})
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		return result
+	}
+
+	first := render(newDoc("# Guide\n\n**bold**", "L1"))
+	if rendered != 2 {
+		t.Fatalf("Expected 2 fragments rendered on a cold cache, got %d", rendered)
+	}
+
+	if second := render(newDoc("# Guide\n\n**bold**", "L1")); second != first || rendered != 0 {
+		t.Errorf("Expected a full cache hit with the same output, rendered %d:\n%s", rendered, second)
+	}
+
+	render(newDoc("# Guide\n\nchanged", "L1"))
+	if rendered != 1 {
+		t.Errorf("Expected only the changed file to be rendered, got %d", rendered)
+	}
+
+	render(newDoc("# Guide\n\nchanged", "L2"))
+	if rendered != 2 {
+		t.Errorf("Expected an option change to invalidate every entry, got %d", rendered)
+	}
+}
+
+func TestRenderWithoutCacheDir(t *testing.T) {
+	rendered := 0
+	fragmentRendered = func(path string) { rendered++ }
+	defer func() { fragmentRendered = func(path string) {} }()
+
+	doc := &Document{
+		ContentItems:      []FileContent{{Filepath: "/docs/notes.txt", Content: "one"}},
+		FormattingOptions: FormattingOptions{OutputFormat: "term"},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := RenderDocument(doc, &FormattingContext{}); err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+	}
+	if rendered != 2 {
+		t.Errorf("Expected every render to miss without a cache dir, got %d", rendered)
+	}
+}
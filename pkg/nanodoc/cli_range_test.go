@@ -106,4 +106,38 @@ func TestCLIRangeSupport(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestCLIRepeatedRangesAreNotMerged(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("Line 1\nLine 2\nLine 3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same range given twice is kept twice, in argument order
+	pathInfos, err := ResolvePaths([]string{testFile + ":L1-2", testFile + ":L3", testFile + ":L1-2"})
+	if err != nil {
+		t.Fatalf("ResolvePaths error: %v", err)
+	}
+	doc, err := BuildDocument(pathInfos, FormattingOptions{})
+	if err != nil {
+		t.Fatalf("BuildDocument error: %v", err)
+	}
+
+	var got []string
+	for _, item := range doc.ContentItems {
+		got = append(got, item.Content)
+	}
+	expected := []string{"Line 1\nLine 2", "Line 3", "Line 1\nLine 2"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected blocks %q, got %q", expected, got)
+	}
+
+	result, err := RenderDocument(doc, &FormattingContext{})
+	if err != nil {
+		t.Fatalf("RenderDocument error: %v", err)
+	}
+	if result != "Line 1\nLine 2\nLine 3\nLine 1\nLine 2\n" {
+		t.Errorf("Expected the repeated range twice in the output, got %q", result)
+	}
+}
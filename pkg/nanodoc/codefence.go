@@ -0,0 +1,66 @@
+package nanodoc
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// codeLanguages maps file extensions to the language hint used in code fences
+var codeLanguages = map[string]string{
+	".go":   "go",
+	".py":   "python",
+	".js":   "javascript",
+	".ts":   "typescript",
+	".rb":   "ruby",
+	".rs":   "rust",
+	".java": "java",
+	".c":    "c",
+	".h":    "c",
+	".cpp":  "cpp",
+	".cs":   "csharp",
+	".sh":   "bash",
+	".bash": "bash",
+	".zsh":  "zsh",
+	".json": "json",
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".toml": "toml",
+	".xml":  "xml",
+	".html": "html",
+	".css":  "css",
+	".sql":  "sql",
+	".lua":  "lua",
+}
+
+// languageForFile infers the code language from a file's extension.
+// It returns an empty string when the extension is not known.
+func languageForFile(path string) string {
+	return codeLanguages[strings.ToLower(filepath.Ext(path))]
+}
+
+// fenceCodeContent wraps content in a fenced code block with an optional
+// language hint and a numberLines attribute. The fence is made longer than
+// any backtick run in the content so it cannot be closed early.
+func fenceCodeContent(content, language string, numberLines bool) string {
+	fenceLen := 3
+	run := 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run >= fenceLen {
+				fenceLen = run + 1
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", fenceLen)
+
+	info := language
+	if numberLines {
+		info = strings.TrimSpace(info + " {.numberLines}")
+	}
+
+	content = strings.TrimSuffix(content, "\n")
+	return fence + info + "\n" + content + "\n" + fence + "\n"
+}
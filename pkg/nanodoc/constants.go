@@ -6,6 +6,13 @@ var DefaultTextExtensions = []string{".txt", ".md"}
 // Bundle file pattern
 const BundlePattern = ".bundle."
 
+// IgnoreFileName is the file listing patterns to skip when scanning a directory
+const IgnoreFileName = ".nanodocignore"
+
+// InlineBlockPrefix starts an inline content block in a bundle file, e.g.
+// "<<<TEXT", which runs until a line containing only the delimiter "TEXT"
+const InlineBlockPrefix = "<<<"
+
 // LineNumberMode represents different line numbering modes
 type LineNumberMode int
 
@@ -18,6 +25,23 @@ const (
 	LineNumberGlobal
 )
 
+// String returns the --linenum value for the mode, or "none"
+func (m LineNumberMode) String() string {
+	switch m {
+	case LineNumberFile:
+		return "file"
+	case LineNumberGlobal:
+		return "global"
+	default:
+		return "none"
+	}
+}
+
+// MarshalText encodes the mode by name, e.g. in JSON output
+func (m LineNumberMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
 // HeaderFormat represents different header formats
 type HeaderFormat string
 
@@ -28,6 +52,8 @@ const (
 	HeaderFormatFilename HeaderFormat = "filename"
 	// HeaderFormatPath - full file path
 	HeaderFormatPath HeaderFormat = "path"
+	// HeaderFormatRelative - path relative to the common root of all files
+	HeaderFormatRelative HeaderFormat = "relative"
 )
 
 // SequenceStyle represents different sequence numbering styles
@@ -40,6 +66,97 @@ const (
 	SequenceLetter SequenceStyle = "letter"
 	// SequenceRoman - i, ii, iii...
 	SequenceRoman SequenceStyle = "roman"
+	// SequenceAlphabetical - the same as SequenceLetter, under the name
+	// --file-numbering documents
+	SequenceAlphabetical SequenceStyle = "alphabetical"
+	// SequenceLetterUpper - A, B, C...
+	SequenceLetterUpper SequenceStyle = "alpha-upper"
+	// SequenceRomanUpper - I, II, III...
+	SequenceRomanUpper SequenceStyle = "roman-upper"
+)
+
+// File index positions relative to the table of contents
+const (
+	// FileIndexBeforeTOC - file index is rendered before the TOC
+	FileIndexBeforeTOC = "before-toc"
+	// FileIndexAfterTOC - file index is rendered after the TOC
+	FileIndexAfterTOC = "after-toc"
+)
+
+// Overflow modes for content lines wider than the page
+const (
+	// OverflowNone - long lines are left untouched
+	OverflowNone = "none"
+	// OverflowTruncate - long lines are cut to the page width with an ellipsis
+	OverflowTruncate = "truncate"
+	// OverflowWrap - long lines are broken across multiple lines
+	OverflowWrap = "wrap"
+)
+
+// Scopes limiting which files get line numbers
+const (
+	// LineNumberScopeAll - every file is numbered
+	LineNumberScopeAll = "all"
+	// LineNumberScopeCode - only source files (not .txt or markdown) are numbered
+	LineNumberScopeCode = "code"
+	// LineNumberScopeText - only .txt and markdown files are numbered
+	LineNumberScopeText = "text"
+)
+
+// Modes for deduplicating resolved files
+const (
+	// UniqueByNone - every resolved file is included
+	UniqueByNone = "none"
+	// UniqueByBasename - only the first file with a given name is included
+	UniqueByBasename = "basename"
+	// UniqueByDir - only the alphabetically first file of each directory is included
+	UniqueByDir = "dir"
+)
+
+// Ways to handle a file included more than once
+const (
+	// DuplicateKeep - every copy is rendered
+	DuplicateKeep = "keep"
+	// DuplicateSkip - only the first copy is rendered
+	DuplicateSkip = "skip"
+	// DuplicateError - a second copy stops the run
+	DuplicateError = "error"
+)
+
+// Where --dedupe-lines looks for earlier copies of a line
+const (
+	// DedupeScopeDocument - a line is dropped if any earlier file had it
+	DedupeScopeDocument = "document"
+	// DedupeScopeFile - a line is only dropped if earlier in the same file
+	DedupeScopeFile = "file"
+)
+
+// When term output may contain ANSI escape sequences
+const (
+	// ColorAuto - escape sequences only when writing to a terminal
+	ColorAuto = "auto"
+	// ColorAlways - escape sequences even when the output is piped or saved
+	ColorAlways = "always"
+	// ColorNever - no escape sequences, whatever the theme
+	ColorNever = "never"
+)
+
+// Ways to handle files larger than --max-file-size
+const (
+	// OversizeSkip - oversized files are left out with a warning
+	OversizeSkip = "skip"
+	// OversizeError - an oversized file stops the run
+	OversizeError = "error"
+)
+
+// Points where file header numbering starts over at 1
+const (
+	// FileNumberResetNone - files are numbered through the whole document
+	FileNumberResetNone = "none"
+	// FileNumberResetBundle - numbering restarts with the files of each bundle
+	FileNumberResetBundle = "bundle"
+	// FileNumberResetDir - numbering restarts when the directory changes
+	FileNumberResetDir = "dir"
 )
 
 // Default theme names
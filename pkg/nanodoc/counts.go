@@ -0,0 +1,111 @@
+package nanodoc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ContentCounts tallies the files and lines of a document's content
+type ContentCounts struct {
+	Files int
+	Lines int
+	// Per-extension tallies keyed by lower-case extension with its dot, or
+	// "(none)" for files without one
+	ByExt map[string]*ExtCount
+}
+
+// ExtCount tallies the files and lines sharing one extension
+type ExtCount struct {
+	Files int
+	Lines int
+}
+
+// CountContent counts the files and lines of the document's content items.
+// Bundle comment sections are not files and are left out.
+func CountContent(doc *Document) *ContentCounts {
+	counts := &ContentCounts{ByExt: make(map[string]*ExtCount)}
+	for _, item := range doc.ContentItems {
+		if item.SectionTitle != "" {
+			continue
+		}
+
+		lines := countContentLines(item.Content)
+		counts.Files++
+		counts.Lines += lines
+
+		ext := contentExt(item)
+		if counts.ByExt[ext] == nil {
+			counts.ByExt[ext] = &ExtCount{}
+		}
+		counts.ByExt[ext].Files++
+		counts.ByExt[ext].Lines += lines
+	}
+	return counts
+}
+
+// countContentLines counts lines the way they are rendered: a final newline
+// does not start another line, and empty content has none
+func countContentLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+}
+
+// contentExt returns the extension a content item is counted under. Inline
+// blocks have synthetic paths and count as having none.
+func contentExt(item FileContent) string {
+	ext := strings.ToLower(filepath.Ext(item.Filepath))
+	if ext == "" || item.OriginalSource != "" {
+		return "(none)"
+	}
+	return ext
+}
+
+// FormatCountOutput formats the totals and, with byExt, one aligned row per
+// extension, most lines first
+func FormatCountOutput(counts *ContentCounts, byExt bool) string {
+	var output strings.Builder
+
+	if byExt && len(counts.ByExt) > 0 {
+		exts := make([]string, 0, len(counts.ByExt))
+		extWidth := 0
+		for ext := range counts.ByExt {
+			exts = append(exts, ext)
+			extWidth = max(extWidth, len(ext))
+		}
+		sort.Slice(exts, func(i, j int) bool {
+			a, b := counts.ByExt[exts[i]], counts.ByExt[exts[j]]
+			if a.Lines != b.Lines {
+				return a.Lines > b.Lines
+			}
+			return exts[i] < exts[j]
+		})
+
+		filesWidth := len(fmt.Sprint(counts.Files))
+		linesWidth := len(fmt.Sprint(counts.Lines))
+		for _, ext := range exts {
+			c := counts.ByExt[ext]
+			output.WriteString(fmt.Sprintf("%-*s  %*d %-5s  %*d %s\n",
+				extWidth, ext,
+				filesWidth, c.Files, plural(c.Files, "file", "files"),
+				linesWidth, c.Lines, plural(c.Lines, "line", "lines")))
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString(fmt.Sprintf("Total: %d %s, %d %s\n",
+		counts.Files, plural(counts.Files, "file", "files"),
+		counts.Lines, plural(counts.Lines, "line", "lines")))
+	return output.String()
+}
+
+// plural picks the singular or plural form of a word for n
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,37 @@
+package nanodoc
+
+import "testing"
+
+func TestCountContentByExt(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/guide.md", Content: "# Guide\n\ntext\n"},
+			{Filepath: "/docs/api.MD", Content: "# API"},
+			{Filepath: "/docs/notes.txt", Content: "one\ntwo"},
+			{Filepath: "/docs/empty.txt", Content: ""},
+			{Filepath: "/docs/book.bundle.txt#section-1", OriginalSource: "/docs/book.bundle.txt", SectionTitle: "Part 1"},
+		},
+	}
+
+	counts := CountContent(doc)
+	if counts.Files != 4 || counts.Lines != 6 {
+		t.Errorf("Totals = %d files, %d lines, want 4 files, 6 lines", counts.Files, counts.Lines)
+	}
+	if md := counts.ByExt[".md"]; md == nil || md.Files != 2 || md.Lines != 4 {
+		t.Errorf(".md tally = %+v, want 2 files, 4 lines", md)
+	}
+	if txt := counts.ByExt[".txt"]; txt == nil || txt.Files != 2 || txt.Lines != 2 {
+		t.Errorf(".txt tally = %+v, want 2 files, 2 lines", txt)
+	}
+
+	want := ".md   2 files  4 lines\n" +
+		".txt  2 files  2 lines\n" +
+		"\n" +
+		"Total: 4 files, 6 lines\n"
+	if got := FormatCountOutput(counts, true); got != want {
+		t.Errorf("FormatCountOutput() = %q, want %q", got, want)
+	}
+	if got := FormatCountOutput(counts, false); got != "Total: 4 files, 6 lines\n" {
+		t.Errorf("FormatCountOutput() without --by-ext = %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package nanodoc
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,17 +22,23 @@ type DryRunInfo struct {
 	TotalLines int
 	// Files requiring additional extensions
 	RequiresExtension map[string]string
+	// Sizes of the files over --max-file-size, which are not counted
+	Oversized map[string]int64
+	// Extensions of the directory and glob files left out as not text, as
+	// found by FindSkippedFiles; nil when not looked for
+	SkippedByExtension map[string]string
 	// Active formatting options
 	Options FormattingOptions
 }
 
 // FileInfo contains dry run information about a file
 type FileInfo struct {
-	Path      string
-	Source    string // Where it came from (directory, bundle, etc.)
-	Extension string
-	LineCount int    // Number of lines that will be processed
-	RangeSpec string // Range specification if any (e.g., "L10-20")
+	Path      string  `json:"path"`
+	Source    string  `json:"source"` // Where it came from (directory, bundle, etc.)
+	Extension string  `json:"extension"`
+	LineCount int     `json:"line_count"`           // Number of lines that will be processed
+	RangeSpec string  `json:"range_spec,omitempty"` // Range specification if any (e.g., "L10-20")
+	Ranges    []Range `json:"ranges,omitempty"`     // RangeSpec resolved against the file's lines
 }
 
 // GenerateDryRunInfo analyzes what files would be processed without actually processing them
@@ -40,6 +47,7 @@ func GenerateDryRunInfo(pathInfos []PathInfo, opts FormattingOptions) (*DryRunIn
 		Files:             make([]FileInfo, 0),
 		Bundles:           make([]string, 0),
 		RequiresExtension: make(map[string]string),
+		Oversized:         make(map[string]int64),
 		Options:           opts,
 	}
 
@@ -58,54 +66,75 @@ func GenerateDryRunInfo(pathInfos []PathInfo, opts FormattingOptions) (*DryRunIn
 				RangeSpec: rangeSpec,
 			}
 			
+			if info.skipOversized(pathInfo.Original, pathInfo.Absolute) {
+				continue
+			}
+
 			// Count lines in the file
-			lineCount, err := countFileLines(pathInfo.Original)
+			lineCount, ranges, err := countFileLines(pathInfo.Original)
 			if err != nil {
 				return nil, err
 			}
 			fileInfo.LineCount = lineCount
+			fileInfo.Ranges = ranges
 			info.TotalLines += lineCount
 			
-			// Check if file needs additional extension
-			if !isTextFileWithExtensions(pathInfo.Absolute, opts.AdditionalExtensions) {
+			if isStdinPath(pathInfo.Original) {
+				fileInfo.Source = "standard input"
+			} else if !isTextFileWithExtensions(pathInfo.Absolute, opts.AdditionalExtensions) {
+				// The file needs an additional extension
 				info.RequiresExtension[pathInfo.Absolute] = ext
 			}
 			
 			info.Files = append(info.Files, fileInfo)
 			
 		case "directory":
-			for _, file := range pathInfo.Files {
+			for _, fileWithRange := range pathInfoFiles(pathInfo) {
+				file, rangeSpec := parsePathWithRange(fileWithRange)
 				fileInfo := FileInfo{
 					Path:      file,
 					Source:    fmt.Sprintf("directory: %s", pathInfo.Original),
 					Extension: filepath.Ext(file),
+					RangeSpec: rangeSpec,
 				}
 				
+				if info.skipOversized(file, file) {
+					continue
+				}
+
 				// Count lines in the file
-				lineCount, err := countFileLines(file)
+				lineCount, ranges, err := countFileLines(fileWithRange)
 				if err != nil {
 					return nil, err
 				}
 				fileInfo.LineCount = lineCount
+				fileInfo.Ranges = ranges
 				info.TotalLines += lineCount
 				
 				info.Files = append(info.Files, fileInfo)
 			}
 			
 		case "glob":
-			for _, file := range pathInfo.Files {
+			for _, fileWithRange := range pathInfoFiles(pathInfo) {
+				file, rangeSpec := parsePathWithRange(fileWithRange)
 				fileInfo := FileInfo{
 					Path:      file,
 					Source:    fmt.Sprintf("glob: %s", pathInfo.Original),
 					Extension: filepath.Ext(file),
+					RangeSpec: rangeSpec,
 				}
 				
+				if info.skipOversized(file, file) {
+					continue
+				}
+
 				// Count lines in the file
-				lineCount, err := countFileLines(file)
+				lineCount, ranges, err := countFileLines(fileWithRange)
 				if err != nil {
 					return nil, err
 				}
 				fileInfo.LineCount = lineCount
+				fileInfo.Ranges = ranges
 				info.TotalLines += lineCount
 				
 				info.Files = append(info.Files, fileInfo)
@@ -114,7 +143,7 @@ func GenerateDryRunInfo(pathInfos []PathInfo, opts FormattingOptions) (*DryRunIn
 		case "bundle":
 			info.Bundles = append(info.Bundles, pathInfo.Absolute)
 			// For dry run, we need to process bundle contents to count lines
-			bp := NewBundleProcessor()
+			bp := newBundleProcessorForOptions(opts)
 			bundlePaths, err := bp.ProcessBundleFile(pathInfo.Absolute)
 			if err != nil {
 				return nil, err
@@ -132,13 +161,18 @@ func GenerateDryRunInfo(pathInfos []PathInfo, opts FormattingOptions) (*DryRunIn
 				_, rangeSpec := parsePathWithRange(bundlePath)
 				fileInfo.RangeSpec = rangeSpec
 				
+				if info.skipOversized(bundlePath, bundlePath) {
+					continue
+				}
+
 				// Count lines in the file
-				lineCount, err := countFileLines(bundlePath)
+				lineCount, ranges, err := countFileLines(bundlePath)
 				if err != nil {
 					// Skip files that can't be read
 					continue
 				}
 				fileInfo.LineCount = lineCount
+				fileInfo.Ranges = ranges
 				info.TotalLines += lineCount
 				
 				info.Files = append(info.Files, fileInfo)
@@ -168,6 +202,119 @@ func GenerateDryRunInfo(pathInfos []PathInfo, opts FormattingOptions) (*DryRunIn
 	return info, nil
 }
 
+// FindSkippedFiles returns the files the directories and globs in pathInfos
+// matched but left out for their extension, mapped to that extension.
+// options should be the ones the paths were resolved with.
+func FindSkippedFiles(pathInfos []PathInfo, options *FormattingOptions) (map[string]string, error) {
+	skipped := make(map[string]string)
+	for _, pathInfo := range pathInfos {
+		var candidates []string
+		var err error
+		switch pathInfo.Type {
+		case "directory":
+			candidates, err = findDirectoryCandidates(pathInfo.Absolute, options)
+		case "glob":
+			candidates, err = findGlobCandidates(pathInfo.Original, options)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		included := make(map[string]bool, len(pathInfo.Files))
+		for _, file := range pathInfo.Files {
+			included[file] = true
+		}
+		for _, file := range candidates {
+			if !included[file] {
+				skipped[file] = filepath.Ext(file)
+			}
+		}
+	}
+	return skipped, nil
+}
+
+// findDirectoryCandidates lists the files of dir that resolution would
+// consider, applying the include and exclude patterns but not the extensions
+func findDirectoryCandidates(dir string, options *FormattingOptions) ([]string, error) {
+	var matcher *PatternMatcher
+	if options != nil && (len(options.IncludePatterns) > 0 || len(options.ExcludePatterns) > 0) {
+		matcher = NewPatternMatcher(dir, options.IncludePatterns, options.ExcludePatterns)
+	}
+
+	var files []string
+	keep := func(path string) error {
+		if matcher != nil {
+			include, err := matcher.ShouldInclude(path)
+			if err != nil || !include {
+				return err
+			}
+		}
+		files = append(files, path)
+		return nil
+	}
+
+	if matcher != nil && matcher.NeedsRecursion() {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			return keep(path)
+		})
+		return files, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := keep(filepath.Join(dir, entry.Name())); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// findGlobCandidates lists the files pattern matches, whatever their extension
+func findGlobCandidates(pattern string, options *FormattingOptions) ([]string, error) {
+	pattern, _ = parsePathWithRange(pattern)
+	if options != nil && options.GlobBase != "" && !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(options.GlobBase, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, match := range matches {
+		absPath, err := filepath.Abs(match)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+			files = append(files, absPath)
+		}
+	}
+	return files, nil
+}
+
+// skipOversized records the file at pathWithRange under path in
+// info.Oversized, and reports true, when it is over --max-file-size. Its
+// lines are not counted, since the run would not read it.
+func (info *DryRunInfo) skipOversized(pathWithRange, path string) bool {
+	size, over := fileOverSize(pathWithRange, info.Options.MaxFileSize)
+	if over {
+		info.Oversized[path] = size
+	}
+	return over
+}
+
 // FormatDryRunOutput formats the dry run information for display
 func FormatDryRunOutput(info *DryRunInfo) string {
 	var output strings.Builder
@@ -206,7 +353,9 @@ func FormatDryRunOutput(info *DryRunInfo) string {
 		
 		for _, file := range files {
 			relPath := filepath.Base(file.Path)
-			if file.RangeSpec != "" {
+			if len(file.Ranges) > 0 {
+				relPath = fmt.Sprintf("%s:%s", relPath, FormatRanges(file.Ranges))
+			} else if file.RangeSpec != "" {
 				relPath = fmt.Sprintf("%s:%s", relPath, file.RangeSpec)
 			}
 			output.WriteString(fmt.Sprintf("%d. %s (%d lines)\n", fileNum, relPath, file.LineCount))
@@ -225,12 +374,59 @@ func FormatDryRunOutput(info *DryRunInfo) string {
 	// Show files requiring extensions
 	if len(info.RequiresExtension) > 0 {
 		output.WriteString("\nFiles requiring --ext flag:\n")
-		for file, ext := range info.RequiresExtension {
+		
+		// Sort paths for consistent output
+		paths := make([]string, 0, len(info.RequiresExtension))
+		for file := range info.RequiresExtension {
+			paths = append(paths, file)
+		}
+		sort.Strings(paths)
+		
+		for _, file := range paths {
 			output.WriteString(fmt.Sprintf("  - %s (requires --ext=%s)\n", 
-				filepath.Base(file), strings.TrimPrefix(ext, ".")))
+				filepath.Base(file), strings.TrimPrefix(info.RequiresExtension[file], ".")))
 		}
 	}
 	
+	// Show directory and glob files left out for their extension
+	if len(info.SkippedByExtension) > 0 {
+		output.WriteString("\nFiles skipped for their extension:\n")
+
+		paths := make([]string, 0, len(info.SkippedByExtension))
+		for file := range info.SkippedByExtension {
+			paths = append(paths, file)
+		}
+		sort.Strings(paths)
+
+		for _, file := range paths {
+			ext := strings.TrimPrefix(info.SkippedByExtension[file], ".")
+			hint := "no extension, cannot be added"
+			if ext != "" {
+				hint = fmt.Sprintf("include with --ext=%s", ext)
+			}
+			output.WriteString(fmt.Sprintf("  - %s (%s)\n", filepath.Base(file), hint))
+		}
+	}
+
+	// Show files over the size limit
+	if len(info.Oversized) > 0 {
+		outcome := "would be skipped"
+		if info.Options.OnOversize == OversizeError {
+			outcome = "would stop the run"
+		}
+		output.WriteString(fmt.Sprintf("\nFiles over --max-file-size %s (%s):\n", formatFileSize(info.Options.MaxFileSize), outcome))
+
+		paths := make([]string, 0, len(info.Oversized))
+		for file := range info.Oversized {
+			paths = append(paths, file)
+		}
+		sort.Strings(paths)
+
+		for _, file := range paths {
+			output.WriteString(fmt.Sprintf("  - %s (%s)\n", filepath.Base(file), formatFileSize(info.Oversized[file])))
+		}
+	}
+
 	// Summary
 	output.WriteString(fmt.Sprintf("\nTotal files to process: %d (%d lines)\n", info.TotalFiles, info.TotalLines))
 	
@@ -258,6 +454,13 @@ func FormatDryRunOutput(info *DryRunInfo) string {
 	if info.Options.HeaderFormat != "nice" {
 		activeOptions = append(activeOptions, fmt.Sprintf("--header-format %s", info.Options.HeaderFormat))
 	}
+	// Patterns have already filtered the files listed above
+	for _, pattern := range info.Options.IncludePatterns {
+		activeOptions = append(activeOptions, fmt.Sprintf("--include %q", pattern))
+	}
+	for _, pattern := range info.Options.ExcludePatterns {
+		activeOptions = append(activeOptions, fmt.Sprintf("--exclude %q", pattern))
+	}
 	
 	if len(activeOptions) > 0 {
 		output.WriteString("\nOptions:\n")
@@ -269,6 +472,13 @@ func FormatDryRunOutput(info *DryRunInfo) string {
 	return output.String()
 }
 
+// FormatCountSummary formats the dry run totals as a single line, for a quick
+// look at a bundle's size without the file listing
+func FormatCountSummary(info *DryRunInfo) string {
+	return fmt.Sprintf("%d files, %d lines, %d bundles, %d files requiring --ext\n",
+		info.TotalFiles, info.TotalLines, len(info.Bundles), len(info.RequiresExtension))
+}
+
 // Helper function to check if slice contains string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -333,13 +543,53 @@ func formatFileSize(size int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
 
-// countFileLines counts the number of lines in a file, respecting line ranges
-func countFileLines(pathWithRange string) (int, error) {
-	path, rangeSpec := parsePathWithRange(pathWithRange)
-	
-	file, err := os.Open(path)
+// ResolvePlan is the machine-readable form of a run that stops before
+// rendering: the merged options, the resolved files and the bundles found
+type ResolvePlan struct {
+	Options FormattingOptions `json:"options"`
+	Files   []FileInfo        `json:"files"`
+	Bundles []string          `json:"bundles"`
+}
+
+// GenerateResolvePlan resolves what would be rendered with the given merged
+// options, reusing the dry run analysis for files and bundles
+func GenerateResolvePlan(pathInfos []PathInfo, opts FormattingOptions) (*ResolvePlan, error) {
+	info, err := GenerateDryRunInfo(pathInfos, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvePlan{Options: opts, Files: info.Files, Bundles: info.Bundles}, nil
+}
+
+// FormatResolvePlan encodes the plan as indented JSON
+func FormatResolvePlan(plan *ResolvePlan) (string, error) {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// countFileLines counts the number of lines in a file, respecting line ranges.
+// It also returns the ranges, resolved against the file, when there are any.
+func countFileLines(pathWithRange string) (int, []Range, error) {
+	path, rangeSpec, err := splitRangeSpec(pathWithRange)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Byte ranges count the lines of the bytes they select
+	if isByteRangeSpec(rangeSpec) {
+		content, err := extractByteRanges(path, rangeSpec)
+		if err != nil {
+			return 0, nil, err
+		}
+		return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1, nil, nil
+	}
+
+	file, err := openSource(path)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	defer func() {
 		_ = file.Close()
@@ -353,9 +603,9 @@ func countFileLines(pathWithRange string) (int, error) {
 			lineCount++
 		}
 		if err := scanner.Err(); err != nil {
-			return 0, err
+			return 0, nil, err
 		}
-		return lineCount, nil
+		return lineCount, nil, nil
 	}
 	
 	// Parse range specification
@@ -365,12 +615,12 @@ func countFileLines(pathWithRange string) (int, error) {
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	
 	ranges, err := parseRanges(rangeSpec, len(lines))
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	
 	// Count lines in all ranges
@@ -379,5 +629,5 @@ func countFileLines(pathWithRange string) (int, error) {
 		totalLines += r.End - r.Start + 1
 	}
 	
-	return totalLines, nil
+	return totalLines, ranges, nil
 }
\ No newline at end of file
@@ -203,6 +203,134 @@ func TestFormatDryRunOutput(t *testing.T) {
 	}
 }
 
+func TestFormatCountSummary(t *testing.T) {
+	info := &DryRunInfo{
+		Files: []FileInfo{
+			{Path: "/tmp/file1.txt", Source: "direct argument", Extension: ".txt", LineCount: 10},
+			{Path: "/tmp/script.py", Source: "bundle: test.bundle.txt", Extension: ".py", LineCount: 15},
+		},
+		Bundles:           []string{"/tmp/test.bundle.txt"},
+		TotalFiles:        2,
+		TotalLines:        27,
+		RequiresExtension: map[string]string{"/tmp/script.py": ".py"},
+	}
+
+	want := "2 files, 27 lines, 1 bundles, 1 files requiring --ext\n"
+	if got := FormatCountSummary(info); got != want {
+		t.Errorf("FormatCountSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatDryRunOutputShowsPatterns(t *testing.T) {
+	info := &DryRunInfo{
+		Options: FormattingOptions{
+			Theme:           "classic",
+			ShowFilenames:   true,
+			SequenceStyle:   SequenceNumerical,
+			HeaderFormat:    HeaderFormatNice,
+			IncludePatterns: []string{"**/*.md", "docs/**"},
+			ExcludePatterns: []string{"**/draft-*"},
+		},
+	}
+
+	output := FormatDryRunOutput(info)
+
+	expected := "\nOptions:\n" +
+		"  --include \"**/*.md\"\n" +
+		"  --include \"docs/**\"\n" +
+		"  --exclude \"**/draft-*\"\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected the patterns in the options section:\n%s\nGot:\n%s", expected, output)
+	}
+
+	info.Options.IncludePatterns = nil
+	info.Options.ExcludePatterns = nil
+	if output := FormatDryRunOutput(info); strings.Contains(output, "Options:") {
+		t.Errorf("Expected no options section without patterns, got:\n%s", output)
+	}
+}
+
+func TestFormatDryRunOutputSortsRequiredExtensions(t *testing.T) {
+	info := &DryRunInfo{
+		RequiresExtension: map[string]string{
+			"/tmp/zeta.py":   ".py",
+			"/tmp/alpha.sh":  ".sh",
+			"/tmp/mid.go":    ".go",
+			"/tmp/beta.rb":   ".rb",
+			"/tmp/omega.lua": ".lua",
+		},
+	}
+
+	expected := "Files requiring --ext flag:\n" +
+		"  - alpha.sh (requires --ext=sh)\n" +
+		"  - beta.rb (requires --ext=rb)\n" +
+		"  - mid.go (requires --ext=go)\n" +
+		"  - omega.lua (requires --ext=lua)\n" +
+		"  - zeta.py (requires --ext=py)\n"
+
+	// Run several times so random map ordering would show up
+	for i := 0; i < 10; i++ {
+		output := FormatDryRunOutput(info)
+		if !strings.Contains(output, expected) {
+			t.Fatalf("Expected sorted extension section:\n%s\nGot:\n%s", expected, output)
+		}
+	}
+}
+
+func TestFindSkippedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	for name, content := range map[string]string{
+		"notes.txt": "notes",
+		"main.rs":   "fn main() {}",
+		"Makefile":  "all:",
+	} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathInfos, err := ResolvePaths([]string{tempDir})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	skipped, err := FindSkippedFiles(pathInfos, &FormattingOptions{})
+	if err != nil {
+		t.Fatalf("FindSkippedFiles() error = %v", err)
+	}
+
+	want := map[string]string{
+		filepath.Join(tempDir, "main.rs"):  ".rs",
+		filepath.Join(tempDir, "Makefile"): "",
+	}
+	if fmt.Sprint(skipped) != fmt.Sprint(want) {
+		t.Errorf("FindSkippedFiles() = %v, want %v", skipped, want)
+	}
+
+	info, err := GenerateDryRunInfo(pathInfos, FormattingOptions{})
+	if err != nil {
+		t.Fatalf("GenerateDryRunInfo() error = %v", err)
+	}
+	info.SkippedByExtension = skipped
+	output := FormatDryRunOutput(info)
+	expected := "Files skipped for their extension:\n  - Makefile (no extension, cannot be added)\n  - main.rs (include with --ext=rs)\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected the skipped files in the output, got:\n%s", output)
+	}
+
+	// With the extension added the file is no longer skipped
+	pathInfos, err = ResolvePathsWithOptions([]string{tempDir}, &FormattingOptions{AdditionalExtensions: []string{"rs"}})
+	if err != nil {
+		t.Fatalf("ResolvePathsWithOptions() error = %v", err)
+	}
+	skipped, err = FindSkippedFiles(pathInfos, &FormattingOptions{AdditionalExtensions: []string{"rs"}})
+	if err != nil {
+		t.Fatalf("FindSkippedFiles() error = %v", err)
+	}
+	if _, ok := skipped[filepath.Join(tempDir, "main.rs")]; ok {
+		t.Errorf("Expected main.rs to be included with --ext=rs, got skipped %v", skipped)
+	}
+}
+
 func TestDryRunWithCircularBundle(t *testing.T) {
 	// This test is no longer valid as bundles must contain bundle files
 	// The BundleProcessor will treat the circular references as regular files
@@ -274,6 +402,7 @@ func TestDryRunWithLineRanges(t *testing.T) {
 		name           string
 		pathInfos      []PathInfo
 		wantTotalLines int
+		wantRanges     string
 	}{
 		{
 			name: "file with line range L2-4",
@@ -285,6 +414,7 @@ func TestDryRunWithLineRanges(t *testing.T) {
 				},
 			},
 			wantTotalLines: 3,
+			wantRanges:     "L2-4",
 		},
 		{
 			name: "file with single line L5",
@@ -296,6 +426,7 @@ func TestDryRunWithLineRanges(t *testing.T) {
 				},
 			},
 			wantTotalLines: 1,
+			wantRanges:     "L5",
 		},
 		{
 			name: "file with open range L8-",
@@ -307,6 +438,7 @@ func TestDryRunWithLineRanges(t *testing.T) {
 				},
 			},
 			wantTotalLines: 3, // Lines 8, 9, 10
+			wantRanges:     "L8-10",
 		},
 	}
 
@@ -325,6 +457,14 @@ func TestDryRunWithLineRanges(t *testing.T) {
 			if len(info.Files) > 0 && !strings.Contains(tt.pathInfos[0].Original, info.Files[0].RangeSpec) {
 				t.Errorf("RangeSpec not preserved in file info")
 			}
+
+			// The display shows the ranges resolved against the file
+			if got := FormatRanges(info.Files[0].Ranges); got != tt.wantRanges {
+				t.Errorf("Ranges = %q, want %q", got, tt.wantRanges)
+			}
+			if output := FormatDryRunOutput(info); !strings.Contains(output, "test.txt:"+tt.wantRanges+" (") {
+				t.Errorf("Expected %q in the dry run output, got:\n%s", "test.txt:"+tt.wantRanges, output)
+			}
 		})
 	}
 }
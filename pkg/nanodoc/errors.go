@@ -16,11 +16,26 @@ var (
 	// ErrInvalidRange is returned when a line range is invalid
 	ErrInvalidRange = errors.New("invalid line range (see: nanodoc topics content)")
 
+	// ErrMixedRanges is returned when a path combines line and byte ranges
+	ErrMixedRanges = errors.New("line (L) and byte (B) ranges cannot be combined")
+
 	// ErrEmptySource is returned when no source files are provided
 	ErrEmptySource = errors.New("no source files provided")
 
 	// ErrInvalidTheme is returned when a theme cannot be loaded
 	ErrInvalidTheme = errors.New("invalid or missing theme (see: nanodoc topics themes)")
+
+	// ErrFileTooLarge is returned when a file is larger than --max-file-size
+	ErrFileTooLarge = errors.New("file exceeds --max-file-size")
+
+	// ErrDuplicateFile is returned for a file included twice with --on-duplicate error
+	ErrDuplicateFile = errors.New("file is included more than once (see --on-duplicate)")
+
+	// ErrGitFiles is returned when git cannot list files, e.g. outside a repository
+	ErrGitFiles = errors.New("git could not list files")
+
+	// ErrUndefinedVariable is returned when a bundle uses a variable that was not set
+	ErrUndefinedVariable = errors.New("undefined bundle variable (see: nanodoc topics bundles)")
 )
 
 // FileError represents an error related to a specific file
@@ -60,3 +75,17 @@ func (e *RangeError) Error() string {
 func (e *RangeError) Unwrap() error {
 	return e.Err
 }
+
+// OptionError represents an option value that is not valid, given on the
+// command line or in a bundle's option lines
+type OptionError struct {
+	Err error
+}
+
+func (e *OptionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
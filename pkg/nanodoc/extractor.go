@@ -2,18 +2,39 @@ package nanodoc
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/arthur-debert/nanodoc/pkg/markdown"
+	"gopkg.in/yaml.v3"
 )
 
 // ExtractFileContent reads a file and extracts content based on optional range specifications.
-// The path can include a range suffix like "file.txt:L10-20,L30,L40-".
+// The path can include a range suffix like "file.txt:L10-20,L30,L40-" or a
+// byte range suffix like "file.bin:B100-200". The path "-" reads standard
+// input, shown as "<stdin>".
 func ExtractFileContent(pathWithRange string) (*FileContent, error) {
-	path, rangeSpec := parsePathWithRange(pathWithRange)
+	path, rangeSpec, err := splitRangeSpec(pathWithRange)
+	if err != nil {
+		return nil, err
+	}
+	if isByteRangeSpec(rangeSpec) {
+		content, err := extractByteRanges(path, rangeSpec)
+		if err != nil {
+			return nil, err
+		}
+		return &FileContent{
+			Filepath: sourceName(path),
+			Content:  content,
+		}, nil
+	}
 
-	file, err := os.Open(path)
+	file, err := openSource(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, &FileError{Path: path, Err: ErrFileNotFound}
@@ -53,9 +74,10 @@ func ExtractFileContent(pathWithRange string) (*FileContent, error) {
 	content := strings.Join(contentParts, "\n")
 
 	return &FileContent{
-		Filepath: path,
-		Content:  content,
-		Ranges:   ranges,
+		Filepath:  sourceName(path),
+		Content:   content,
+		Ranges:    ranges,
+		FileLines: len(lines),
 	}, nil
 }
 
@@ -64,9 +86,13 @@ func ExtractFileContent(pathWithRange string) (*FileContent, error) {
 //
 //	"file.txt:L10-20" -> ("file.txt", "L10-20")
 //	"file.txt:L5" -> ("file.txt", "L5")
+//	"file.bin:B100-200" -> ("file.bin", "B100-200")
 func parsePathWithRange(pathWithRange string) (path, rangeSpec string) {
-	// Look for the last colon followed by 'L' (to avoid issues with Windows paths)
+	// Look for the last colon followed by 'L' or 'B' (to avoid issues with Windows paths)
 	idx := strings.LastIndex(pathWithRange, ":L")
+	if byteIdx := strings.LastIndex(pathWithRange, ":B"); byteIdx > idx {
+		idx = byteIdx
+	}
 	if idx == -1 {
 		return pathWithRange, ""
 	}
@@ -74,12 +100,162 @@ func parsePathWithRange(pathWithRange string) (path, rangeSpec string) {
 	return pathWithRange[:idx], pathWithRange[idx+1:]
 }
 
+// splitRangeSpec is parsePathWithRange for callers that read the file: it
+// rejects paths that mix line and byte ranges, such as "f:L1-2:B0-10".
+func splitRangeSpec(pathWithRange string) (path, rangeSpec string, err error) {
+	path, rangeSpec = parsePathWithRange(pathWithRange)
+	if rangeSpec == "" {
+		return path, rangeSpec, nil
+	}
+	if _, inner := parsePathWithRange(path); inner != "" {
+		return "", "", &RangeError{Input: inner + ":" + rangeSpec, Err: ErrMixedRanges}
+	}
+	return path, rangeSpec, nil
+}
+
+// ExtractFileContentWithOptions is ExtractFileContent with the size limit in
+// opts: a file larger than opts.MaxFileSize is not read and gives a
+// FileError wrapping ErrFileTooLarge. Standard input is not limited. Range
+// errors are wrapped in a FileError naming the file.
+func ExtractFileContentWithOptions(pathWithRange string, opts *FormattingOptions) (*FileContent, error) {
+	if err := checkFileSize(pathWithRange, opts.MaxFileSize); err != nil {
+		return nil, err
+	}
+	return extractNamed(pathWithRange)
+}
+
+// checkFileSize returns a FileError wrapping ErrFileTooLarge when the file
+// at pathWithRange is larger than maxSize. A maxSize of 0, standard input
+// and files that cannot be stat'ed pass; reading reports the latter.
+func checkFileSize(pathWithRange string, maxSize int64) error {
+	size, over := fileOverSize(pathWithRange, maxSize)
+	if !over {
+		return nil
+	}
+	path, _ := parsePathWithRange(pathWithRange)
+	return &FileError{
+		Path: path,
+		Err:  fmt.Errorf("%w (%s, limit %s)", ErrFileTooLarge, formatFileSize(size), formatFileSize(maxSize)),
+	}
+}
+
+// fileOverSize returns the size of the file at pathWithRange and whether it
+// is larger than maxSize
+func fileOverSize(pathWithRange string, maxSize int64) (int64, bool) {
+	if maxSize <= 0 || isStdinPath(pathWithRange) {
+		return 0, false
+	}
+	path, _ := parsePathWithRange(pathWithRange)
+	stat, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	return stat.Size(), stat.Size() > maxSize
+}
+
+// extractFiles extracts each of the resolved files with the size limit in
+// opts, opts.Concurrency at a time. With OnOversize set to skip, an oversized
+// file is nil in the result and its error is returned in skipped instead of
+// stopping the extraction.
+func extractFiles(pathInfos []PathInfo, opts *FormattingOptions) ([]*FileContent, []error, error) {
+	paths := make([]string, len(pathInfos))
+	for i, info := range pathInfos {
+		paths[i] = info.Original
+	}
+
+	contents, errs := extractAll(paths, opts.Concurrency, func(path string) (*FileContent, error) {
+		return ExtractFileContentWithOptions(path, opts)
+	})
+
+	var skipped []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrFileTooLarge) && opts.OnOversize != OversizeError {
+			skipped = append(skipped, err)
+			continue
+		}
+		return nil, nil, err
+	}
+	return contents, skipped, nil
+}
+
+// isByteRangeSpec reports whether a range spec selects bytes rather than lines
+func isByteRangeSpec(spec string) bool {
+	return strings.HasPrefix(spec, "B")
+}
+
+// extractByteRanges reads the byte ranges in spec from path and concatenates
+// them. Offsets start at 0 and the end is exclusive, so "B100-200" is the 100
+// bytes from offset 100; "B100-" runs to the end of the file.
+func extractByteRanges(path, spec string) (string, error) {
+	data, err := readSource(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", &FileError{Path: path, Err: ErrFileNotFound}
+		}
+		return "", &FileError{Path: path, Err: err}
+	}
+
+	var content strings.Builder
+	for _, part := range strings.Split(spec, ",") {
+		start, end, err := parseByteRange(part, len(data))
+		if err != nil {
+			return "", err
+		}
+		content.Write(data[start:end])
+	}
+	return content.String(), nil
+}
+
+// parseByteRange parses a single byte range like "B100-200" or "B100-" and
+// clamps its end to size.
+func parseByteRange(spec string, size int) (start, end int, err error) {
+	if strings.HasPrefix(spec, "L") {
+		return 0, 0, &RangeError{Input: spec, Err: ErrMixedRanges}
+	}
+	if !strings.HasPrefix(spec, "B") {
+		return 0, 0, &RangeError{Input: spec, Err: fmt.Errorf("byte range must start with 'B'")}
+	}
+
+	parts := strings.Split(spec[1:], "-")
+	if len(parts) != 2 {
+		return 0, 0, &RangeError{Input: spec, Err: fmt.Errorf("byte range must be B<start>-<end>")}
+	}
+
+	start, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || start < 0 {
+		return 0, 0, &RangeError{Input: spec, Err: fmt.Errorf("invalid start offset")}
+	}
+	end = size
+	if strings.TrimSpace(parts[1]) != "" {
+		end, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return 0, 0, &RangeError{Input: spec, Err: fmt.Errorf("invalid end offset")}
+		}
+	}
+	if end <= start {
+		return 0, 0, &RangeError{Input: spec, Err: fmt.Errorf("end offset must be after the start")}
+	}
+	if start >= size {
+		return 0, 0, &RangeError{Input: spec, Err: fmt.Errorf("start offset %d is past the end of the file (%d bytes)", start, size)}
+	}
+	if end > size {
+		end = size
+	}
+	return start, end, nil
+}
+
 // parseRanges parses a comma-separated list of range specifications.
 func parseRanges(spec string, totalLines int) ([]Range, error) {
 	rangeStrings := strings.Split(spec, ",")
 	var ranges []Range
 
 	for _, rangeStr := range rangeStrings {
+		if isByteRangeSpec(rangeStr) {
+			return nil, &RangeError{Input: spec, Err: ErrMixedRanges}
+		}
 		if !strings.HasPrefix(rangeStr, "L") {
 			return nil, &RangeError{Input: spec, Err: fmt.Errorf("range specifier must start with 'L'")}
 		}
@@ -225,37 +401,199 @@ func extractLinesInRange(lines []string, r *Range) string {
 	return strings.Join(extractedLines, "\n")
 }
 
-// ResolveAndExtractFiles takes a list of resolved paths and extracts their content
+// ResolveAndExtractFiles takes a list of resolved paths and extracts their
+// content, reading up to runtime.NumCPU() files at once. The contents keep the
+// order of pathInfos; on failure the error of the earliest failing file is
+// returned.
 func ResolveAndExtractFiles(pathInfos []PathInfo, additionalExtensions []string) ([]FileContent, error) {
-	var contents []FileContent
+	var paths []string
+	var bundleErr error
 
+collect:
 	for _, info := range pathInfos {
 		switch info.Type {
 		case "file":
 			// Single file - check if it has range specification in original path
-			content, err := ExtractFileContent(info.Original)
-			if err != nil {
-				return nil, err
-			}
-			contents = append(contents, *content)
+			paths = append(paths, info.Original)
 
 		case "directory", "glob":
-			// Multiple files from directory or glob
-			for _, filePath := range info.Files {
-				content, err := ExtractFileContent(filePath)
-				if err != nil {
-					return nil, err
-				}
-				contents = append(contents, *content)
-			}
+			// Multiple files from directory or glob, sharing its range
+			paths = append(paths, pathInfoFiles(info)...)
 
 		case "bundle":
 			// Bundle files will be handled in step 6
-			return nil, fmt.Errorf("bundle files not yet supported")
+			bundleErr = fmt.Errorf("bundle files not yet supported")
+			break collect
 		}
 	}
 
+	extracted, errs := extractAll(paths, 0, extractNamed)
+	contents := make([]FileContent, 0, len(extracted))
+	for i, content := range extracted {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		contents = append(contents, *content)
+	}
+	if bundleErr != nil {
+		return nil, bundleErr
+	}
+
 	return contents, nil
 }
 
+// pathInfoFiles returns the files of a directory or glob PathInfo with the
+// range on its Original, as in "src/:L1-5", applied to each of them
+func pathInfoFiles(info PathInfo) []string {
+	_, rangeSpec := parsePathWithRange(info.Original)
+	if rangeSpec == "" {
+		return info.Files
+	}
+	files := make([]string, len(info.Files))
+	for i, file := range info.Files {
+		files[i] = file + ":" + rangeSpec
+	}
+	return files
+}
+
+// extractNamed is ExtractFileContent with range errors wrapped in a
+// FileError, so a range shared by the files of a directory or glob names the
+// file it does not fit
+func extractNamed(pathWithRange string) (*FileContent, error) {
+	content, err := ExtractFileContent(pathWithRange)
+	var rangeErr *RangeError
+	if errors.As(err, &rangeErr) {
+		path, _ := parsePathWithRange(pathWithRange)
+		return nil, &FileError{Path: path, Err: err}
+	}
+	return content, err
+}
+
+// extractAll runs extract on each of paths with a pool of up to workers
+// goroutines, runtime.NumCPU() when workers is 0 or less, and returns the
+// contents and errors in the order of paths. A single path, or a single
+// worker, is extracted in the calling goroutine.
+func extractAll(paths []string, workers int, extract func(string) (*FileContent, error)) ([]*FileContent, []error) {
+	contents := make([]*FileContent, len(paths))
+	errs := make([]error, len(paths))
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	if workers <= 1 {
+		for i, path := range paths {
+			contents[i], errs[i] = extract(path)
+		}
+		return contents, errs
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				contents[i], errs[i] = extract(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
+	return contents, errs
+}
+
+// selectSections keeps only the named sections of each markdown file, in the
+// order given. Files without any of them, including non-markdown files, are
+// kept whole unless sectionOnly is set, in which case they are dropped.
+func selectSections(contents []FileContent, titles []string, sectionOnly bool) []FileContent {
+	parser := markdown.NewParser()
+	transformer := markdown.NewTransformer()
+
+	result := make([]FileContent, 0, len(contents))
+	for _, item := range contents {
+		// Inline blocks and bundle comment sections are not files
+		if item.OriginalSource != "" || item.SectionTitle != "" {
+			result = append(result, item)
+			continue
+		}
+
+		var found []string
+		if isMarkdownFile(item.Filepath) {
+			mdDoc, err := parser.Parse([]byte(item.Content))
+			if err == nil {
+				for _, title := range titles {
+					if section, ok := transformer.ExtractSection(mdDoc, title); ok {
+						found = append(found, section)
+					}
+				}
+			}
+		}
+
+		if len(found) > 0 {
+			item.Content = strings.Join(found, "\n\n")
+		} else if sectionOnly {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// stripFrontMatter removes the YAML front matter block content starts with,
+// along with the blank lines after it, and returns the rest and the block's
+// title, if it has one. Content without a closed block that parses as a YAML
+// mapping is returned unchanged.
+func stripFrontMatter(content string) (string, string) {
+	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
+		return content, ""
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		if line != "---" && line != "..." {
+			continue
+		}
+
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "")), &fields); err != nil {
+			return content, ""
+		}
+		title, _ := fields["title"].(string)
+		return strings.TrimLeft(strings.Join(lines[i+1:], ""), "\r\n"), title
+	}
+	return content, ""
+}
+
+// dedupeLines drops every line of the contents that already appeared earlier,
+// in any file or, with the file scope, in the same file. Blank lines are kept
+// so paragraphs stay apart.
+func dedupeLines(contents []FileContent, scope string) {
+	seen := make(map[string]bool)
+	for i := range contents {
+		if scope == DedupeScopeFile {
+			seen = make(map[string]bool)
+		}
+
+		lines := strings.Split(contents[i].Content, "\n")
+		kept := lines[:0]
+		for _, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				if seen[line] {
+					continue
+				}
+				seen[line] = true
+			}
+			kept = append(kept, line)
+		}
+		contents[i].Content = strings.Join(kept, "\n")
+	}
+}
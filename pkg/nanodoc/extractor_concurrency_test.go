@@ -0,0 +1,66 @@
+package nanodoc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractConcurrentlyKeepsOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	var paths []string
+	for i := 0; i < 40; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("file%02d.txt", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("content %d", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	pathInfos := []PathInfo{{Original: tempDir, Absolute: tempDir, Type: "directory", Files: paths}}
+
+	contents, err := ResolveAndExtractFiles(pathInfos, nil)
+	if err != nil {
+		t.Fatalf("ResolveAndExtractFiles() error = %v", err)
+	}
+	if len(contents) != len(paths) {
+		t.Fatalf("Expected %d contents, got %d", len(paths), len(contents))
+	}
+	for i, content := range contents {
+		if content.Filepath != paths[i] || content.Content != fmt.Sprintf("content %d", i) {
+			t.Errorf("Content %d = %s %q, want %s", i, content.Filepath, content.Content, paths[i])
+		}
+	}
+
+	// Every pool size builds the same document
+	for _, workers := range []int{1, 3, 64} {
+		doc, err := BuildDocument(pathInfos, FormattingOptions{Concurrency: workers})
+		if err != nil {
+			t.Fatalf("BuildDocument(Concurrency %d) error = %v", workers, err)
+		}
+		for i, item := range doc.ContentItems {
+			if item.Filepath != paths[i] {
+				t.Errorf("Concurrency %d: item %d = %s, want %s", workers, i, item.Filepath, paths[i])
+			}
+		}
+	}
+}
+
+func TestExtractConcurrentlyReturnsFirstError(t *testing.T) {
+	tempDir := t.TempDir()
+	present := filepath.Join(tempDir, "present.txt")
+	if err := os.WriteFile(present, []byte("here"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(tempDir, "missing.txt")
+	badRange := present + ":L5-9"
+
+	pathInfos := []PathInfo{{Type: "glob", Files: []string{present, missing, present, badRange}}}
+	_, err := ResolveAndExtractFiles(pathInfos, nil)
+
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) || fileErr.Path != missing || !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("ResolveAndExtractFiles() error = %v, want the missing file's error", err)
+	}
+}
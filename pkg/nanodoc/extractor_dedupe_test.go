@@ -0,0 +1,58 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDedupeLines(t *testing.T) {
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "a.log")
+	second := filepath.Join(tempDir, "b.log")
+	if err := os.WriteFile(first, []byte("start\nboot ok\n\nboot ok\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("boot ok\nnew entry\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pathInfos := []PathInfo{
+		{Original: first, Absolute: first, Type: "file"},
+		{Original: second, Absolute: second, Type: "file"},
+	}
+
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{DedupeScopeDocument, []string{"start\nboot ok\n", "new entry"}},
+		{DedupeScopeFile, []string{"start\nboot ok\n", "boot ok\nnew entry"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			doc, err := BuildDocumentWithOptions(pathInfos, FormattingOptions{DedupeLines: true, DedupeLinesScope: tt.scope})
+			if err != nil {
+				t.Fatalf("BuildDocumentWithOptions() error = %v", err)
+			}
+			for i, want := range tt.want {
+				if got := doc.ContentItems[i].Content; got != want {
+					t.Errorf("file %d content = %q, want %q", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	// Line numbers count the surviving lines
+	doc, err := BuildDocumentWithOptions(pathInfos, FormattingOptions{OutputFormat: "term", DedupeLines: true})
+	if err != nil {
+		t.Fatalf("BuildDocumentWithOptions() error = %v", err)
+	}
+	result, err := RenderDocument(doc, &FormattingContext{LineNumbers: LineNumberGlobal})
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if strings.Count(result, "boot ok") != 1 || !strings.Contains(result, "4 | new entry") {
+		t.Errorf("Expected boot ok once and new entry numbered 4, got:\n%s", result)
+	}
+}
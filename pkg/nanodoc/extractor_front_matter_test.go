@@ -0,0 +1,99 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripFrontMatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantBody  string
+		wantTitle string
+	}{
+		{
+			name:      "block with a title",
+			content:   "---\ntitle: Getting Started\ntags: [intro]\n---\n\n# Intro\n",
+			wantBody:  "# Intro\n",
+			wantTitle: "Getting Started",
+		},
+		{
+			name:     "block without a title",
+			content:  "---\ndraft: true\n...\nbody",
+			wantBody: "body",
+		},
+		{
+			name:     "no front matter",
+			content:  "# Intro\n---\n",
+			wantBody: "# Intro\n---\n",
+		},
+		{
+			name:     "unclosed block is left intact",
+			content:  "---\ntitle: Open\n\n# Intro\n",
+			wantBody: "---\ntitle: Open\n\n# Intro\n",
+		},
+		{
+			name:     "invalid YAML is left intact",
+			content:  "---\ntitle: [unclosed\n---\nbody",
+			wantBody: "---\ntitle: [unclosed\n---\nbody",
+		},
+		{
+			name:     "a thematic break that is not a mapping is left intact",
+			content:  "---\nJust a paragraph.\n---\nbody",
+			wantBody: "---\nJust a paragraph.\n---\nbody",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, title := stripFrontMatter(tt.content)
+			if body != tt.wantBody {
+				t.Errorf("stripFrontMatter() body = %q, want %q", body, tt.wantBody)
+			}
+			if title != tt.wantTitle {
+				t.Errorf("stripFrontMatter() title = %q, want %q", title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestBuildDocumentStripFrontMatter(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"getting_started.md": "---\ntitle: Start Here\n---\n# Getting Started\n",
+		"notes.txt":          "---\ntitle: Kept\n---\n",
+	}
+	var paths []string
+	for _, name := range []string{"getting_started.md", "notes.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	pathInfos, err := ResolvePaths(paths)
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	doc, err := BuildDocument(pathInfos, FormattingOptions{StripFrontMatter: true})
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+
+	if got := doc.ContentItems[0].Content; got != "# Getting Started" {
+		t.Errorf("Expected the markdown front matter removed, got %q", got)
+	}
+	if got := doc.ContentItems[1].Content; got != "---\ntitle: Kept\n---" {
+		t.Errorf("Expected text files untouched, got %q", got)
+	}
+
+	// The front matter title wins over the first heading and the filename
+	generateTOC(doc)
+	opts := &FormattingOptions{HeaderFormat: HeaderFormatNice}
+	if got := generateHeaderName(doc.ContentItems[0], opts, doc); got != "Start Here" {
+		t.Errorf("generateHeaderName() = %q, want %q", got, "Start Here")
+	}
+}
@@ -0,0 +1,70 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildDocumentSections(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"guide.md":  "# Guide\n\nintro\n\n## Install\n\nrun it\n\n## Usage\n\nuse it\n",
+		"notes.txt": "plain notes",
+	}
+	var pathInfos []PathInfo
+	for _, name := range []string{"guide.md", "notes.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		pathInfos = append(pathInfos, PathInfo{Original: path, Absolute: path, Type: "file"})
+	}
+
+	tests := []struct {
+		name        string
+		sections    []string
+		sectionOnly bool
+		expected    []string
+	}{
+		{
+			name:     "one named section",
+			sections: []string{"Install"},
+			expected: []string{"## Install\n\nrun it", "plain notes"},
+		},
+		{
+			name:        "section only skips other files",
+			sections:    []string{"Install"},
+			sectionOnly: true,
+			expected:    []string{"## Install\n\nrun it"},
+		},
+		{
+			name:     "sections follow the given order",
+			sections: []string{"Usage", "Install"},
+			expected: []string{"## Usage\n\nuse it\n\n## Install\n\nrun it", "plain notes"},
+		},
+		{
+			name:        "no match keeps the file unless section only",
+			sections:    []string{"Missing"},
+			sectionOnly: true,
+			expected:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BuildDocument(pathInfos, FormattingOptions{Sections: tt.sections, SectionOnly: tt.sectionOnly})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+			if len(doc.ContentItems) != len(tt.expected) {
+				t.Fatalf("Expected %d items, got %d: %+v", len(tt.expected), len(doc.ContentItems), doc.ContentItems)
+			}
+			for i, want := range tt.expected {
+				if got := doc.ContentItems[i].Content; got != want {
+					t.Errorf("Item %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFileSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "512", want: 512},
+		{input: "512B", want: 512},
+		{input: "64KB", want: 64 << 10},
+		{input: "10mb", want: 10 << 20},
+		{input: "1.5G", want: 3 << 29},
+		{input: "2 MiB", want: 2 << 20},
+		{input: "10XB", wantErr: true},
+		{input: "MB", wantErr: true},
+		{input: "-5MB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFileSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFileSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFileSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxFileSize(t *testing.T) {
+	tempDir := t.TempDir()
+	big := filepath.Join(tempDir, "big.txt")
+	small := filepath.Join(tempDir, "small.txt")
+	if err := os.WriteFile(big, []byte(strings.Repeat("x", 2048)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(small, []byte("small"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pathInfos := []PathInfo{{Original: tempDir, Absolute: tempDir, Type: "directory", Files: []string{big, small}}}
+
+	t.Run("extract", func(t *testing.T) {
+		_, err := ExtractFileContentWithOptions(big+":L1", &FormattingOptions{MaxFileSize: 1024})
+		var fileErr *FileError
+		if !errors.As(err, &fileErr) || !errors.Is(err, ErrFileTooLarge) {
+			t.Fatalf("ExtractFileContentWithOptions() error = %v, want a FileError wrapping ErrFileTooLarge", err)
+		}
+		if !strings.Contains(err.Error(), "(2.0 KB, limit 1.0 KB)") {
+			t.Errorf("Expected the sizes in the error, got %v", err)
+		}
+
+		if _, err := ExtractFileContentWithOptions(big, &FormattingOptions{}); err != nil {
+			t.Errorf("ExtractFileContentWithOptions() without a limit error = %v", err)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		doc, err := BuildDocument(pathInfos, FormattingOptions{MaxFileSize: 1024, OnOversize: OversizeSkip})
+		if err != nil {
+			t.Fatalf("BuildDocument() error = %v", err)
+		}
+		if len(doc.ContentItems) != 1 || doc.ContentItems[0].Filepath != small {
+			t.Errorf("Expected only %s, got %+v", small, doc.ContentItems)
+		}
+		if len(doc.Skipped) != 1 || !errors.Is(doc.Skipped[0], ErrFileTooLarge) {
+			t.Errorf("Expected big.txt in Skipped, got %v", doc.Skipped)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := BuildDocument(pathInfos, FormattingOptions{MaxFileSize: 1024, OnOversize: OversizeError})
+		if !errors.Is(err, ErrFileTooLarge) {
+			t.Errorf("BuildDocument() error = %v, want ErrFileTooLarge", err)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		info, err := GenerateDryRunInfo(pathInfos, FormattingOptions{MaxFileSize: 1024})
+		if err != nil {
+			t.Fatalf("GenerateDryRunInfo() error = %v", err)
+		}
+		if info.TotalFiles != 1 || info.Oversized[big] != 2048 {
+			t.Errorf("Expected big.txt flagged and not counted, got %d files, oversized %v", info.TotalFiles, info.Oversized)
+		}
+		output := FormatDryRunOutput(info)
+		if !strings.Contains(output, "Files over --max-file-size 1.0 KB (would be skipped):\n  - big.txt (2.0 KB)") {
+			t.Errorf("Expected big.txt flagged in the dry run output, got:\n%s", output)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package nanodoc
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -50,6 +51,12 @@ func TestParsePathWithRange(t *testing.T) {
 			wantPath:  "C:\\path\\to\\file.txt",
 			wantRange: "L1-5",
 		},
+		{
+			name:      "path with byte range",
+			input:     "file.bin:B100-200",
+			wantPath:  "file.bin",
+			wantRange: "B100-200",
+		},
 		{
 			name:      "path with colon but no range",
 			input:     "C:\\file.txt",
@@ -270,6 +277,57 @@ func TestExtractFileContent(t *testing.T) {
 	}
 }
 
+func TestExtractFileContentByteRange(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(testFile, []byte("0123456789abcdef"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		pathWithRange string
+		wantContent   string
+		wantErr       bool
+		wantMixed     bool
+	}{
+		{"byte subrange", testFile + ":B4-8", "4567", false, false},
+		{"open-ended", testFile + ":B12-", "cdef", false, false},
+		{"end past the file is clamped", testFile + ":B14-100", "ef", false, false},
+		{"several ranges are concatenated", testFile + ":B0-2,B10-12", "01ab", false, false},
+		{"start past the file", testFile + ":B16-20", "", true, false},
+		{"empty range", testFile + ":B5-5", "", true, false},
+		{"missing end separator", testFile + ":B5", "", true, false},
+		{"line range in a byte spec", testFile + ":B0-4,L1", "", true, true},
+		{"byte range in a line spec", testFile + ":L1,B0-4", "", true, true},
+		{"both suffixes", testFile + ":L1:B0-4", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractFileContent(tt.pathWithRange)
+			if tt.wantErr {
+				var rangeErr *RangeError
+				if !errors.As(err, &rangeErr) {
+					t.Fatalf("ExtractFileContent() error = %v, want a *RangeError", err)
+				}
+				if tt.wantMixed && !errors.Is(err, ErrMixedRanges) {
+					t.Errorf("ExtractFileContent() error = %v, want %v", err, ErrMixedRanges)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractFileContent() error = %v", err)
+			}
+			if got.Filepath != testFile {
+				t.Errorf("ExtractFileContent() path = %q, want %q", got.Filepath, testFile)
+			}
+			if got.Content != tt.wantContent {
+				t.Errorf("ExtractFileContent() content = %q, want %q", got.Content, tt.wantContent)
+			}
+		})
+	}
+}
+
 func TestResolveAndExtractFiles(t *testing.T) {
 	// Create temp directory and test files
 	tempDir, err := os.MkdirTemp("", "nanodoc-resolve-extract-test-*")
@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,6 +17,122 @@ import (
 type Theme struct {
 	Name   string
 	Styles map[string]string
+	// noColor makes the SGR methods return "", for output without colors
+	noColor bool
+}
+
+// Theme keys for banner rendering: "banner.<style>.char" replaces the line
+// character of a banner style and "banner.color" colors the whole banner
+const (
+	themeBannerCharKey  = "banner.%s.char"
+	themeBannerColorKey = "banner.color"
+)
+
+// themeLineNumberKey colors the line number gutter, digits and separator
+const themeLineNumberKey = "line-number"
+
+// BannerGlyph returns the theme's line character for the named banner style,
+// or "" when the theme does not define a single-character glyph for it
+func (t *Theme) BannerGlyph(style string) string {
+	if t == nil {
+		return ""
+	}
+	glyph := t.Styles[fmt.Sprintf(themeBannerCharKey, style)]
+	if utf8.RuneCountInString(glyph) != 1 {
+		return ""
+	}
+	return glyph
+}
+
+// BannerSGR returns the ANSI escape sequence for the theme's banner color,
+// or "" when the theme leaves banners uncolored
+func (t *Theme) BannerSGR() string {
+	if t == nil || t.noColor {
+		return ""
+	}
+	return styleToSGR(t.Styles[themeBannerColorKey])
+}
+
+// EmphasisSGR returns the ANSI escape sequence for the theme's emphasis
+// style, falling back to bold when there is no theme or it has none
+func (t *Theme) EmphasisSGR() string {
+	if t != nil && t.noColor {
+		return ""
+	}
+	if t != nil {
+		if sgr := styleToSGR(t.Styles["emphasis"]); sgr != "" {
+			return sgr
+		}
+	}
+	return "\x1b[1m"
+}
+
+// ZebraSGR returns the ANSI escape sequence for the background of striped
+// lines, or "" when there is no theme or it defines no zebra style
+func (t *Theme) ZebraSGR() string {
+	if t == nil || t.noColor {
+		return ""
+	}
+	return styleToSGR(t.Styles["zebra"])
+}
+
+// LineNumberSGR returns the ANSI escape sequence for the line number gutter,
+// or "" when there is no theme or it defines no line-number style
+func (t *Theme) LineNumberSGR() string {
+	if t == nil || t.noColor {
+		return ""
+	}
+	return styleToSGR(t.Styles[themeLineNumberKey])
+}
+
+// sgrColors maps theme color names to their ANSI foreground codes
+var sgrColors = map[string]int{
+	"black": 30, "red": 31, "green": 32, "yellow": 33,
+	"blue": 34, "magenta": 35, "cyan": 36, "white": 37,
+}
+
+// sgrAttributes maps theme style attributes to their ANSI codes
+var sgrAttributes = map[string]int{
+	"bold": 1, "dim": 2, "italic": 3, "underline": 4,
+}
+
+// styleToSGR converts a theme style such as "bright_blue bold on black" into
+// an ANSI escape sequence; unknown words are ignored
+func styleToSGR(style string) string {
+	var codes []string
+	words := strings.Fields(style)
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		background := false
+		if word == "on" && i+1 < len(words) {
+			background = true
+			i++
+			word = words[i]
+		}
+
+		if code, ok := sgrAttributes[word]; ok && !background {
+			codes = append(codes, strconv.Itoa(code))
+			continue
+		}
+
+		name := strings.TrimPrefix(word, "bright_")
+		code, ok := sgrColors[name]
+		if !ok {
+			continue
+		}
+		if name != word {
+			code += 60
+		}
+		if background {
+			code += 10
+		}
+		codes = append(codes, strconv.Itoa(code))
+	}
+
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
 }
 
 // FormattingContext holds the state for formatting operations
@@ -25,6 +143,16 @@ type FormattingContext struct {
 	HeaderFormat   HeaderFormat
 	SequenceStyle SequenceStyle
 	ShowTOC       bool
+	// TOCLinks links term TOC entries to their files; callers clear it when
+	// the output cannot show hyperlinks
+	TOCLinks bool
+	// Highlight colors code files in term output; callers clear it when the
+	// output cannot show colors
+	Highlight bool
+	// Color is ColorNever to keep nanodoc's own escape sequences (theme
+	// colors, highlighting, hyperlinks) out of term output; those in the
+	// files are kept. Callers resolve ColorAuto for where the output goes
+	Color string
 }
 
 const (
@@ -54,7 +182,8 @@ func GetAvailableThemes() ([]string, error) {
 	return themes, nil
 }
 
-// LoadTheme loads a theme from the embedded filesystem
+// LoadTheme loads a theme from the embedded filesystem. An unknown name is an
+// ErrInvalidTheme error that lists the available themes.
 func LoadTheme(themeName string) (*Theme, error) {
 	if themeName == "" {
 		themeName = DefaultTheme
@@ -62,18 +191,11 @@ func LoadTheme(themeName string) (*Theme, error) {
 
 	slog.Debug("Loading theme", "name", themeName)
 
-	// Try to load the requested theme
 	themeData, err := loadThemeFile(themeName)
 	if err != nil {
-		// Fall back to default theme
-		slog.Warn("Failed to load theme, falling back to default", 
-			"theme", themeName, "error", err)
-		
-		themeData, err = loadThemeFile(DefaultTheme)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load default theme: %w", err)
-		}
-		themeName = DefaultTheme
+		available, _ := GetAvailableThemes()
+		return nil, fmt.Errorf("unknown theme %q, available themes: %s: %w",
+			themeName, strings.Join(available, ", "), ErrInvalidTheme)
 	}
 
 	theme := &Theme{
@@ -125,7 +247,9 @@ func loadThemeFile(themeName string) (map[string]string, error) {
 	return styles, nil
 }
 
-// NewFormattingContext creates a new formatting context with the given options
+// NewFormattingContext creates a new formatting context with the given options.
+// The theme is loaded fresh on each call, so the returned context is independent
+// of options and of any other context.
 func NewFormattingContext(options FormattingOptions) (*FormattingContext, error) {
 	theme, err := LoadTheme(options.Theme)
 	if err != nil {
@@ -139,9 +263,41 @@ func NewFormattingContext(options FormattingOptions) (*FormattingContext, error)
 		HeaderFormat:   options.HeaderFormat,
 		SequenceStyle: options.SequenceStyle,
 		ShowTOC:       options.ShowTOC,
+		TOCLinks:      options.TOCLinks,
+		Highlight:     options.Highlight,
 	}, nil
 }
 
+// Clone returns a copy of the context with its own copy of the theme
+func (fc *FormattingContext) Clone() *FormattingContext {
+	clone := *fc
+	clone.Theme = fc.Theme.Clone()
+	return &clone
+}
+
+// Clone returns a copy of the theme with its own style map
+func (t *Theme) Clone() *Theme {
+	if t == nil {
+		return nil
+	}
+	styles := make(map[string]string, len(t.Styles))
+	for k, v := range t.Styles {
+		styles[k] = v
+	}
+	return &Theme{Name: t.Name, Styles: styles, noColor: t.noColor}
+}
+
+// withoutColors returns a copy of the theme whose SGR methods return "",
+// keeping its banner glyphs
+func (t *Theme) withoutColors() *Theme {
+	plain := t.Clone()
+	if plain == nil {
+		plain = &Theme{}
+	}
+	plain.noColor = true
+	return plain
+}
+
 // ApplyTheme applies the theme to a document (placeholder for now)
 func (fc *FormattingContext) ApplyTheme(doc *Document) error {
 	// This is a placeholder - actual formatting will be implemented
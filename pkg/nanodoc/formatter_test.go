@@ -1,8 +1,10 @@
 package nanodoc
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -60,9 +62,9 @@ func TestLoadTheme(t *testing.T) {
 			wantErr:   false,
 		},
 		{
-			name:      "non-existent theme falls back to default",
+			name:      "non-existent theme is an error",
 			themeName: "non-existent-theme",
-			wantErr:   false,
+			wantErr:   true,
 		},
 	}
 
@@ -88,8 +90,8 @@ func TestLoadTheme(t *testing.T) {
 					}
 				}
 
-				// If non-existent theme was requested, it should fall back to default
-				if tt.themeName == "non-existent-theme" || tt.themeName == "" {
+				// An empty name loads the default theme
+				if tt.themeName == "" {
 					if theme.Name != DefaultTheme {
 						t.Errorf("Expected theme name to be %q, got %q", DefaultTheme, theme.Name)
 					}
@@ -99,6 +101,23 @@ func TestLoadTheme(t *testing.T) {
 	}
 }
 
+func TestLoadThemeUnknownListsThemes(t *testing.T) {
+	_, err := LoadTheme("classci-dark")
+	if !errors.Is(err, ErrInvalidTheme) {
+		t.Fatalf("LoadTheme() error = %v, want ErrInvalidTheme", err)
+	}
+
+	themes, err2 := GetAvailableThemes()
+	if err2 != nil {
+		t.Fatalf("GetAvailableThemes() error = %v", err2)
+	}
+	for _, want := range append(themes, `"classci-dark"`) {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the error to mention %s, got: %v", want, err)
+		}
+	}
+}
+
 func TestLoadCustomTheme(t *testing.T) {
 	// Create a temporary theme file
 	tmpDir := t.TempDir()
@@ -0,0 +1,75 @@
+package nanodoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitChangedFiles lists the text files under dir that differ from ref,
+// committed or not, as absolute paths. Deleted files are left out.
+func GitChangedFiles(dir, ref string, options *FormattingOptions) ([]string, error) {
+	return gitFiles(dir, options, "diff", "--name-only", "-z", "--relative", "--diff-filter=d", ref, "--")
+}
+
+// GitTrackedFiles lists the text files under dir that git tracks, as
+// absolute paths
+func GitTrackedFiles(dir string, options *FormattingOptions) ([]string, error) {
+	return gitFiles(dir, options, "ls-files", "-z")
+}
+
+// gitFiles runs git in dir and returns the files it lists, relative to dir,
+// as absolute paths. Like directory expansion, only files with a text
+// extension (or one of options' extensions) are kept.
+func gitFiles(dir string, options *FormattingOptions, args ...string) ([]string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = absDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%w: git is not installed", ErrGitFiles)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrGitFiles, message)
+	}
+
+	var extensions []string
+	if options != nil {
+		extensions = options.AdditionalExtensions
+	}
+
+	var files []string
+	for _, name := range strings.Split(stdout.String(), "\x00") {
+		if name == "" {
+			continue
+		}
+		path := filepath.Join(absDir, filepath.FromSlash(name))
+		// Tracked files can be missing from the working tree
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if !isTextFileWithExtensions(path, extensions) {
+			continue
+		}
+		files = append(files, path)
+	}
+
+	if options != nil && options.NoDefaultExtensions {
+		files = filterByExtensions(files, options.AdditionalExtensions)
+	}
+	return files, nil
+}
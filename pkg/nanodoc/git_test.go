@@ -0,0 +1,84 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGitFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("README.md", "readme")
+	write("docs/guide.txt", "guide")
+	write("main.go", "package main")
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	write("docs/guide.txt", "guide, changed")
+
+	t.Run("changed since a ref", func(t *testing.T) {
+		files, err := GitChangedFiles(repo, "HEAD", nil)
+		if err != nil {
+			t.Fatalf("GitChangedFiles() error = %v", err)
+		}
+		want := []string{filepath.Join(repo, "docs", "guide.txt")}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("GitChangedFiles() = %v, want %v", files, want)
+		}
+	})
+
+	t.Run("tracked text files", func(t *testing.T) {
+		files, err := GitTrackedFiles(repo, nil)
+		if err != nil {
+			t.Fatalf("GitTrackedFiles() error = %v", err)
+		}
+		want := []string{filepath.Join(repo, "README.md"), filepath.Join(repo, "docs", "guide.txt")}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("GitTrackedFiles() = %v, want %v", files, want)
+		}
+
+		// Extra extensions are honored like in directories
+		files, err = GitTrackedFiles(repo, &FormattingOptions{AdditionalExtensions: []string{"go"}, NoDefaultExtensions: true})
+		if err != nil {
+			t.Fatalf("GitTrackedFiles() error = %v", err)
+		}
+		want = []string{filepath.Join(repo, "main.go")}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("GitTrackedFiles() with --ext go = %v, want %v", files, want)
+		}
+	})
+
+	t.Run("outside a repository", func(t *testing.T) {
+		_, err := GitTrackedFiles(t.TempDir(), nil)
+		if !errors.Is(err, ErrGitFiles) {
+			t.Errorf("Expected ErrGitFiles outside a repository, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package nanodoc
+
+import "strings"
+
+// syntaxLanguage describes what the highlighter needs to know about a
+// language: its keywords, comment markers and string delimiters
+type syntaxLanguage struct {
+	keywords     map[string]bool
+	lineComments []string
+	blockComment [2]string
+	// String delimiters, longest first; only those in multiline may span lines
+	quotes    []string
+	multiline map[string]bool
+}
+
+// newSyntaxLanguage builds a syntaxLanguage from a space-separated keyword list
+func newSyntaxLanguage(keywords string, lineComments []string, blockComment [2]string, quotes []string, multiline ...string) *syntaxLanguage {
+	lang := &syntaxLanguage{
+		keywords:     make(map[string]bool),
+		lineComments: lineComments,
+		blockComment: blockComment,
+		quotes:       quotes,
+		multiline:    make(map[string]bool),
+	}
+	for _, keyword := range strings.Fields(keywords) {
+		lang.keywords[keyword] = true
+	}
+	for _, quote := range multiline {
+		lang.multiline[quote] = true
+	}
+	return lang
+}
+
+var (
+	cComments    = [2]string{"/*", "*/"}
+	cQuotes      = []string{`"`, "'"}
+	scriptQuotes = []string{"`", `"`, "'"}
+	shellSyntax  = newSyntaxLanguage("case do done elif else esac export fi for function if in local return select then until while",
+		[]string{"#"}, [2]string{}, cQuotes)
+)
+
+// syntaxLanguages maps the code fence language of a file (see
+// languageForFile) to its highlighting rules
+var syntaxLanguages = map[string]*syntaxLanguage{
+	"go": newSyntaxLanguage("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var true false nil iota",
+		[]string{"//"}, cComments, scriptQuotes, "`"),
+	"python": newSyntaxLanguage("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield True False None",
+		[]string{"#"}, [2]string{}, []string{`"""`, "'''", `"`, "'"}, `"""`, "'''"),
+	"javascript": newSyntaxLanguage("async await break case catch class const continue debugger default delete do else export extends finally for function if import in instanceof let new of return static super switch this throw try typeof var void while with yield true false null undefined",
+		[]string{"//"}, cComments, scriptQuotes, "`"),
+	"typescript": newSyntaxLanguage("abstract any as async await boolean break case catch class const continue declare default delete do else enum export extends finally for from function if implements import in instanceof interface let new number of private protected public readonly return static string super switch this throw try type typeof var void while yield true false null undefined",
+		[]string{"//"}, cComments, scriptQuotes, "`"),
+	"rust": newSyntaxLanguage("as async await break const continue crate dyn else enum extern fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait type unsafe use where while true false",
+		[]string{"//"}, cComments, []string{`"`}),
+	"java": newSyntaxLanguage("abstract boolean break byte case catch char class const continue default do double else enum extends final finally float for if implements import instanceof int interface long new package private protected public return short static super switch synchronized this throw throws try void volatile while true false null",
+		[]string{"//"}, cComments, cQuotes),
+	"c": newSyntaxLanguage("auto break case char const continue default do double else enum extern float for goto if int long register return short signed sizeof static struct switch typedef union unsigned void volatile while NULL",
+		[]string{"//"}, cComments, cQuotes),
+	"cpp": newSyntaxLanguage("auto bool break case catch char class const constexpr continue default delete do double else enum explicit extern false float for friend goto if inline int long namespace new nullptr operator private protected public return short signed sizeof static struct switch template this throw true try typedef typename union unsigned using virtual void volatile while",
+		[]string{"//"}, cComments, cQuotes),
+	"csharp": newSyntaxLanguage("abstract as base bool break case catch char class const continue decimal default delegate do double else enum event explicit extern false finally float for foreach if implicit in int interface internal is lock long namespace new null object operator out override private protected public readonly ref return sealed short static string struct switch this throw true try typeof uint ulong using var virtual void while",
+		[]string{"//"}, cComments, cQuotes),
+	"ruby": newSyntaxLanguage("alias and begin break case class def defined? do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield",
+		[]string{"#"}, [2]string{}, cQuotes),
+	"bash": shellSyntax,
+	"zsh":  shellSyntax,
+	"lua": newSyntaxLanguage("and break do else elseif end false for function goto if in local nil not or repeat return then true until while",
+		[]string{"--"}, [2]string{"--[[", "]]"}, cQuotes),
+}
+
+// Theme keys for --highlight, with the style used when a theme has none
+var syntaxStyleDefaults = map[string]string{
+	"syntax.keyword": "blue bold",
+	"syntax.string":  "green",
+	"syntax.comment": "bright_black italic",
+	"syntax.number":  "magenta",
+}
+
+// syntaxStyles holds the ANSI escape sequences code is highlighted with
+type syntaxStyles struct {
+	keyword, str, comment, number string
+}
+
+// newSyntaxStyles reads the highlighting styles from theme, falling back to
+// syntaxStyleDefaults for the keys it does not set
+func newSyntaxStyles(theme *Theme) *syntaxStyles {
+	sgr := func(key string) string {
+		if theme != nil {
+			if style, ok := theme.Styles[key]; ok {
+				return styleToSGR(style)
+			}
+		}
+		return styleToSGR(syntaxStyleDefaults[key])
+	}
+	return &syntaxStyles{
+		keyword: sgr("syntax.keyword"),
+		str:     sgr("syntax.string"),
+		comment: sgr("syntax.comment"),
+		number:  sgr("syntax.number"),
+	}
+}
+
+// cacheKey identifies the styles in fragment cache keys; nil styles, meaning
+// no highlighting, give ""
+func (s *syntaxStyles) cacheKey() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join([]string{s.keyword, s.str, s.comment, s.number}, ",")
+}
+
+// highlightSyntax colors the code in content by the language of path. Files
+// in languages it does not know, markdown and text among them, are returned
+// as they are.
+func highlightSyntax(content, path string, styles *syntaxStyles) string {
+	lang := syntaxLanguages[languageForFile(path)]
+	if lang == nil || styles == nil || content == "" {
+		return content
+	}
+
+	var out strings.Builder
+	emit := func(sgr, token string) {
+		if sgr == "" {
+			out.WriteString(token)
+			return
+		}
+		// Close the style at each line end so every line stands alone
+		out.WriteString(sgr)
+		out.WriteString(strings.ReplaceAll(token, "\n", ansiReset+"\n"+sgr))
+		out.WriteString(ansiReset)
+	}
+
+	for i := 0; i < len(content); {
+		rest := content[i:]
+
+		if open, close := lang.blockComment[0], lang.blockComment[1]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], close)
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += len(open) + len(close)
+			}
+			emit(styles.comment, rest[:end])
+			i += end
+			continue
+		}
+
+		if hasAnyPrefix(rest, lang.lineComments) {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			emit(styles.comment, rest[:end])
+			i += end
+			continue
+		}
+
+		if quote := matchingPrefix(rest, lang.quotes); quote != "" {
+			end := stringEnd(rest, quote, lang.multiline[quote])
+			emit(styles.str, rest[:end])
+			i += end
+			continue
+		}
+
+		c := rest[0]
+		switch {
+		case isDigit(c) && (i == 0 || !isIdentByte(content[i-1])):
+			end := 1
+			for end < len(rest) && (isIdentByte(rest[end]) || rest[end] == '.') {
+				end++
+			}
+			emit(styles.number, rest[:end])
+			i += end
+		case isIdentByte(c):
+			end := 1
+			for end < len(rest) && isIdentByte(rest[end]) {
+				end++
+			}
+			// Ruby's defined? keeps its question mark
+			if end < len(rest) && rest[end] == '?' && lang.keywords[rest[:end+1]] {
+				end++
+			}
+			word := rest[:end]
+			if lang.keywords[word] {
+				emit(styles.keyword, word)
+			} else {
+				out.WriteString(word)
+			}
+			i += end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// stringEnd returns the length of the string literal rest starts with,
+// including its quotes. Backslashes escape the next character, except in Go
+// raw strings. A string that may not span lines ends at the line end.
+func stringEnd(rest, quote string, multiline bool) int {
+	raw := quote == "`"
+	for i := len(quote); i < len(rest); i++ {
+		switch {
+		case rest[i] == '\\' && !raw:
+			i++
+		case rest[i] == '\n' && !multiline:
+			return i
+		case strings.HasPrefix(rest[i:], quote):
+			return i + len(quote)
+		}
+	}
+	return len(rest)
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	return matchingPrefix(s, prefixes) != ""
+}
+
+// matchingPrefix returns the first of prefixes s starts with, or ""
+func matchingPrefix(s string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,112 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlightSyntax(t *testing.T) {
+	styles := &syntaxStyles{keyword: "<k>", str: "<s>", comment: "<c>", number: "<n>"}
+	// Each colored token ends with a reset
+	r := ansiReset
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{
+			name:    "go keywords, strings and numbers",
+			path:    "main.go",
+			content: `func main() { x := "a\"b" + 42 }`,
+			want:    "<k>func" + r + ` main() { x := <s>"a\"b"` + r + " + <n>42" + r + " }",
+		},
+		{
+			name:    "keywords inside identifiers are left alone",
+			path:    "main.go",
+			content: "format(v2)",
+			want:    "format(v2)",
+		},
+		{
+			name:    "line comment runs to the line end",
+			path:    "app.py",
+			content: "x = 1  # if \"quoted\"\nreturn x",
+			want:    "x = <n>1" + r + "  <c># if \"quoted\"" + r + "\n<k>return" + r + " x",
+		},
+		{
+			name:    "block comments close and reopen at line ends",
+			path:    "main.c",
+			content: "/* one\ntwo */ int",
+			want:    "<c>/* one" + r + "\n<c>two */" + r + " <k>int" + r,
+		},
+		{
+			name:    "python triple-quoted strings span lines",
+			path:    "app.py",
+			content: "\"\"\"doc\nif\"\"\"",
+			want:    "<s>\"\"\"doc" + r + "\n<s>if\"\"\"" + r,
+		},
+		{
+			name:    "unterminated strings stop at the line end",
+			path:    "main.go",
+			content: "\"open\nfor",
+			want:    "<s>\"open" + r + "\n<k>for" + r,
+		},
+		{
+			name:    "markdown is untouched",
+			path:    "README.md",
+			content: "# if \"x\" 1",
+			want:    "# if \"x\" 1",
+		},
+		{
+			name:    "text is untouched",
+			path:    "notes.txt",
+			content: "for 1",
+			want:    "for 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSyntax(tt.content, tt.path, styles); got != tt.want {
+				t.Errorf("highlightSyntax() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderHighlight(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/src/main.go", Content: "return 1"},
+			{Filepath: "/docs/notes.md", Content: "return 1"},
+		},
+		FormattingOptions: FormattingOptions{OutputFormat: "term"},
+	}
+	theme := &Theme{Name: "test", Styles: map[string]string{"syntax.keyword": "red"}}
+	ctx := &FormattingContext{Theme: theme, LineNumbers: LineNumberFile, Highlight: true}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	// The theme's keyword style wins, the defaults fill in the rest, and
+	// line numbers go on after the colors
+	goLine := "1 | \x1b[31mreturn" + ansiReset + " \x1b[35m1" + ansiReset
+	if !strings.Contains(result, goLine) {
+		t.Errorf("Expected highlighted Go code %q, got:\n%q", goLine, result)
+	}
+	if !strings.Contains(result, "1 | return 1\n") {
+		t.Errorf("Expected the markdown file untouched, got:\n%q", result)
+	}
+
+	// Without Highlight in the context nothing is colored
+	ctx.Highlight = false
+	result, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if strings.Contains(result, "\x1b[") {
+		t.Errorf("Expected no colors without Highlight, got:\n%q", result)
+	}
+}
@@ -0,0 +1,155 @@
+package nanodoc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/arthur-debert/nanodoc/pkg/markdown"
+)
+
+// externalLinkTimeout bounds each request made when checking external links
+const externalLinkTimeout = 10 * time.Second
+
+// LinkCheckResult contains the outcome of checking links across a document
+type LinkCheckResult struct {
+	// Number of links that were checked
+	Checked int
+	// Links whose targets could not be found
+	Broken []BrokenLink
+}
+
+// BrokenLink describes a link whose target does not exist
+type BrokenLink struct {
+	Source      string // Markdown file containing the link
+	Destination string // Link destination as written
+	IsImage     bool
+	Reason      string
+}
+
+// CheckLinks verifies relative link and image targets in the markdown files of
+// a document. Relative targets are resolved against the directory of the file
+// that contains them. External URLs are only checked when checkExternal is set.
+func CheckLinks(doc *Document, checkExternal bool) (*LinkCheckResult, error) {
+	result := &LinkCheckResult{Broken: make([]BrokenLink, 0)}
+	parser := markdown.NewParser()
+	transformer := markdown.NewTransformer()
+	client := &http.Client{Timeout: externalLinkTimeout}
+
+	for _, item := range doc.ContentItems {
+		if !isMarkdownFile(item.Filepath) {
+			continue
+		}
+
+		mdDoc, err := parser.Parse([]byte(item.Content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse content for file %s: %w", item.Filepath, err)
+		}
+
+		for _, link := range transformer.CollectLinks(mdDoc) {
+			target, err := url.Parse(link.Destination)
+			if err != nil {
+				result.Checked++
+				result.Broken = append(result.Broken, BrokenLink{
+					Source:      item.Filepath,
+					Destination: link.Destination,
+					IsImage:     link.IsImage,
+					Reason:      "malformed link",
+				})
+				continue
+			}
+
+			var reason string
+			switch {
+			case target.Scheme == "http" || target.Scheme == "https":
+				if !checkExternal {
+					continue
+				}
+				reason = checkExternalLink(client, link.Destination)
+			case target.Scheme != "" || target.Host != "":
+				// mailto:, ftp: and the like are never checked
+				continue
+			case target.Path == "":
+				// Same-document anchors
+				continue
+			default:
+				reason = checkRelativeLink(item.Filepath, target.Path)
+			}
+
+			result.Checked++
+			if reason != "" {
+				result.Broken = append(result.Broken, BrokenLink{
+					Source:      item.Filepath,
+					Destination: link.Destination,
+					IsImage:     link.IsImage,
+					Reason:      reason,
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// FormatLinkCheckOutput formats the link check result for display
+func FormatLinkCheckOutput(result *LinkCheckResult) string {
+	var output strings.Builder
+
+	for _, broken := range result.Broken {
+		kind := "link"
+		if broken.IsImage {
+			kind = "image"
+		}
+		output.WriteString(fmt.Sprintf("%s: broken %s %s (%s)\n", broken.Source, kind, broken.Destination, broken.Reason))
+	}
+
+	if len(result.Broken) > 0 {
+		output.WriteString("\n")
+	}
+	output.WriteString(fmt.Sprintf("Checked %d link(s), %d broken\n", result.Checked, len(result.Broken)))
+
+	return output.String()
+}
+
+// checkRelativeLink returns a reason when the target path does not exist
+func checkRelativeLink(sourcePath, targetPath string) string {
+	if unescaped, err := url.PathUnescape(targetPath); err == nil {
+		targetPath = unescaped
+	}
+
+	resolved := targetPath
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(sourcePath), filepath.FromSlash(targetPath))
+	}
+
+	if _, err := os.Stat(resolved); err != nil {
+		if os.IsNotExist(err) {
+			return "not found"
+		}
+		return err.Error()
+	}
+	return ""
+}
+
+// checkExternalLink returns a reason when the URL cannot be reached
+func checkExternalLink(client *http.Client, target string) string {
+	resp, err := client.Head(target)
+	if err != nil {
+		return err.Error()
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return ""
+}
+
+// isMarkdownFile reports whether the path has a markdown extension
+func isMarkdownFile(path string) bool {
+	return strings.HasSuffix(path, ".md") || strings.HasSuffix(path, ".markdown")
+}
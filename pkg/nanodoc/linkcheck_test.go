@@ -0,0 +1,61 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckLinks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "images", "logo.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "other.md"), []byte("# Other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	guide := filepath.Join(tempDir, "guide.md")
+	content := "# Guide\n\n" +
+		"See [other](other.md#intro) and [missing](missing.md).\n\n" +
+		"![logo](images/logo.png)\n\n" +
+		"Visit [site](https://example.com), [mail](mailto:a@b.c) or [top](#guide).\n"
+	if err := os.WriteFile(guide, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: guide, Content: content},
+			{Filepath: filepath.Join(tempDir, "notes.txt"), Content: "[ignored](nope.md)"},
+		},
+	}
+
+	result, err := CheckLinks(doc, false)
+	if err != nil {
+		t.Fatalf("CheckLinks() error = %v", err)
+	}
+
+	if result.Checked != 3 {
+		t.Errorf("Expected 3 checked links, got %d", result.Checked)
+	}
+	if len(result.Broken) != 1 {
+		t.Fatalf("Expected 1 broken link, got %d: %+v", len(result.Broken), result.Broken)
+	}
+	if result.Broken[0].Destination != "missing.md" || result.Broken[0].Source != guide {
+		t.Errorf("Unexpected broken link: %+v", result.Broken[0])
+	}
+
+	output := FormatLinkCheckOutput(result)
+	if !strings.Contains(output, "broken link missing.md (not found)") {
+		t.Errorf("Expected broken link in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Checked 3 link(s), 1 broken") {
+		t.Errorf("Expected summary in output, got:\n%s", output)
+	}
+}
@@ -2,13 +2,27 @@ package nanodoc
 
 import (
 	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // ParseBundleOptions parses bundle option lines using Cobra
 func ParseBundleOptions(optionLines []string) (FormattingOptions, error) {
+	opts, _, err := parseBundleOptions(optionLines)
+	return opts, err
+}
+
+// parseBundleOptions parses bundle option lines and also returns the command
+// they were parsed with, whose flags tell which options the lines set
+func parseBundleOptions(optionLines []string) (FormattingOptions, *cobra.Command, error) {
 	// Create a temporary command to parse options
 	tempCmd := &cobra.Command{}
 	
@@ -26,6 +40,46 @@ func ParseBundleOptions(optionLines []string) (FormattingOptions, error) {
 	var bundleIncludePatterns []string
 	var bundleExcludePatterns []string
 	var bundleOutputFormat string
+	var bundleFileIndex bool
+	var bundleFileIndexPosition string
+	var bundleMarkdownCodeFences bool
+	var bundleMarkdownCollapsible bool
+	var bundleMarkdownNormalize bool
+	var bundleOverflow string
+	var bundleUniqueBy string
+	var bundleOnDuplicate string
+	var bundleDedupeLines bool
+	var bundleDedupeLinesScope string
+	var bundleAsSection bool
+	var bundleSortBundle bool
+	var bundleRenderMarkdown bool
+	var bundleRenderMdTables bool
+	var bundleEmptyNoNumber bool
+	var bundleCommentSections bool
+	var bundleFootnotePaths bool
+	var bundleHeaderShowSize bool
+	var bundleHeaderShowRange bool
+	var bundleRTL bool
+	var bundleZebra bool
+	var bundleHighlight bool
+	var bundleTOCMaxEntries int
+	var bundleAnchorEvery int
+	var bundleJSONIncludeContent bool
+	var bundlePlainHeaders bool
+	var bundleSqueezeBlanks bool
+	var bundleBlankMarker bool
+	var bundleCompact bool
+	var bundleReflow bool
+	var bundleWrapWidth int
+	var bundleStrict bool
+	var bundleStripFrontMatter bool
+	var bundleTOCLinks bool
+	var bundleHeadersOnly bool
+	var bundleLineNumberScope string
+	var bundleLineNumberMinLines int
+	var bundleHeaderChar string
+	var bundleFileNumberReset string
+	var bundleSequenceStart int
 	
 	tempCmd.Flags().StringVarP(&bundleLineNum, "linenum", "l", "", "")
 	tempCmd.Flags().BoolVar(&bundleToc, "toc", false, "")
@@ -40,6 +94,46 @@ func ParseBundleOptions(optionLines []string) (FormattingOptions, error) {
 	tempCmd.Flags().StringSliceVar(&bundleIncludePatterns, "include", []string{}, "")
 	tempCmd.Flags().StringSliceVar(&bundleExcludePatterns, "exclude", []string{}, "")
 	tempCmd.Flags().StringVar(&bundleOutputFormat, "output-format", "term", "")
+	tempCmd.Flags().BoolVar(&bundleFileIndex, "file-index", false, "")
+	tempCmd.Flags().StringVar(&bundleFileIndexPosition, "file-index-position", FileIndexBeforeTOC, "")
+	tempCmd.Flags().BoolVar(&bundleMarkdownCodeFences, "md-code-fences", false, "")
+	tempCmd.Flags().BoolVar(&bundleMarkdownCollapsible, "md-collapsible", false, "")
+	tempCmd.Flags().BoolVar(&bundleMarkdownNormalize, "md-normalize", false, "")
+	tempCmd.Flags().StringVar(&bundleOverflow, "overflow", OverflowNone, "")
+	tempCmd.Flags().StringVar(&bundleUniqueBy, "unique-by", UniqueByNone, "")
+	tempCmd.Flags().StringVar(&bundleOnDuplicate, "on-duplicate", DuplicateKeep, "")
+	tempCmd.Flags().BoolVar(&bundleDedupeLines, "dedupe-lines", false, "")
+	tempCmd.Flags().StringVar(&bundleDedupeLinesScope, "dedupe-lines-scope", DedupeScopeDocument, "")
+	tempCmd.Flags().BoolVar(&bundleAsSection, "bundle-as-section", false, "")
+	tempCmd.Flags().BoolVar(&bundleSortBundle, "sort-bundle", false, "")
+	tempCmd.Flags().BoolVar(&bundleRenderMarkdown, "render-markdown", false, "")
+	tempCmd.Flags().BoolVar(&bundleRenderMdTables, "render-markdown-tables", false, "")
+	tempCmd.Flags().BoolVar(&bundleEmptyNoNumber, "empty-no-number", false, "")
+	tempCmd.Flags().BoolVar(&bundleCommentSections, "bundle-comments-as-sections", false, "")
+	tempCmd.Flags().BoolVar(&bundleFootnotePaths, "footnote-paths", false, "")
+	tempCmd.Flags().BoolVar(&bundleHeaderShowSize, "header-show-size", false, "")
+	tempCmd.Flags().BoolVar(&bundleHeaderShowRange, "header-show-range", false, "")
+	tempCmd.Flags().BoolVar(&bundleRTL, "rtl", false, "")
+	tempCmd.Flags().BoolVar(&bundleZebra, "zebra", false, "")
+	tempCmd.Flags().BoolVar(&bundleHighlight, "highlight", false, "")
+	tempCmd.Flags().IntVar(&bundleTOCMaxEntries, "toc-max-entries", 0, "")
+	tempCmd.Flags().IntVar(&bundleAnchorEvery, "anchor-every", 0, "")
+	tempCmd.Flags().BoolVar(&bundleJSONIncludeContent, "json-include-content", true, "")
+	tempCmd.Flags().BoolVar(&bundlePlainHeaders, "plain-headers", false, "")
+	tempCmd.Flags().BoolVar(&bundleSqueezeBlanks, "squeeze-blanks", false, "")
+	tempCmd.Flags().BoolVar(&bundleBlankMarker, "blank-marker", false, "")
+	tempCmd.Flags().BoolVar(&bundleCompact, "compact", false, "")
+	tempCmd.Flags().BoolVar(&bundleReflow, "reflow", false, "")
+	tempCmd.Flags().IntVar(&bundleWrapWidth, "wrap", 0, "")
+	tempCmd.Flags().BoolVar(&bundleStrict, "strict", false, "")
+	tempCmd.Flags().BoolVar(&bundleStripFrontMatter, "strip-front-matter", false, "")
+	tempCmd.Flags().BoolVar(&bundleTOCLinks, "toc-links", false, "")
+	tempCmd.Flags().BoolVar(&bundleHeadersOnly, "headers-only", false, "")
+	tempCmd.Flags().StringVar(&bundleLineNumberScope, "linenum-scope", LineNumberScopeAll, "")
+	tempCmd.Flags().IntVar(&bundleLineNumberMinLines, "linenum-min-lines", 0, "")
+	tempCmd.Flags().StringVar(&bundleHeaderChar, "header-char", "", "")
+	tempCmd.Flags().StringVar(&bundleFileNumberReset, "file-number-reset", FileNumberResetNone, "")
+	tempCmd.Flags().IntVar(&bundleSequenceStart, "seq-start", 1, "")
 	
 	// Parse the option lines
 	// Need to split options that have values into separate elements
@@ -51,7 +145,7 @@ func ParseBundleOptions(optionLines []string) (FormattingOptions, error) {
 	}
 	
 	if err := tempCmd.ParseFlags(args); err != nil {
-		return FormattingOptions{}, err
+		return FormattingOptions{}, nil, err
 	}
 	
 	// Convert to FormattingOptions
@@ -64,20 +158,60 @@ func ParseBundleOptions(optionLines []string) (FormattingOptions, error) {
 	}
 	
 	return FormattingOptions{
-		LineNumbers:          lineNumberMode,
-		ShowTOC:              bundleToc,
-		Theme:                bundleTheme,
-		ShowFilenames:        bundleShowFilenames,
-		SequenceStyle:        SequenceStyle(bundleFileNumbering),
-		HeaderFormat:         HeaderFormat(bundleFilenameFormat),
-		HeaderAlignment:      bundleFilenameAlign,
-		HeaderStyle:          bundleFilenameBanner,
-		PageWidth:            bundlePageWidth,
-		AdditionalExtensions: bundleAdditionalExt,
-		IncludePatterns:      bundleIncludePatterns,
-		ExcludePatterns:      bundleExcludePatterns,
-		OutputFormat:         bundleOutputFormat,
-	}, nil
+		LineNumbers:              lineNumberMode,
+		ShowTOC:                  bundleToc,
+		Theme:                    bundleTheme,
+		ShowFilenames:            bundleShowFilenames,
+		SequenceStyle:            SequenceStyle(bundleFileNumbering),
+		HeaderFormat:             HeaderFormat(bundleFilenameFormat),
+		HeaderAlignment:          bundleFilenameAlign,
+		HeaderStyle:              bundleFilenameBanner,
+		PageWidth:                bundlePageWidth,
+		AdditionalExtensions:     bundleAdditionalExt,
+		IncludePatterns:          bundleIncludePatterns,
+		ExcludePatterns:          bundleExcludePatterns,
+		OutputFormat:             bundleOutputFormat,
+		ShowFileIndex:            bundleFileIndex,
+		FileIndexPosition:        bundleFileIndexPosition,
+		MarkdownCodeFences:       bundleMarkdownCodeFences,
+		MarkdownCollapsible:      bundleMarkdownCollapsible,
+		MarkdownNormalize:        bundleMarkdownNormalize,
+		Overflow:                 bundleOverflow,
+		UniqueBy:                 bundleUniqueBy,
+		OnDuplicate:              bundleOnDuplicate,
+		DedupeLines:              bundleDedupeLines,
+		DedupeLinesScope:         bundleDedupeLinesScope,
+		BundleAsSection:          bundleAsSection,
+		SortBundle:               bundleSortBundle,
+		RenderMarkdown:           bundleRenderMarkdown,
+		RenderMarkdownTables:     bundleRenderMdTables,
+		EmptyNoNumber:            bundleEmptyNoNumber,
+		BundleCommentsAsSections: bundleCommentSections,
+		FootnotePaths:            bundleFootnotePaths,
+		HeaderShowSize:           bundleHeaderShowSize,
+		HeaderShowRange:          bundleHeaderShowRange,
+		RTL:                      bundleRTL,
+		Zebra:                    bundleZebra,
+		Highlight:                bundleHighlight,
+		TOCMaxEntries:            bundleTOCMaxEntries,
+		AnchorEvery:              bundleAnchorEvery,
+		JSONOmitContent:          !bundleJSONIncludeContent,
+		PlainHeaders:             bundlePlainHeaders,
+		SqueezeBlanks:            bundleSqueezeBlanks,
+		BlankMarker:              bundleBlankMarker,
+		Compact:                  bundleCompact,
+		Reflow:                   bundleReflow,
+		WrapWidth:                bundleWrapWidth,
+		Strict:                   bundleStrict,
+		StripFrontMatter:         bundleStripFrontMatter,
+		TOCLinks:                 bundleTOCLinks,
+		HeadersOnly:              bundleHeadersOnly,
+		LineNumberScope:          bundleLineNumberScope,
+		LineNumberMinLines:       bundleLineNumberMinLines,
+		HeaderChar:               bundleHeaderChar,
+		FileNumberReset:          bundleFileNumberReset,
+		SequenceStart:            bundleSequenceStart,
+	}, tempCmd, nil
 }
 
 // TrackExplicitFlags determines which flags were explicitly set by the user
@@ -124,6 +258,126 @@ func TrackExplicitFlags(cmd *cobra.Command) map[string]bool {
 	if cmd.Flags().Changed("output-format") {
 		explicitFlags["output-format"] = true
 	}
+	if cmd.Flags().Changed("file-index") {
+		explicitFlags["file-index"] = true
+	}
+	if cmd.Flags().Changed("file-index-position") {
+		explicitFlags["file-index-position"] = true
+	}
+	if cmd.Flags().Changed("md-code-fences") {
+		explicitFlags["md-code-fences"] = true
+	}
+	if cmd.Flags().Changed("md-collapsible") {
+		explicitFlags["md-collapsible"] = true
+	}
+	if cmd.Flags().Changed("md-normalize") {
+		explicitFlags["md-normalize"] = true
+	}
+	if cmd.Flags().Changed("overflow") {
+		explicitFlags["overflow"] = true
+	}
+	if cmd.Flags().Changed("unique-by") {
+		explicitFlags["unique-by"] = true
+	}
+	if cmd.Flags().Changed("on-duplicate") {
+		explicitFlags["on-duplicate"] = true
+	}
+	if cmd.Flags().Changed("dedupe-lines") {
+		explicitFlags["dedupe-lines"] = true
+	}
+	if cmd.Flags().Changed("dedupe-lines-scope") {
+		explicitFlags["dedupe-lines-scope"] = true
+	}
+	if cmd.Flags().Changed("bundle-as-section") {
+		explicitFlags["bundle-as-section"] = true
+	}
+	if cmd.Flags().Changed("sort-bundle") {
+		explicitFlags["sort-bundle"] = true
+	}
+	if cmd.Flags().Changed("render-markdown") {
+		explicitFlags["render-markdown"] = true
+	}
+	if cmd.Flags().Changed("render-markdown-tables") {
+		explicitFlags["render-markdown-tables"] = true
+	}
+	if cmd.Flags().Changed("empty-no-number") {
+		explicitFlags["empty-no-number"] = true
+	}
+	if cmd.Flags().Changed("bundle-comments-as-sections") {
+		explicitFlags["bundle-comments-as-sections"] = true
+	}
+	if cmd.Flags().Changed("footnote-paths") {
+		explicitFlags["footnote-paths"] = true
+	}
+	if cmd.Flags().Changed("header-show-size") {
+		explicitFlags["header-show-size"] = true
+	}
+	if cmd.Flags().Changed("header-show-range") {
+		explicitFlags["header-show-range"] = true
+	}
+	if cmd.Flags().Changed("rtl") {
+		explicitFlags["rtl"] = true
+	}
+	if cmd.Flags().Changed("zebra") {
+		explicitFlags["zebra"] = true
+	}
+	if cmd.Flags().Changed("highlight") {
+		explicitFlags["highlight"] = true
+	}
+	if cmd.Flags().Changed("toc-max-entries") {
+		explicitFlags["toc-max-entries"] = true
+	}
+	if cmd.Flags().Changed("anchor-every") {
+		explicitFlags["anchor-every"] = true
+	}
+	if cmd.Flags().Changed("json-include-content") {
+		explicitFlags["json-include-content"] = true
+	}
+	if cmd.Flags().Changed("plain-headers") {
+		explicitFlags["plain-headers"] = true
+	}
+	if cmd.Flags().Changed("squeeze-blanks") {
+		explicitFlags["squeeze-blanks"] = true
+	}
+	if cmd.Flags().Changed("blank-marker") {
+		explicitFlags["blank-marker"] = true
+	}
+	if cmd.Flags().Changed("compact") {
+		explicitFlags["compact"] = true
+	}
+	if cmd.Flags().Changed("reflow") {
+		explicitFlags["reflow"] = true
+	}
+	if cmd.Flags().Changed("wrap") {
+		explicitFlags["wrap"] = true
+	}
+	if cmd.Flags().Changed("strict") {
+		explicitFlags["strict"] = true
+	}
+	if cmd.Flags().Changed("strip-front-matter") {
+		explicitFlags["strip-front-matter"] = true
+	}
+	if cmd.Flags().Changed("toc-links") {
+		explicitFlags["toc-links"] = true
+	}
+	if cmd.Flags().Changed("headers-only") {
+		explicitFlags["headers-only"] = true
+	}
+	if cmd.Flags().Changed("linenum-scope") {
+		explicitFlags["linenum-scope"] = true
+	}
+	if cmd.Flags().Changed("linenum-min-lines") {
+		explicitFlags["linenum-min-lines"] = true
+	}
+	if cmd.Flags().Changed("header-char") {
+		explicitFlags["header-char"] = true
+	}
+	if cmd.Flags().Changed("file-number-reset") {
+		explicitFlags["file-number-reset"] = true
+	}
+	if cmd.Flags().Changed("seq-start") {
+		explicitFlags["seq-start"] = true
+	}
 	
 	return explicitFlags
 }
@@ -163,23 +417,233 @@ func MergeOptionsWithExplicitFlags(bundleOpts, cmdOpts FormattingOptions, explic
 	
 	// Merge additional extensions (bundle + command line)
 	if len(bundleOpts.AdditionalExtensions) > 0 && !explicitFlags["txt-ext"] {
-		result.AdditionalExtensions = append(bundleOpts.AdditionalExtensions, result.AdditionalExtensions...)
+		result.AdditionalExtensions = concatStrings(bundleOpts.AdditionalExtensions, result.AdditionalExtensions)
 	}
 	
 	// Merge patterns
 	if len(bundleOpts.IncludePatterns) > 0 && !explicitFlags["include"] {
-		result.IncludePatterns = append(bundleOpts.IncludePatterns, result.IncludePatterns...)
+		result.IncludePatterns = concatStrings(bundleOpts.IncludePatterns, result.IncludePatterns)
 	}
 	if len(bundleOpts.ExcludePatterns) > 0 && !explicitFlags["exclude"] {
-		result.ExcludePatterns = append(bundleOpts.ExcludePatterns, result.ExcludePatterns...)
+		result.ExcludePatterns = concatStrings(bundleOpts.ExcludePatterns, result.ExcludePatterns)
 	}
 	if !explicitFlags["output-format"] {
 		result.OutputFormat = bundleOpts.OutputFormat
 	}
+	if !explicitFlags["file-index"] {
+		result.ShowFileIndex = bundleOpts.ShowFileIndex
+	}
+	if !explicitFlags["file-index-position"] {
+		result.FileIndexPosition = bundleOpts.FileIndexPosition
+	}
+	if !explicitFlags["md-code-fences"] {
+		result.MarkdownCodeFences = bundleOpts.MarkdownCodeFences
+	}
+	if !explicitFlags["md-collapsible"] {
+		result.MarkdownCollapsible = bundleOpts.MarkdownCollapsible
+	}
+	if !explicitFlags["md-normalize"] {
+		result.MarkdownNormalize = bundleOpts.MarkdownNormalize
+	}
+	if !explicitFlags["overflow"] {
+		result.Overflow = bundleOpts.Overflow
+	}
+	if !explicitFlags["unique-by"] {
+		result.UniqueBy = bundleOpts.UniqueBy
+	}
+	if !explicitFlags["on-duplicate"] {
+		result.OnDuplicate = bundleOpts.OnDuplicate
+	}
+	if !explicitFlags["dedupe-lines"] {
+		result.DedupeLines = bundleOpts.DedupeLines
+	}
+	if !explicitFlags["dedupe-lines-scope"] {
+		result.DedupeLinesScope = bundleOpts.DedupeLinesScope
+	}
+	if !explicitFlags["bundle-as-section"] {
+		result.BundleAsSection = bundleOpts.BundleAsSection
+	}
+	if !explicitFlags["sort-bundle"] {
+		result.SortBundle = bundleOpts.SortBundle
+	}
+	if !explicitFlags["render-markdown"] {
+		result.RenderMarkdown = bundleOpts.RenderMarkdown
+	}
+	if !explicitFlags["render-markdown-tables"] {
+		result.RenderMarkdownTables = bundleOpts.RenderMarkdownTables
+	}
+	if !explicitFlags["empty-no-number"] {
+		result.EmptyNoNumber = bundleOpts.EmptyNoNumber
+	}
+	if !explicitFlags["bundle-comments-as-sections"] {
+		result.BundleCommentsAsSections = bundleOpts.BundleCommentsAsSections
+	}
+	if !explicitFlags["footnote-paths"] {
+		result.FootnotePaths = bundleOpts.FootnotePaths
+	}
+	if !explicitFlags["header-show-size"] {
+		result.HeaderShowSize = bundleOpts.HeaderShowSize
+	}
+	if !explicitFlags["header-show-range"] {
+		result.HeaderShowRange = bundleOpts.HeaderShowRange
+	}
+	if !explicitFlags["rtl"] {
+		result.RTL = bundleOpts.RTL
+	}
+	if !explicitFlags["zebra"] {
+		result.Zebra = bundleOpts.Zebra
+	}
+	if !explicitFlags["highlight"] {
+		result.Highlight = bundleOpts.Highlight
+	}
+	if !explicitFlags["toc-max-entries"] {
+		result.TOCMaxEntries = bundleOpts.TOCMaxEntries
+	}
+	if !explicitFlags["anchor-every"] {
+		result.AnchorEvery = bundleOpts.AnchorEvery
+	}
+	if !explicitFlags["json-include-content"] {
+		result.JSONOmitContent = bundleOpts.JSONOmitContent
+	}
+	if !explicitFlags["plain-headers"] {
+		result.PlainHeaders = bundleOpts.PlainHeaders
+	}
+	if !explicitFlags["squeeze-blanks"] {
+		result.SqueezeBlanks = bundleOpts.SqueezeBlanks
+	}
+	if !explicitFlags["blank-marker"] {
+		result.BlankMarker = bundleOpts.BlankMarker
+	}
+	if !explicitFlags["compact"] {
+		result.Compact = bundleOpts.Compact
+	}
+	if !explicitFlags["reflow"] {
+		result.Reflow = bundleOpts.Reflow
+	}
+	if !explicitFlags["wrap"] {
+		result.WrapWidth = bundleOpts.WrapWidth
+	}
+	if !explicitFlags["strict"] {
+		result.Strict = bundleOpts.Strict
+	}
+	if !explicitFlags["strip-front-matter"] {
+		result.StripFrontMatter = bundleOpts.StripFrontMatter
+	}
+	if !explicitFlags["toc-links"] {
+		result.TOCLinks = bundleOpts.TOCLinks
+	}
+	if !explicitFlags["headers-only"] {
+		result.HeadersOnly = bundleOpts.HeadersOnly
+	}
+	if !explicitFlags["linenum-scope"] {
+		result.LineNumberScope = bundleOpts.LineNumberScope
+	}
+	if !explicitFlags["linenum-min-lines"] {
+		result.LineNumberMinLines = bundleOpts.LineNumberMinLines
+	}
+	if !explicitFlags["header-char"] {
+		result.HeaderChar = bundleOpts.HeaderChar
+	}
+	if !explicitFlags["file-number-reset"] {
+		result.FileNumberReset = bundleOpts.FileNumberReset
+	}
+	if !explicitFlags["seq-start"] {
+		result.SequenceStart = bundleOpts.SequenceStart
+	}
 	
 	return result
 }
 
+// MergeBundleOptions reads the option lines of the bundles among pathInfos,
+// merges them with opts and applies the presets that depend on the result.
+// Options named in explicitFlags keep their value from opts; the others take
+// the value the bundles give them. The merged options are validated; option
+// lines that do not parse and invalid values give an *OptionError.
+func MergeBundleOptions(pathInfos []PathInfo, opts FormattingOptions, explicitFlags map[string]bool) (FormattingOptions, error) {
+	bundleOptionLines, err := ExtractBundleOptionLinesWithOptions(pathInfos, opts)
+	if err != nil {
+		return FormattingOptions{}, fmt.Errorf("error extracting bundle options: %w", err)
+	}
+
+	merged := opts
+	if len(bundleOptionLines) > 0 {
+		bundleOpts, err := ParseBundleOptions(bundleOptionLines)
+		if err != nil {
+			return FormattingOptions{}, &OptionError{Err: fmt.Errorf("error parsing bundle options: %w", err)}
+		}
+		merged = MergeOptionsWithExplicitFlags(bundleOpts, opts, explicitFlags)
+	}
+	merged = ApplyDirectionDefaults(merged, explicitFlags)
+	merged = ApplyCompactPreset(merged, explicitFlags)
+
+	// Options from bundles are checked like those from the command line
+	if err := merged.Validate(); err != nil {
+		return FormattingOptions{}, &OptionError{Err: err}
+	}
+	return merged, nil
+}
+
+// explicitFlagsOf returns the merge keys of the options in opts that differ
+// from their defaults. Callers without a command line use it to treat the
+// options they changed as given explicitly.
+func explicitFlagsOf(opts FormattingOptions) (map[string]bool, error) {
+	defaults, cmd, err := parseBundleOptions(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark every option as set to list all the merge keys
+	cmd.Flags().VisitAll(func(f *pflag.Flag) { f.Changed = true })
+
+	// An option differs from its default when taking it from opts changes
+	// the merged result
+	base := MergeOptionsWithExplicitFlags(defaults, opts, nil)
+	explicitFlags := make(map[string]bool)
+	for key := range TrackExplicitFlags(cmd) {
+		merged := MergeOptionsWithExplicitFlags(defaults, opts, map[string]bool{key: true})
+		if !reflect.DeepEqual(merged, base) {
+			explicitFlags[key] = true
+		}
+	}
+	return explicitFlags, nil
+}
+
+// ApplyDirectionDefaults right-aligns headers in right-to-left output unless
+// --header-align was given on the command line
+func ApplyDirectionDefaults(opts FormattingOptions, explicitFlags map[string]bool) FormattingOptions {
+	if opts.RTL && !explicitFlags["header-align"] && (opts.HeaderAlignment == "" || opts.HeaderAlignment == "left") {
+		opts.HeaderAlignment = "right"
+	}
+	return opts
+}
+
+// ApplyCompactPreset fills in the settings --compact stands for: inline
+// headers, squeezed blank lines and no TOC. Each is left alone when its own
+// flag was given on the command line.
+func ApplyCompactPreset(opts FormattingOptions, explicitFlags map[string]bool) FormattingOptions {
+	if !opts.Compact {
+		return opts
+	}
+	if !explicitFlags["header-style"] {
+		opts.HeaderStyle = InlineHeaderStyle
+	}
+	if !explicitFlags["squeeze-blanks"] {
+		opts.SqueezeBlanks = true
+	}
+	if !explicitFlags["toc"] {
+		opts.ShowTOC = false
+	}
+	return opts
+}
+
+// concatStrings returns a new slice holding a followed by b, so merged
+// options never share a backing array with the bundle options
+func concatStrings(a, b []string) []string {
+	result := make([]string, 0, len(a)+len(b))
+	result = append(result, a...)
+	return append(result, b...)
+}
+
 // BuildFormattingOptions constructs FormattingOptions from command-line flag values
 func BuildFormattingOptions(
 	lineNum string,
@@ -210,8 +674,8 @@ func BuildFormattingOptions(
 	}
 
 	// Validate output format
-	if outputFormat != "term" && outputFormat != "plain" && outputFormat != "markdown" {
-		return FormattingOptions{}, fmt.Errorf("invalid --output-format value: %s (must be 'term', 'plain', or 'markdown')", outputFormat)
+	if !isValidOutputFormat(outputFormat) {
+		return FormattingOptions{}, fmt.Errorf("invalid --output-format value: %s (must be %s)", outputFormat, outputFormatChoices())
 	}
 
 	return FormattingOptions{
@@ -229,4 +693,170 @@ func BuildFormattingOptions(
 		ExcludePatterns:      excludePatterns,
 		OutputFormat:         outputFormat,
 	}, nil
-}
\ No newline at end of file
+}
+
+// Validate checks the options that are not covered by BuildFormattingOptions
+func (opts FormattingOptions) Validate() error {
+	if opts.FileIndexPosition != "" && opts.FileIndexPosition != FileIndexBeforeTOC && opts.FileIndexPosition != FileIndexAfterTOC {
+		return fmt.Errorf("invalid --file-index-position value: %s (must be '%s' or '%s')", opts.FileIndexPosition, FileIndexBeforeTOC, FileIndexAfterTOC)
+	}
+	switch opts.Overflow {
+	case "", OverflowNone, OverflowTruncate, OverflowWrap:
+	default:
+		return fmt.Errorf("invalid --overflow value: %s (must be '%s', '%s' or '%s')", opts.Overflow, OverflowNone, OverflowTruncate, OverflowWrap)
+	}
+	switch opts.UniqueBy {
+	case "", UniqueByNone, UniqueByBasename, UniqueByDir:
+	default:
+		return fmt.Errorf("invalid --unique-by value: %s (must be '%s', '%s' or '%s')", opts.UniqueBy, UniqueByNone, UniqueByBasename, UniqueByDir)
+	}
+	switch opts.OnDuplicate {
+	case "", DuplicateKeep, DuplicateSkip, DuplicateError:
+	default:
+		return fmt.Errorf("invalid --on-duplicate value: %s (must be '%s', '%s' or '%s')", opts.OnDuplicate, DuplicateKeep, DuplicateSkip, DuplicateError)
+	}
+	switch opts.DedupeLinesScope {
+	case "", DedupeScopeDocument, DedupeScopeFile:
+	default:
+		return fmt.Errorf("invalid --dedupe-lines-scope value: %s (must be '%s' or '%s')", opts.DedupeLinesScope, DedupeScopeDocument, DedupeScopeFile)
+	}
+	switch opts.LineNumberScope {
+	case "", LineNumberScopeAll, LineNumberScopeCode, LineNumberScopeText:
+	default:
+		return fmt.Errorf("invalid --linenum-scope value: %s (must be '%s', '%s' or '%s')", opts.LineNumberScope, LineNumberScopeAll, LineNumberScopeCode, LineNumberScopeText)
+	}
+	if opts.TOCMaxEntries < 0 {
+		return fmt.Errorf("invalid --toc-max-entries value: %d (must be 0 or more)", opts.TOCMaxEntries)
+	}
+	if opts.WrapWidth < 0 {
+		return fmt.Errorf("invalid --wrap value: %d (must be 0 or more)", opts.WrapWidth)
+	}
+	if opts.LineNumberMinLines < 0 {
+		return fmt.Errorf("invalid --linenum-min-lines value: %d (must be 0 or more)", opts.LineNumberMinLines)
+	}
+	switch opts.FileNumberReset {
+	case "", FileNumberResetNone, FileNumberResetBundle, FileNumberResetDir:
+	default:
+		return fmt.Errorf("invalid --file-number-reset value: %s (must be '%s', '%s' or '%s')", opts.FileNumberReset, FileNumberResetNone, FileNumberResetBundle, FileNumberResetDir)
+	}
+	// 0 is the unset value, which starts at 1 like the flag's default
+	if opts.SequenceStart < 0 {
+		return fmt.Errorf("invalid --seq-start value: %d (must be 1 or more)", opts.SequenceStart)
+	}
+	if opts.HeaderChar != "" && utf8.RuneCountInString(opts.HeaderChar) != 1 {
+		return fmt.Errorf("invalid --header-char value: %q (must be a single character)", opts.HeaderChar)
+	}
+	if opts.AnchorEvery < 0 {
+		return fmt.Errorf("invalid --anchor-every value: %d (must be 0 or more)", opts.AnchorEvery)
+	}
+	switch opts.HeaderFormat {
+	case "", HeaderFormatNice, HeaderFormatFilename, HeaderFormatPath, HeaderFormatRelative:
+	default:
+		return fmt.Errorf("invalid --header-format value: %s (must be '%s', '%s', '%s' or '%s')", opts.HeaderFormat, HeaderFormatNice, HeaderFormatFilename, HeaderFormatPath, HeaderFormatRelative)
+	}
+	switch opts.OnOversize {
+	case "", OversizeSkip, OversizeError:
+	default:
+		return fmt.Errorf("invalid --on-oversize value: %s (must be '%s' or '%s')", opts.OnOversize, OversizeSkip, OversizeError)
+	}
+	for _, pair := range opts.MarkdownFrontMatter {
+		if key, _, found := strings.Cut(pair, "="); !found || !frontMatterKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid --md-frontmatter value: %s (must be KEY=VALUE)", pair)
+		}
+	}
+	// The spec applies to every file, so it is checked before any is read;
+	// "$" lines count from the end of a file as long as any
+	if opts.HighlightLines != "" {
+		if _, err := parseRanges(opts.HighlightLines, math.MaxInt32); err != nil {
+			return fmt.Errorf("invalid --highlight-lines value: %w", err)
+		}
+	}
+	return nil
+}
+
+// fileSizeUnits are the multipliers of the size suffixes ParseFileSize
+// accepts, in powers of 1024 to match formatFileSize
+var fileSizeUnits = map[string]float64{
+	"":  1,
+	"B": 1,
+	"K": 1 << 10, "KB": 1 << 10, "KIB": 1 << 10,
+	"M": 1 << 20, "MB": 1 << 20, "MIB": 1 << 20,
+	"G": 1 << 30, "GB": 1 << 30, "GIB": 1 << 30,
+}
+
+// frontMatterKeyPattern matches the keys accepted by --md-frontmatter
+var frontMatterKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// fileSizePattern splits a size like "10MB" or "1.5 G" into number and unit
+var fileSizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([A-Za-z]*)$`)
+
+// ParseFileSize parses a size given with --max-file-size, e.g. "512", "64KB"
+// or "1.5G", into bytes. Units are case insensitive and 1KB is 1024 bytes.
+func ParseFileSize(value string) (int64, error) {
+	match := fileSizePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid --max-file-size value: %s (must be a size like 10MB)", value)
+	}
+	unit, ok := fileSizeUnits[strings.ToUpper(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid --max-file-size value: %s (unknown unit %q)", value, match[2])
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --max-file-size value: %s (must be a size like 10MB)", value)
+	}
+	return int64(number * unit), nil
+}
+
+// ParseBundleVars parses KEY=VALUE pairs given with --bundle-var
+func ParseBundleVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || !bundleVarNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid --bundle-var value: %s (must be KEY=VALUE)", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// FormatOptionsDump renders every field of opts as a Name=value line, in
+// declaration order, so the options actually used for rendering can be inspected
+func FormatOptionsDump(opts FormattingOptions) string {
+	var b strings.Builder
+	value := reflect.ValueOf(opts)
+	for i := 0; i < value.NumField(); i++ {
+		b.WriteString(fmt.Sprintf("%s=%s\n", value.Type().Field(i).Name, formatOptionValue(value.Field(i))))
+	}
+	return b.String()
+}
+
+// formatOptionValue formats a single option field, sorting map keys and
+// showing unset pointers as <nil>
+func formatOptionValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "<nil>"
+		}
+		return formatOptionValue(v.Elem())
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatOptionValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case reflect.Map:
+		items := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			items = append(items, fmt.Sprintf("%v:%v", key.Interface(), v.MapIndex(key).Interface()))
+		}
+		sort.Strings(items)
+		return "{" + strings.Join(items, ",") + "}"
+	case reflect.String:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
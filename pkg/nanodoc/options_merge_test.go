@@ -0,0 +1,274 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mergeBundleOpts and mergeCmdOpts differ in every field that bundles can set,
+// so each precedence case can tell which side a merged value came from
+func mergeBundleOpts() FormattingOptions {
+	return FormattingOptions{
+		Theme:                    "classic-dark",
+		LineNumbers:              LineNumberGlobal,
+		ShowFilenames:            false,
+		HeaderFormat:             HeaderFormatPath,
+		SequenceStyle:            SequenceRoman,
+		ShowTOC:                  true,
+		HeaderAlignment:          "center",
+		HeaderStyle:              "dashed",
+		PageWidth:                100,
+		AdditionalExtensions:     []string{"go"},
+		IncludePatterns:          []string{"**/*.md"},
+		ExcludePatterns:          []string{"vendor/**"},
+		OutputFormat:             "markdown",
+		ShowFileIndex:            true,
+		FileIndexPosition:        FileIndexAfterTOC,
+		MarkdownCodeFences:       true,
+		MarkdownCollapsible:      true,
+		MarkdownNormalize:        true,
+		Overflow:                 OverflowWrap,
+		UniqueBy:                 UniqueByBasename,
+		OnDuplicate:              DuplicateSkip,
+		DedupeLines:              true,
+		DedupeLinesScope:         DedupeScopeFile,
+		BundleAsSection:          true,
+		SortBundle:               true,
+		RenderMarkdown:           true,
+		RenderMarkdownTables:     true,
+		EmptyNoNumber:            true,
+		BundleCommentsAsSections: true,
+		FootnotePaths:            true,
+		HeaderShowSize:           true,
+		HeaderShowRange:          true,
+		RTL:                      true,
+		Zebra:                    true,
+		Highlight:                true,
+		TOCMaxEntries:            10,
+		AnchorEvery:              50,
+		JSONOmitContent:          true,
+		PlainHeaders:             true,
+		SqueezeBlanks:            true,
+		BlankMarker:              true,
+		Compact:                  true,
+		Reflow:                   true,
+		WrapWidth:                72,
+		Strict:                   true,
+		StripFrontMatter:         true,
+		TOCLinks:                 true,
+		HeadersOnly:              true,
+		LineNumberScope:          LineNumberScopeCode,
+		LineNumberMinLines:       20,
+		HeaderChar:               "*",
+		FileNumberReset:          FileNumberResetBundle,
+		SequenceStart:            27,
+	}
+}
+
+func mergeCmdOpts() FormattingOptions {
+	return FormattingOptions{
+		Theme:                    "classic-light",
+		LineNumbers:              LineNumberFile,
+		ShowFilenames:            true,
+		HeaderFormat:             HeaderFormatNice,
+		SequenceStyle:            SequenceNumerical,
+		ShowTOC:                  false,
+		HeaderAlignment:          "left",
+		HeaderStyle:              "none",
+		PageWidth:                80,
+		AdditionalExtensions:     []string{"py"},
+		IncludePatterns:          []string{"docs/**"},
+		ExcludePatterns:          []string{"*.log"},
+		OutputFormat:             "term",
+		ShowFileIndex:            false,
+		FileIndexPosition:        FileIndexBeforeTOC,
+		MarkdownCodeFences:       false,
+		MarkdownCollapsible:      false,
+		MarkdownNormalize:        false,
+		Overflow:                 OverflowNone,
+		UniqueBy:                 UniqueByNone,
+		OnDuplicate:              DuplicateKeep,
+		DedupeLines:              false,
+		DedupeLinesScope:         DedupeScopeDocument,
+		BundleAsSection:          false,
+		SortBundle:               false,
+		RenderMarkdown:           false,
+		RenderMarkdownTables:     false,
+		EmptyNoNumber:            false,
+		BundleCommentsAsSections: false,
+		FootnotePaths:            false,
+		HeaderShowSize:           false,
+		HeaderShowRange:          false,
+		RTL:                      false,
+		Zebra:                    false,
+		Highlight:                false,
+		TOCMaxEntries:            3,
+		AnchorEvery:              0,
+		JSONOmitContent:          false,
+		PlainHeaders:             false,
+		SqueezeBlanks:            false,
+		BlankMarker:              false,
+		Compact:                  false,
+		Reflow:                   false,
+		WrapWidth:                0,
+		Strict:                   false,
+		StripFrontMatter:         false,
+		TOCLinks:                 false,
+		HeadersOnly:              false,
+		LineNumberScope:          LineNumberScopeAll,
+		LineNumberMinLines:       0,
+		HeaderChar:               "~",
+		FileNumberReset:          FileNumberResetNone,
+		SequenceStart:            1,
+	}
+}
+
+func TestMergeOptionsPrecedence(t *testing.T) {
+	tests := []struct {
+		flag string
+		get  func(FormattingOptions) interface{}
+		// merged is the expected value when the flag is not explicit
+		merged interface{}
+	}{
+		{"theme", func(o FormattingOptions) interface{} { return o.Theme }, "classic-dark"},
+		{"line-numbers", func(o FormattingOptions) interface{} { return o.LineNumbers }, LineNumberGlobal},
+		{"no-header", func(o FormattingOptions) interface{} { return o.ShowFilenames }, false},
+		{"header-format", func(o FormattingOptions) interface{} { return o.HeaderFormat }, HeaderFormatPath},
+		{"sequence", func(o FormattingOptions) interface{} { return o.SequenceStyle }, SequenceRoman},
+		{"toc", func(o FormattingOptions) interface{} { return o.ShowTOC }, true},
+		{"header-align", func(o FormattingOptions) interface{} { return o.HeaderAlignment }, "center"},
+		{"header-style", func(o FormattingOptions) interface{} { return o.HeaderStyle }, "dashed"},
+		{"page-width", func(o FormattingOptions) interface{} { return o.PageWidth }, 100},
+		{"txt-ext", func(o FormattingOptions) interface{} { return o.AdditionalExtensions }, []string{"go", "py"}},
+		{"include", func(o FormattingOptions) interface{} { return o.IncludePatterns }, []string{"**/*.md", "docs/**"}},
+		{"exclude", func(o FormattingOptions) interface{} { return o.ExcludePatterns }, []string{"vendor/**", "*.log"}},
+		{"output-format", func(o FormattingOptions) interface{} { return o.OutputFormat }, "markdown"},
+		{"file-index", func(o FormattingOptions) interface{} { return o.ShowFileIndex }, true},
+		{"file-index-position", func(o FormattingOptions) interface{} { return o.FileIndexPosition }, FileIndexAfterTOC},
+		{"md-code-fences", func(o FormattingOptions) interface{} { return o.MarkdownCodeFences }, true},
+		{"md-collapsible", func(o FormattingOptions) interface{} { return o.MarkdownCollapsible }, true},
+		{"md-normalize", func(o FormattingOptions) interface{} { return o.MarkdownNormalize }, true},
+		{"overflow", func(o FormattingOptions) interface{} { return o.Overflow }, OverflowWrap},
+		{"unique-by", func(o FormattingOptions) interface{} { return o.UniqueBy }, UniqueByBasename},
+		{"on-duplicate", func(o FormattingOptions) interface{} { return o.OnDuplicate }, DuplicateSkip},
+		{"dedupe-lines", func(o FormattingOptions) interface{} { return o.DedupeLines }, true},
+		{"dedupe-lines-scope", func(o FormattingOptions) interface{} { return o.DedupeLinesScope }, DedupeScopeFile},
+		{"bundle-as-section", func(o FormattingOptions) interface{} { return o.BundleAsSection }, true},
+		{"sort-bundle", func(o FormattingOptions) interface{} { return o.SortBundle }, true},
+		{"render-markdown", func(o FormattingOptions) interface{} { return o.RenderMarkdown }, true},
+		{"render-markdown-tables", func(o FormattingOptions) interface{} { return o.RenderMarkdownTables }, true},
+		{"empty-no-number", func(o FormattingOptions) interface{} { return o.EmptyNoNumber }, true},
+		{"bundle-comments-as-sections", func(o FormattingOptions) interface{} { return o.BundleCommentsAsSections }, true},
+		{"footnote-paths", func(o FormattingOptions) interface{} { return o.FootnotePaths }, true},
+		{"header-show-size", func(o FormattingOptions) interface{} { return o.HeaderShowSize }, true},
+		{"header-show-range", func(o FormattingOptions) interface{} { return o.HeaderShowRange }, true},
+		{"rtl", func(o FormattingOptions) interface{} { return o.RTL }, true},
+		{"zebra", func(o FormattingOptions) interface{} { return o.Zebra }, true},
+		{"highlight", func(o FormattingOptions) interface{} { return o.Highlight }, true},
+		{"toc-max-entries", func(o FormattingOptions) interface{} { return o.TOCMaxEntries }, 10},
+		{"anchor-every", func(o FormattingOptions) interface{} { return o.AnchorEvery }, 50},
+		{"json-include-content", func(o FormattingOptions) interface{} { return o.JSONOmitContent }, true},
+		{"plain-headers", func(o FormattingOptions) interface{} { return o.PlainHeaders }, true},
+		{"squeeze-blanks", func(o FormattingOptions) interface{} { return o.SqueezeBlanks }, true},
+		{"blank-marker", func(o FormattingOptions) interface{} { return o.BlankMarker }, true},
+		{"compact", func(o FormattingOptions) interface{} { return o.Compact }, true},
+		{"reflow", func(o FormattingOptions) interface{} { return o.Reflow }, true},
+		{"wrap", func(o FormattingOptions) interface{} { return o.WrapWidth }, 72},
+		{"strict", func(o FormattingOptions) interface{} { return o.Strict }, true},
+		{"strip-front-matter", func(o FormattingOptions) interface{} { return o.StripFrontMatter }, true},
+		{"toc-links", func(o FormattingOptions) interface{} { return o.TOCLinks }, true},
+		{"headers-only", func(o FormattingOptions) interface{} { return o.HeadersOnly }, true},
+		{"linenum-scope", func(o FormattingOptions) interface{} { return o.LineNumberScope }, LineNumberScopeCode},
+		{"linenum-min-lines", func(o FormattingOptions) interface{} { return o.LineNumberMinLines }, 20},
+		{"header-char", func(o FormattingOptions) interface{} { return o.HeaderChar }, "*"},
+		{"file-number-reset", func(o FormattingOptions) interface{} { return o.FileNumberReset }, FileNumberResetBundle},
+		{"seq-start", func(o FormattingOptions) interface{} { return o.SequenceStart }, 27},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			cmdOpts := mergeCmdOpts()
+
+			// Not set on the command line: the bundle value wins (lists are combined)
+			result := MergeOptionsWithExplicitFlags(mergeBundleOpts(), cmdOpts, map[string]bool{})
+			if got := tt.get(result); !reflect.DeepEqual(got, tt.merged) {
+				t.Errorf("without explicit flag: got %v, want %v", got, tt.merged)
+			}
+
+			// Set on the command line: the command value wins
+			result = MergeOptionsWithExplicitFlags(mergeBundleOpts(), cmdOpts, map[string]bool{tt.flag: true})
+			if got, want := tt.get(result), tt.get(cmdOpts); !reflect.DeepEqual(got, want) {
+				t.Errorf("with explicit flag: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMergeOptionsDoesNotAliasBundleSlices(t *testing.T) {
+	bundleOpts := mergeBundleOpts()
+	// Leave spare capacity so an in-place append would be visible
+	bundleOpts.AdditionalExtensions = make([]string, 1, 4)
+	bundleOpts.AdditionalExtensions[0] = "go"
+
+	first := MergeOptionsWithExplicitFlags(bundleOpts, FormattingOptions{AdditionalExtensions: []string{"py"}}, map[string]bool{})
+	second := MergeOptionsWithExplicitFlags(bundleOpts, FormattingOptions{AdditionalExtensions: []string{"rs"}}, map[string]bool{})
+
+	if !reflect.DeepEqual(first.AdditionalExtensions, []string{"go", "py"}) {
+		t.Errorf("first merge = %v, want [go py]", first.AdditionalExtensions)
+	}
+	if !reflect.DeepEqual(second.AdditionalExtensions, []string{"go", "rs"}) {
+		t.Errorf("second merge = %v, want [go rs]", second.AdditionalExtensions)
+	}
+	if len(bundleOpts.AdditionalExtensions) != 1 {
+		t.Errorf("bundle options were modified: %v", bundleOpts.AdditionalExtensions)
+	}
+}
+
+func TestMergeBundleOptionsValidatesBundleValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantErr string
+	}{
+		{name: "header char", line: "--header-char ab", wantErr: "invalid --header-char value"},
+		{name: "overflow", line: "--overflow bogus", wantErr: "invalid --overflow value"},
+		{name: "valid", line: "--header-char *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte("notes"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			bundleFile := filepath.Join(tempDir, "docs.bundle.txt")
+			if err := os.WriteFile(bundleFile, []byte(tt.line+"\nnotes.txt\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			pathInfos, err := ResolvePaths([]string{bundleFile})
+			if err != nil {
+				t.Fatalf("ResolvePaths() error = %v", err)
+			}
+
+			defaults, err := ParseBundleOptions(nil)
+			if err != nil {
+				t.Fatalf("ParseBundleOptions() error = %v", err)
+			}
+			_, err = MergeBundleOptions(pathInfos, defaults, map[string]bool{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("MergeBundleOptions() error = %v", err)
+				}
+				return
+			}
+			var optionErr *OptionError
+			if !errors.As(err, &optionErr) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MergeBundleOptions() error = %v, want an OptionError with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package nanodoc
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -350,4 +351,40 @@ func TestBuildFormattingOptions(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestFormatOptionsDump(t *testing.T) {
+	varDefault := "TBD"
+	opts := FormattingOptions{
+		Theme:                "classic-dark",
+		LineNumbers:          LineNumberGlobal,
+		ShowTOC:              true,
+		AdditionalExtensions: []string{"go", "py"},
+		BundleVars:           map[string]string{"VERSION": "1.0", "ENV": "prod"},
+		BundleVarDefault:     &varDefault,
+	}
+
+	dump := FormatOptionsDump(opts)
+	expected := []string{
+		"Theme=classic-dark\n",
+		"LineNumbers=global\n",
+		"ShowTOC=true\n",
+		"ShowFilenames=false\n",
+		"AdditionalExtensions=[go,py]\n",
+		"BundleVars={ENV:prod,VERSION:1.0}\n",
+		"BundleVarDefault=TBD\n",
+		"IncludePatterns=[]\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(dump, want) {
+			t.Errorf("Dump missing %q\nGot:\n%s", want, dump)
+		}
+	}
+	if !strings.HasPrefix(dump, "Theme=") {
+		t.Errorf("Expected fields in declaration order, got:\n%s", dump)
+	}
+
+	opts.BundleVarDefault = nil
+	if dump := FormatOptionsDump(opts); !strings.Contains(dump, "BundleVarDefault=<nil>\n") {
+		t.Errorf("Expected unset pointer shown as <nil>, got:\n%s", dump)
+	}
+}
@@ -0,0 +1,107 @@
+package nanodoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OutputFormatFunc renders a document in a custom output format
+type OutputFormatFunc func(doc *Document, ctx *FormattingContext) (string, error)
+
+// builtinOutputFormats are the output formats RenderDocument handles itself
+var builtinOutputFormats = []string{"term", "plain", "markdown", "json"}
+
+// OutputFormatRegistry manages custom output format implementations
+type OutputFormatRegistry struct {
+	mu      sync.RWMutex
+	formats map[string]OutputFormatFunc
+}
+
+// Global output format registry
+var globalOutputFormatRegistry = &OutputFormatRegistry{
+	formats: make(map[string]OutputFormatFunc),
+}
+
+// Register adds a new output format to the registry
+func (r *OutputFormatRegistry) Register(name string, fn OutputFormatFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name == "" || fn == nil {
+		return fmt.Errorf("output format needs a name and a render function")
+	}
+	for _, builtin := range builtinOutputFormats {
+		if name == builtin {
+			return fmt.Errorf("output format %q is built in", name)
+		}
+	}
+	if _, exists := r.formats[name]; exists {
+		return fmt.Errorf("output format %q already registered", name)
+	}
+
+	r.formats[name] = fn
+	return nil
+}
+
+// Get retrieves an output format by name
+func (r *OutputFormatRegistry) Get(name string) (OutputFormatFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fn, exists := r.formats[name]
+	return fn, exists
+}
+
+// List returns all registered output format names
+func (r *OutputFormatRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.formats))
+	for name := range r.formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterOutputFormat registers a custom output format in the global
+// registry, making name valid for --output-format
+func RegisterOutputFormat(name string, fn func(*Document, *FormattingContext) (string, error)) error {
+	return globalOutputFormatRegistry.Register(name, fn)
+}
+
+// GetOutputFormat retrieves a custom output format from the global registry
+func GetOutputFormat(name string) (OutputFormatFunc, bool) {
+	return globalOutputFormatRegistry.Get(name)
+}
+
+// GetOutputFormatNames returns all registered custom output format names
+func GetOutputFormatNames() []string {
+	return globalOutputFormatRegistry.List()
+}
+
+// outputFormatChoices lists the valid output formats for error messages,
+// e.g. "'term', 'plain', 'markdown', or 'json'"
+func outputFormatChoices() string {
+	names := append(append([]string{}, builtinOutputFormats...), GetOutputFormatNames()...)
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	last := len(quoted) - 1
+	return strings.Join(quoted[:last], ", ") + ", or " + quoted[last]
+}
+
+// isValidOutputFormat reports whether name is a built-in or registered format
+func isValidOutputFormat(name string) bool {
+	for _, builtin := range builtinOutputFormats {
+		if name == builtin {
+			return true
+		}
+	}
+	_, exists := GetOutputFormat(name)
+	return exists
+}
@@ -0,0 +1,58 @@
+package nanodoc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterOutputFormat(t *testing.T) {
+	rst := func(doc *Document, ctx *FormattingContext) (string, error) {
+		var b strings.Builder
+		for _, item := range doc.ContentItems {
+			title := filepath.Base(item.Filepath)
+			b.WriteString(title + "\n" + strings.Repeat("=", len(title)) + "\n\n" + item.Content + "\n\n")
+		}
+		return b.String(), nil
+	}
+	if err := RegisterOutputFormat("test-rst", rst); err != nil {
+		t.Fatalf("RegisterOutputFormat() error = %v", err)
+	}
+
+	if err := RegisterOutputFormat("test-rst", rst); err == nil {
+		t.Error("Expected an error registering a format twice")
+	}
+	if err := RegisterOutputFormat("markdown", rst); err == nil {
+		t.Error("Expected an error registering a built-in format")
+	}
+
+	opts, err := BuildFormattingOptions("", false, "classic", true, "numerical", "nice", "left", "none", 80, nil, nil, nil, "test-rst")
+	if err != nil {
+		t.Fatalf("BuildFormattingOptions() error = %v", err)
+	}
+
+	doc := &Document{
+		ContentItems:      []FileContent{{Filepath: "/docs/intro.txt", Content: "Hello"}},
+		FormattingOptions: opts,
+	}
+	result, err := RenderDocument(doc, &FormattingContext{})
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if want := "intro.txt\n=========\n\nHello\n\n"; result != want {
+		t.Errorf("RenderDocument() = %q, want %q", result, want)
+	}
+
+	found := false
+	for _, name := range GetOutputFormatNames() {
+		found = found || name == "test-rst"
+	}
+	if !found {
+		t.Errorf("Expected test-rst in %v", GetOutputFormatNames())
+	}
+
+	_, err = BuildFormattingOptions("", false, "classic", true, "numerical", "nice", "left", "none", 80, nil, nil, nil, "rts")
+	if err == nil || !strings.Contains(err.Error(), "'json', ") || !strings.Contains(err.Error(), "'test-rst'") {
+		t.Errorf("Expected the error to list built-in and registered formats, got %v", err)
+	}
+}
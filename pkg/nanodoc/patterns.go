@@ -99,4 +99,21 @@ func (pm *PatternMatcher) ShouldInclude(filePath string) (bool, error) {
 // HasPatterns returns true if any include or exclude patterns are specified
 func (pm *PatternMatcher) HasPatterns() bool {
 	return len(pm.includePatterns) > 0 || len(pm.excludePatterns) > 0
-}
\ No newline at end of file
+}
+
+// matchesAnyPattern reports whether path matches one of the glob patterns.
+// Patterns without a "/" are matched against the file name, others against
+// the whole slash-separated path.
+func matchesAnyPattern(path string, patterns []string) bool {
+	slashPath := filepath.ToSlash(path)
+	for _, pattern := range patterns {
+		target := slashPath
+		if !strings.Contains(pattern, "/") {
+			target = filepath.Base(path)
+		}
+		if match, err := doublestar.Match(pattern, target); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package nanodoc
+
+// Render bundles the files at paths into one document and renders it: paths
+// are resolved (directories, globs and bundles expand to their files), bundle
+// option lines are merged with opts, the content is extracted and the result
+// is rendered in opts.OutputFormat. It is the programmatic equivalent of
+// running nanodoc with paths as arguments.
+//
+// opts plays the part of the command-line flags. Start from the defaults
+// (ParseBundleOptions(nil)) and change what you need: options that differ
+// from their defaults win over the bundles' option lines, the others take
+// the value the bundles give them.
+//
+// Errors can be told apart with errors.As: a *FileError names a path that
+// could not be read or resolved, a *CircularDependencyError a bundle or live
+// bundle that includes itself, and a *RangeError an invalid line range.
+// errors.Is matches ErrEmptySource when paths is empty and ErrInvalidTheme
+// for an unknown theme.
+func Render(paths []string, opts FormattingOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
+	pathInfos, err := ResolvePathsWithOptions(paths, &opts)
+	if err != nil {
+		return "", err
+	}
+
+	explicitFlags, err := explicitFlagsOf(opts)
+	if err != nil {
+		return "", err
+	}
+	doc, ctx, err := PrepareDocument(pathInfos, opts, explicitFlags)
+	if err != nil {
+		return "", err
+	}
+	return RenderDocument(doc, ctx)
+}
+
+// PrepareDocument runs the stages between resolving paths and rendering:
+// the option lines of the bundles in pathInfos are merged with opts and
+// validated, the document is built from the merged options and its
+// formatting context created. Options named in explicitFlags win over the
+// bundles' option lines. A context error wraps ErrInvalidTheme; the others
+// come from merging or building.
+func PrepareDocument(pathInfos []PathInfo, opts FormattingOptions, explicitFlags map[string]bool) (*Document, *FormattingContext, error) {
+	merged, err := MergeBundleOptions(pathInfos, opts, explicitFlags)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := BuildDocument(pathInfos, merged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err := NewFormattingContext(doc.FormattingOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, ctx, nil
+}
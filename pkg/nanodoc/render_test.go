@@ -0,0 +1,90 @@
+package nanodoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"intro.txt":       "intro text",
+		"usage.txt":       "usage text",
+		"docs.bundle.txt": "--linenum file\n--header-style dashed\nintro.txt\nusage.txt\n",
+		"loop.bundle.txt": "loop.bundle.txt\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defaults, err := ParseBundleOptions(nil)
+	if err != nil {
+		t.Fatalf("ParseBundleOptions() error = %v", err)
+	}
+
+	t.Run("bundle options apply", func(t *testing.T) {
+		result, err := Render([]string{filepath.Join(tempDir, "docs.bundle.txt")}, defaults)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if !strings.Contains(result, "--------\n1. Intro\n--------\n\n1 | intro text") {
+			t.Errorf("Expected the bundle's header style and line numbers, got:\n%s", result)
+		}
+	})
+
+	t.Run("changed options win over the bundle", func(t *testing.T) {
+		opts := defaults
+		opts.HeaderStyle = "solid"
+		result, err := Render([]string{filepath.Join(tempDir, "docs.bundle.txt")}, opts)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if !strings.Contains(result, "========\n1. Intro\n========\n\n1 | intro text") {
+			t.Errorf("Expected the solid header with the bundle's line numbers, got:\n%s", result)
+		}
+	})
+
+	t.Run("errors keep their types", func(t *testing.T) {
+		_, err := Render([]string{filepath.Join(tempDir, "missing.txt")}, defaults)
+		var fileErr *FileError
+		if !errors.As(err, &fileErr) {
+			t.Errorf("Expected a FileError for a missing path, got %v", err)
+		}
+
+		_, err = Render([]string{filepath.Join(tempDir, "loop.bundle.txt")}, defaults)
+		var circularErr *CircularDependencyError
+		if !errors.As(err, &circularErr) {
+			t.Errorf("Expected a CircularDependencyError for a bundle including itself, got %v", err)
+		}
+	})
+
+	t.Run("prepare document", func(t *testing.T) {
+		pathInfos, err := ResolvePaths([]string{filepath.Join(tempDir, "docs.bundle.txt")})
+		if err != nil {
+			t.Fatalf("ResolvePaths() error = %v", err)
+		}
+
+		opts := defaults
+		opts.HeaderStyle = "solid"
+		doc, ctx, err := PrepareDocument(pathInfos, opts, map[string]bool{"header-style": true})
+		if err != nil {
+			t.Fatalf("PrepareDocument() error = %v", err)
+		}
+		if len(doc.ContentItems) != 2 {
+			t.Errorf("Expected 2 content items, got %d", len(doc.ContentItems))
+		}
+		if doc.FormattingOptions.HeaderStyle != "solid" || ctx.LineNumbers != LineNumberFile {
+			t.Errorf("Expected the merged options, got header style %q and line numbers %v", doc.FormattingOptions.HeaderStyle, ctx.LineNumbers)
+		}
+
+		opts.Theme = "no-such-theme"
+		if _, _, err := PrepareDocument(pathInfos, opts, map[string]bool{"theme": true}); !errors.Is(err, ErrInvalidTheme) {
+			t.Errorf("Expected ErrInvalidTheme for an unknown theme, got %v", err)
+		}
+	})
+}
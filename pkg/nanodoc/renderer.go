@@ -1,7 +1,10 @@
 package nanodoc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"html"
 	"log/slog"
 	"path/filepath"
 	"regexp"
@@ -18,8 +21,25 @@ type RendererOptions struct {
 	UseRichFormatting  bool
 }
 
-// RenderDocument renders a Document object to a string
+// RenderDocument renders a Document object to a string. It only reads ctx and
+// doc.FormattingOptions, so one context can be shared by concurrent renders of
+// different documents; it does fill in doc.TOC.
 func RenderDocument(doc *Document, ctx *FormattingContext) (string, error) {
+	// Registered output formats render the whole document themselves
+	if render, ok := GetOutputFormat(doc.FormattingOptions.OutputFormat); ok {
+		return render(doc, ctx)
+	}
+
+	// For JSON output, emit the files as structured data; an empty document
+	// is still a document, with no files
+	if doc.FormattingOptions.OutputFormat == "json" {
+		return renderJSON(doc)
+	}
+
+	if len(doc.ContentItems) == 0 && doc.FormattingOptions.EmptyDocumentMessage != "" {
+		return doc.FormattingOptions.EmptyDocumentMessage + "\n", nil
+	}
+
 	// For markdown output, use enhanced renderer with all features
 	if doc.FormattingOptions.OutputFormat == "markdown" {
 		return renderMarkdownEnhanced(doc, ctx)
@@ -30,6 +50,16 @@ func RenderDocument(doc *Document, ctx *FormattingContext) (string, error) {
 		return renderPlainText(doc)
 	}
 
+	// Without colors nanodoc writes no escape sequences of its own; those in
+	// the files are content and are kept
+	plain := ctx.Color == ColorNever
+	if plain {
+		ctx = ctx.Clone()
+		ctx.Theme = ctx.Theme.withoutColors()
+		ctx.TOCLinks = false
+		ctx.Highlight = false
+	}
+
 	var parts []string
 
 	// Generate TOC first, as it's used for filenames
@@ -38,59 +68,245 @@ func RenderDocument(doc *Document, ctx *FormattingContext) (string, error) {
 		generateTOC(doc)
 	}
 
+	// The file index goes before the TOC unless requested otherwise
+	indexBeforeTOC := doc.FormattingOptions.FileIndexPosition != FileIndexAfterTOC
+	if hasFileIndex(doc) && indexBeforeTOC {
+		parts = append(parts, renderFileIndex(doc))
+		parts = append(parts, "\n")
+	}
+
+	// With TOC links, each file header is preceded by an anchor the TOC
+	// entries for its headings point to
+	var fileAnchors map[string]string
+	if ctx.ShowTOC && ctx.TOCLinks {
+		fileAnchors = fileAnchorIDs(doc)
+	}
+
 	// Render TOC if requested
 	if ctx.ShowTOC {
 		var tocParts []string
 		tocParts = append(tocParts, "Table of Contents")
 		tocParts = append(tocParts, "=================")
 		tocParts = append(tocParts, "")
-		for _, entry := range doc.TOC {
+		entries, omitted := limitTOC(doc.TOC, doc.FormattingOptions.TOCMaxEntries)
+		for _, entry := range entries {
 			// Indent based on heading level, assuming Level 1 is the base
 			indent := strings.Repeat("  ", entry.Level-1)
-			tocParts = append(tocParts, fmt.Sprintf("%s- %s (%s)", indent, entry.Title, filepath.Base(entry.Path)))
+			title := entry.Title
+			if ctx.TOCLinks {
+				title = hyperlink("#"+fileAnchors[entry.Path], title)
+			}
+			tocParts = append(tocParts, fmt.Sprintf("%s- %s (%s)", indent, title, filepath.Base(entry.Path)))
+		}
+		if omitted > 0 {
+			tocParts = append(tocParts, tocOmittedNote(omitted))
 		}
 		tocParts = append(tocParts, "")
 		parts = append(parts, strings.Join(tocParts, "\n"))
 		parts = append(parts, "\n")
 	}
 
+	if hasFileIndex(doc) && !indexBeforeTOC {
+		parts = append(parts, renderFileIndex(doc))
+		parts = append(parts, "\n")
+	}
+
+	cache, err := newFragmentCache(doc.FormattingOptions.CacheDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Code is only colored when the context allows it
+	var syntax *syntaxStyles
+	if ctx.Highlight {
+		syntax = newSyntaxStyles(ctx.Theme)
+	}
+
+	highlighting := doc.FormattingOptions.HighlightLines != ""
+	if highlighting && doc.FormattingOptions.HighlightLegend != "" {
+		parts = append(parts, doc.FormattingOptions.HighlightLegend+"\n\n")
+	}
+
+	// Inline "name:" headers sit directly on their content
+	inlineHeaders := doc.FormattingOptions.HeaderStyle == InlineHeaderStyle
+
 	// Render each content item
 	prevOriginalSource := ""
-	sequenceNumber := 0
+	prevSourceGroup := ""
+	sequenceNumber := firstSequenceNumber(&doc.FormattingOptions) - 1
+	subSequenceNumber := 0
+	numberGroup := ""
 	globalLineNumber := 1
+	var footnotes []string
 
 	for _, item := range doc.ContentItems {
 		// Check if we need a file separator
 		isNotInlined := item.OriginalSource == ""
 		differentSource := item.Filepath != prevOriginalSource
+		inSection := doc.FormattingOptions.BundleAsSection && item.SourceGroup != ""
 
-		if isNotInlined && differentSource && ctx.ShowFilenames {
-			// Add separator if not first item
+		// A bundle rendered as a section gets a single banner with its name
+		if inSection && item.SourceGroup != prevSourceGroup && ctx.ShowFilenames {
 			if len(parts) > 0 && !strings.HasSuffix(parts[len(parts)-1], "\n\n") {
 				parts = append(parts, "\n")
 			}
 
-			// Generate filename
 			sequenceNumber++
-			filename := generateFilename(item.Filepath, &doc.FormattingOptions, sequenceNumber, doc)
-			parts = append(parts, filename)
+			subSequenceNumber = 0
+			parts = append(parts, generateBundleBanner(item.SourceGroup, &doc.FormattingOptions, sequenceNumber, ctx.Theme))
 			parts = append(parts, "\n\n")
 		}
 
-		// Add content with optional line numbers
-		content := item.Content
-		
+		// A bundle comment section is just a banner with its title
+		if item.SectionTitle != "" {
+			if len(parts) > 0 && !strings.HasSuffix(parts[len(parts)-1], "\n\n") {
+				parts = append(parts, "\n")
+			}
+			parts = append(parts, generateSectionBanner(item.SectionTitle, &doc.FormattingOptions, ctx.Theme))
+			parts = append(parts, "\n\n")
+			prevSourceGroup = item.SourceGroup
+			continue
+		}
+
+		if isNotInlined && differentSource && ctx.ShowFilenames {
+			// Add separator if not first item; inline headers need none
+			if len(parts) > 0 && !inlineHeaders && !strings.HasSuffix(parts[len(parts)-1], "\n\n") {
+				parts = append(parts, "\n")
+			}
+
+			if id, ok := fileAnchors[item.Filepath]; ok {
+				parts = append(parts, hyperlinkAnchor(id))
+			}
+
+			if inSection {
+				// Files within a bundle section get a lighter sub-header
+				subSequenceNumber++
+				parts = append(parts, generateSectionFileHeader(item, &doc.FormattingOptions, sequenceNumber, subSequenceNumber, doc))
+			} else if doc.FormattingOptions.FootnotePaths {
+				// The header is a short marker; the path goes in the map at the end
+				sequenceNumber++
+				marker := fmt.Sprintf("[%s]", generateSequence(sequenceNumber, doc.FormattingOptions.SequenceStyle))
+				footnotes = append(footnotes, fmt.Sprintf("%s = %s", marker, item.Filepath))
+				parts = append(parts, applyBannerStyle(marker, &doc.FormattingOptions, ctx.Theme))
+			} else {
+				// Numbering restarts where --file-number-reset asks
+				if group, ok := fileNumberGroup(item, doc.FormattingOptions.FileNumberReset); ok {
+					first := firstSequenceNumber(&doc.FormattingOptions)
+					if sequenceNumber >= first && group != numberGroup {
+						sequenceNumber = first - 1
+					}
+					numberGroup = group
+				}
+
+				// Generate filename
+				sequenceNumber++
+				filename := generateFilename(item, &doc.FormattingOptions, sequenceNumber, doc, ctx.Theme)
+				parts = append(parts, filename)
+			}
+			if inlineHeaders {
+				parts = append(parts, "\n")
+			} else {
+				parts = append(parts, "\n\n")
+			}
+		}
+
+		// Inline blocks have no header but are set apart like files
+		if !isNotInlined && ctx.ShowFilenames && !inlineHeaders && len(parts) > 0 && !strings.HasSuffix(parts[len(parts)-1], "\n\n") {
+			parts = append(parts, "\n")
+		}
+
+		// With --headers-only the header is all a file shows
+		if doc.FormattingOptions.HeadersOnly {
+			if item.OriginalSource != "" {
+				prevOriginalSource = item.OriginalSource
+			} else {
+				prevOriginalSource = item.Filepath
+			}
+			prevSourceGroup = item.SourceGroup
+			continue
+		}
+
+		// Add content with optional line numbers, reusing the cached
+		// fragment when the file and the options shaping it are unchanged
+		key := fragmentKey(item, &doc.FormattingOptions, syntax, plain)
+		content, ok := cache.get(key)
+		if !ok {
+			content, err = renderFragment(item, &doc.FormattingOptions, syntax, plain)
+			if err != nil {
+				return "", err
+			}
+			cache.put(key, content)
+		}
+
 		// Handle empty files
-		if content == "" {
+		isPlaceholder := content == ""
+		if isPlaceholder {
 			content = "(empty file)"
 		}
-		
-		if ctx.LineNumbers != LineNumberNone {
-			numberedContent, newGlobalLineNum := addLineNumbers(content, ctx.LineNumbers, globalLineNumber)
-			content = numberedContent
-			if ctx.LineNumbers == LineNumberGlobal {
+
+		var highlighted map[int]bool
+		if highlighting && !isPlaceholder {
+			highlighted, err = highlightSet(doc.FormattingOptions.HighlightLines, strings.Count(content, "\n")+1)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		// Runs of blank lines become one blank line, or one unnumbered marker
+		var unnumbered map[int]bool
+		if doc.FormattingOptions.SqueezeBlanks && !isPlaceholder {
+			content, highlighted, unnumbered = squeezeBlankLines(content, highlighted, doc.FormattingOptions.BlankMarker)
+		}
+
+		// Files outside the scope are not numbered and, in global mode, do
+		// not use up numbers
+		lineNumbers := ctx.LineNumbers
+		if (isPlaceholder && doc.FormattingOptions.EmptyNoNumber) || !inLineNumberScope(item, doc.FormattingOptions.LineNumberScope) {
+			lineNumbers = LineNumberNone
+		}
+		// Files shorter than the minimum are not numbered either, but in
+		// global mode their lines still use up numbers
+		if lineNumbers != LineNumberNone && countContentLines(item.Content) < doc.FormattingOptions.LineNumberMinLines {
+			if lineNumbers == LineNumberGlobal {
+				_, globalLineNumber = addHighlightedLineNumbers(content, lineNumbers, globalLineNumber, nil, unnumbered, "", "")
+			}
+			lineNumbers = LineNumberNone
+		}
+		numberSGR := ctx.Theme.LineNumberSGR()
+		gutterSGR := ""
+		if doc.FormattingOptions.HighlightGutter {
+			gutterSGR = ctx.Theme.EmphasisSGR()
+		}
+
+		// Stripes go on before the gutter is added, so it stays unshaded
+		if doc.FormattingOptions.Zebra && !isPlaceholder {
+			stripeWidth := 0
+			if !doc.FormattingOptions.RTL {
+				stripeWidth = doc.FormattingOptions.PageWidth
+				if lineNumbers != LineNumberNone {
+					stripeWidth -= lineNumberGutterWidth(content, lineNumbers, globalLineNumber)
+				}
+			}
+			content = stripeLines(content, ctx.Theme.ZebraSGR(), stripeWidth)
+		}
+
+		if doc.FormattingOptions.RTL {
+			laidOut, newGlobalLineNum := layoutRTL(content, lineNumbers, globalLineNumber, &doc.FormattingOptions, highlighted, unnumbered, numberSGR, gutterSGR)
+			content = laidOut
+			if lineNumbers == LineNumberGlobal {
 				globalLineNumber = newGlobalLineNum
 			}
+		} else {
+			gutterWidth := 0
+			if lineNumbers != LineNumberNone {
+				gutterWidth = lineNumberGutterWidth(content, lineNumbers, globalLineNumber)
+				numberedContent, newGlobalLineNum := addHighlightedLineNumbers(content, lineNumbers, globalLineNumber, highlighted, unnumbered, numberSGR, gutterSGR)
+				content = numberedContent
+				if lineNumbers == LineNumberGlobal {
+					globalLineNumber = newGlobalLineNum
+				}
+			}
+			content = applyOverflow(content, doc.FormattingOptions.Overflow, doc.FormattingOptions.PageWidth, gutterWidth)
 		}
 
 		parts = append(parts, content)
@@ -106,34 +322,109 @@ func RenderDocument(doc *Document, ctx *FormattingContext) (string, error) {
 		} else {
 			prevOriginalSource = item.Filepath
 		}
+		prevSourceGroup = item.SourceGroup
 	}
 
-	result := strings.Join(parts, "")
-	return result, nil
+	if len(footnotes) > 0 {
+		parts = append(parts, "\n"+strings.Join(footnotes, "\n")+"\n")
+	}
+
+	return strings.Join(parts, ""), nil
+}
+
+func generateFilename(item FileContent, opts *FormattingOptions, seqNum int, doc *Document, theme *Theme) string {
+	headerText := generateFileHeaderText(item, opts, seqNum, doc)
+
+	// Apply the banner style from the registry
+	return applyBannerStyle(headerText, opts, theme)
+}
+
+// generateFileHeaderText generates the text content for the header of item
+func generateFileHeaderText(item FileContent, opts *FormattingOptions, seqNum int, doc *Document) string {
+	baseName := generateHeaderName(item, opts, doc) + headerRangeSuffix(item, opts) + headerSizeSuffix(item, opts)
+
+	// Add sequence number
+	seq := generateSequence(seqNum, opts.SequenceStyle)
+	if seq != "" {
+		return fmt.Sprintf("%s. %s", seq, baseName)
+	}
+	return baseName
 }
 
-func generateFilename(filePath string, opts *FormattingOptions, seqNum int, doc *Document) string {
-	headerText := generateFileHeaderText(filePath, opts, seqNum, doc)
+// generateBundleBanner generates the banner shown once for a bundle rendered
+// as a section, named after the bundle file without its bundle suffix
+func generateBundleBanner(bundlePath string, opts *FormattingOptions, seqNum int, theme *Theme) string {
+	name := bundlePath
+	if opts.HeaderFormat != HeaderFormatPath {
+		name = filepath.Base(bundlePath)
+		if idx := strings.Index(name, BundlePattern); idx > 0 {
+			name = name[:idx]
+		}
+		if opts.HeaderFormat != HeaderFormatFilename {
+			name = toTitleCase(splitCamelCase(strings.NewReplacer("_", " ", "-", " ").Replace(name)))
+		}
+	}
 
-	// Get banner style from registry
-	style, exists := GetBannerStyle(opts.HeaderStyle)
-	if !exists {
-		// Fallback to none style if not found
-		style, _ = GetBannerStyle("none")
+	headerText := name
+	if seq := generateSequence(seqNum, opts.SequenceStyle); seq != "" {
+		headerText = fmt.Sprintf("%s. %s", seq, name)
 	}
 
-	// Apply the banner style
-	return style.Apply(headerText, opts)
+	return applyBannerStyle(headerText, opts, theme)
 }
 
-// generateFileHeaderText generates the text content for a file header
-func generateFileHeaderText(filePath string, opts *FormattingOptions, seqNum int, doc *Document) string {
-	// Find the primary title for this file from the TOC
-	var title string
-	for _, entry := range doc.TOC {
-		if entry.Path == filePath {
-			title = entry.Title
-			break
+// generateSectionBanner generates the banner for a bundle comment section.
+// Without a banner style it falls back to solid lines so the section stands
+// out from file headers.
+func generateSectionBanner(title string, opts *FormattingOptions, theme *Theme) string {
+	sectionOpts := *opts
+	if sectionOpts.HeaderStyle == "" || sectionOpts.HeaderStyle == "none" {
+		sectionOpts.HeaderStyle = "solid"
+	}
+	return applyBannerStyle(title, &sectionOpts, theme)
+}
+
+// generateSectionFileHeader generates the sub-header for a file inside a
+// bundle section, e.g. "1.2. Install"
+func generateSectionFileHeader(item FileContent, opts *FormattingOptions, seqNum, subSeqNum int, doc *Document) string {
+	name := generateHeaderName(item, opts, doc) + headerRangeSuffix(item, opts) + headerSizeSuffix(item, opts)
+	return fmt.Sprintf("%s.%d. %s", generateSequence(seqNum, opts.SequenceStyle), subSeqNum, name)
+}
+
+// headerSizeSuffix returns the size of item's content as " (12.3 KB)" when
+// headers show sizes, or "" otherwise
+func headerSizeSuffix(item FileContent, opts *FormattingOptions) string {
+	if !opts.HeaderShowSize {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", formatFileSize(int64(len(item.Content))))
+}
+
+// headerRangeSuffix returns the line ranges item was included with as
+// " (L10-20)" when headers show ranges, or "" for a whole file
+func headerRangeSuffix(item FileContent, opts *FormattingOptions) string {
+	if !opts.HeaderShowRange || coversFile(item.Ranges, item.FileLines) {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", FormatRanges(item.Ranges))
+}
+
+// generateHeaderName generates the name shown for a file in its header
+func generateHeaderName(item FileContent, opts *FormattingOptions, doc *Document) string {
+	// A title given in the bundle is used whatever the header format
+	if item.HeaderTitle != "" {
+		return item.HeaderTitle
+	}
+
+	// Find the primary title for this file from its front matter or the TOC
+	filePath := item.Filepath
+	title := item.FrontMatterTitle
+	if title == "" {
+		for _, entry := range doc.TOC {
+			if entry.Path == filePath {
+				title = entry.Title
+				break
+			}
 		}
 	}
 
@@ -143,6 +434,12 @@ func generateFileHeaderText(filePath string, opts *FormattingOptions, seqNum int
 		baseName = filepath.Base(filePath)
 	case HeaderFormatPath:
 		baseName = filePath
+	case HeaderFormatRelative:
+		root := doc.CommonRoot
+		if root == "" {
+			root = commonRoot(doc.ContentItems)
+		}
+		baseName = relativeTo(root, filePath)
 	case HeaderFormatNice:
 		fallthrough
 	default:
@@ -159,42 +456,148 @@ func generateFileHeaderText(filePath string, opts *FormattingOptions, seqNum int
 		baseName = niceName
 	}
 
-	// Add sequence number
-	seq := generateSequence(seqNum, opts.SequenceStyle)
-	if seq != "" {
-		return fmt.Sprintf("%s. %s", seq, baseName)
-	}
 	return baseName
 }
 
+// commonRoot returns the nearest directory containing all the files among
+// items; inline blocks, sections and standard input are left out
+func commonRoot(items []FileContent) string {
+	var root []string
+	found := false
+	for _, item := range items {
+		if item.OriginalSource != "" || item.SectionTitle != "" || item.Filepath == StdinName {
+			continue
+		}
+		absPath, err := filepath.Abs(item.Filepath)
+		if err != nil {
+			continue
+		}
+		dir := strings.Split(filepath.Dir(absPath), string(filepath.Separator))
+		if !found {
+			root, found = dir, true
+			continue
+		}
+		n := 0
+		for n < len(root) && n < len(dir) && root[n] == dir[n] {
+			n++
+		}
+		root = root[:n]
+	}
+	if !found {
+		return ""
+	}
+	if len(root) == 1 && root[0] == "" {
+		return string(filepath.Separator)
+	}
+	return strings.Join(root, string(filepath.Separator))
+}
+
+// relativeTo returns filePath relative to root, or filePath itself when it
+// is not under root
+func relativeTo(root, filePath string) string {
+	absPath, err := filepath.Abs(filePath)
+	if root == "" || err != nil {
+		return filePath
+	}
+	rel, err := filepath.Rel(root, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filePath
+	}
+	return rel
+}
+
+// hasFileIndex reports whether a file index should be rendered for the document
+func hasFileIndex(doc *Document) bool {
+	return doc.FormattingOptions.ShowFileIndex && len(doc.ContentItems) > 0
+}
+
+// generateFileIndex returns one numbered entry per file header in the document,
+// using the same sequence style as the headers
+func generateFileIndex(doc *Document) []string {
+	var entries []string
+	prevOriginalSource := ""
+	sequenceNumber := firstSequenceNumber(&doc.FormattingOptions) - 1
+
+	for _, item := range doc.ContentItems {
+		if item.OriginalSource == "" && item.Filepath != prevOriginalSource {
+			sequenceNumber++
+			seq := generateSequence(sequenceNumber, doc.FormattingOptions.SequenceStyle)
+			entries = append(entries, fmt.Sprintf("%s. %s", seq, filepath.Base(item.Filepath)))
+		}
+
+		if item.OriginalSource != "" {
+			prevOriginalSource = item.OriginalSource
+		} else {
+			prevOriginalSource = item.Filepath
+		}
+	}
+
+	return entries
+}
+
+// renderFileIndex renders the file index section for term output
+func renderFileIndex(doc *Document) string {
+	var indexParts []string
+	indexParts = append(indexParts, "Files")
+	indexParts = append(indexParts, "=====")
+	indexParts = append(indexParts, "")
+	indexParts = append(indexParts, generateFileIndex(doc)...)
+	indexParts = append(indexParts, "")
+	return strings.Join(indexParts, "\n")
+}
+
+// renderFileIndexMarkdown renders the file index section for markdown output.
+// Non-numerical sequences are emitted as bullets, as markdown only numbers lists.
+func renderFileIndexMarkdown(doc *Document) string {
+	var builder strings.Builder
+	builder.WriteString("## Files\n\n")
+	for _, entry := range generateFileIndex(doc) {
+		if doc.FormattingOptions.SequenceStyle != SequenceNumerical && doc.FormattingOptions.SequenceStyle != "" {
+			builder.WriteString("- ")
+		}
+		builder.WriteString(entry)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
 // generateSequence generates a sequence number in the specified style
 func generateSequence(num int, style SequenceStyle) string {
 	switch style {
 	case SequenceNumerical:
 		return strconv.Itoa(num)
-	case SequenceLetter:
-		if num <= 26 {
-			return string(rune('a' + num - 1))
-		}
-		// For numbers > 26, use aa, ab, ac, etc.
-		return string(rune('a'+((num-1)/26)-1)) + string(rune('a'+((num-1)%26)))
+	case SequenceLetter, SequenceAlphabetical:
+		return strings.ToLower(toLetters(num))
+	case SequenceLetterUpper:
+		return toLetters(num)
 	case SequenceRoman:
+		return strings.ToLower(toRoman(num))
+	case SequenceRomanUpper:
 		return toRoman(num)
 	default:
 		return strconv.Itoa(num)
 	}
 }
 
+// firstSequenceNumber returns the number of the first file header, which
+// --seq-start sets so split documents can continue each other's numbering
+func firstSequenceNumber(opts *FormattingOptions) int {
+	if opts.SequenceStart > 1 {
+		return opts.SequenceStart
+	}
+	return 1
+}
+
 // splitCamelCase splits a camelCase string into words
 func splitCamelCase(s string) string {
 	// Add space before capital letters preceded by lowercase
 	re1 := regexp.MustCompile("([a-z])([A-Z])")
 	s = re1.ReplaceAllString(s, "$1 $2")
-	
+
 	// Handle consecutive uppercase followed by lowercase (e.g., HTMLFile -> HTML File)
 	re2 := regexp.MustCompile("([A-Z])([A-Z][a-z])")
 	s = re2.ReplaceAllString(s, "$1 $2")
-	
+
 	return s
 }
 
@@ -209,11 +612,21 @@ func toTitleCase(s string) string {
 	return strings.Join(words, " ")
 }
 
-// toRoman converts a number to Roman numerals (simplified version)
+// toLetters converts a number to upper-case letters like spreadsheet
+// columns: A..Z, then AA, AB, ... ZZ, AAA, ...
+func toLetters(num int) string {
+	letters := ""
+	for ; num > 0; num = (num - 1) / 26 {
+		letters = string(rune('A'+(num-1)%26)) + letters
+	}
+	return letters
+}
+
+// toRoman converts a number to upper-case Roman numerals (simplified version)
 func toRoman(num int) string {
 	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
 	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
-	
+
 	result := ""
 	for i := 0; i < len(values); i++ {
 		for num >= values[i] {
@@ -221,40 +634,489 @@ func toRoman(num int) string {
 			result += symbols[i]
 		}
 	}
-	return strings.ToLower(result)
+	return result
+}
+
+// lineNumberWidth calculates the width needed for the line numbers of content
+func lineNumberWidth(lineCount int, mode LineNumberMode, startNum int) int {
+	maxLineNum := startNum + lineCount - 1
+	if mode == LineNumberFile {
+		maxLineNum = lineCount
+	}
+	return len(strconv.Itoa(maxLineNum))
+}
+
+// inLineNumberScope reports whether item gets line numbers under scope.
+// Inline bundle blocks count as text.
+func inLineNumberScope(item FileContent, scope string) bool {
+	isText := item.OriginalSource != "" || isMarkdownFile(item.Filepath) || strings.HasSuffix(item.Filepath, ".txt")
+	switch scope {
+	case LineNumberScopeCode:
+		return !isText
+	case LineNumberScopeText:
+		return isText
+	default:
+		return true
+	}
+}
+
+// lineNumberGutterWidth returns the width of the "N | " prefix addLineNumbers
+// puts in front of each line of content
+func lineNumberGutterWidth(content string, mode LineNumberMode, startNum int) int {
+	lineCount := strings.Count(content, "\n") + 1
+	return lineNumberWidth(lineCount, mode, startNum) + len(" | ")
 }
 
 // addLineNumbers adds line numbers to content
 func addLineNumbers(content string, mode LineNumberMode, startNum int) (string, int) {
+	return addHighlightedLineNumbers(content, mode, startNum, nil, nil, "", "")
+}
+
+// addHighlightedLineNumbers adds line numbers to content, colors the gutter
+// with numberSGR and the digits of the highlighted lines (1-based within
+// content) with gutterSGR. Unnumbered lines get a blank gutter and use up no
+// number.
+func addHighlightedLineNumbers(content string, mode LineNumberMode, startNum int, highlighted, unnumbered map[int]bool, numberSGR, gutterSGR string) (string, int) {
 	lines := strings.Split(content, "\n")
-	
+
 	// Calculate the width needed for line numbers
-	maxLineNum := startNum + len(lines) - 1
-	if mode == LineNumberFile {
-		maxLineNum = len(lines)
-	}
-	width := len(strconv.Itoa(maxLineNum))
-	
+	width := lineNumberWidth(len(lines), mode, startNum)
+
+	// Colored content may carry a style across lines; the gutter is printed
+	// with styles reset and the active style is restored after it
+	colored := strings.Contains(content, "\x1b")
+	activeStyle := ""
+
 	var result []string
 	lineNum := startNum
 	if mode == LineNumberFile {
 		lineNum = 1
 	}
-	
-	for _, line := range lines {
+
+	separator := " | "
+	if numberSGR != "" {
+		separator = numberSGR + separator + ansiReset
+	}
+
+	for i, line := range lines {
+		digits := fmt.Sprintf("%*d", width, lineNum)
+		if unnumbered[i+1] {
+			digits = strings.Repeat(" ", width)
+		} else if gutterSGR != "" && highlighted[i+1] {
+			digits = gutterSGR + digits + ansiReset
+		} else if numberSGR != "" {
+			digits = numberSGR + digits + ansiReset
+		}
+
 		// Don't add line numbers to empty lines at the end
-		if line == "" && lineNum == len(lines) {
+		if stripANSI(line) == "" && lineNum == len(lines) {
 			result = append(result, line)
+		} else if colored {
+			numberedLine := fmt.Sprintf("%s%s%s%s%s", ansiReset, digits, separator, activeStyle, line)
+			result = append(result, numberedLine)
 		} else {
-			numberedLine := fmt.Sprintf("%*d | %s", width, lineNum, line)
+			numberedLine := fmt.Sprintf("%s%s%s", digits, separator, line)
 			result = append(result, numberedLine)
 		}
-		lineNum++
+		if colored {
+			activeStyle = trackSGR(activeStyle, line)
+		}
+		if !unnumbered[i+1] {
+			lineNum++
+		}
+	}
+
+	return strings.Join(result, "\n"), lineNum
+}
+
+// stripeLines shades every other line of content, starting with the second,
+// with the stripeSGR background. Striped lines are padded to width cells so
+// the stripe spans the page; width <= 0 shades only the text. A stripeSGR of
+// "" leaves content unchanged. Tabs are expanded on every line, so the padding
+// is measured in the cells they take and lines still line up with each other.
+func stripeLines(content, stripeSGR string, width int) string {
+	if stripeSGR == "" {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	activeStyle := ""
+	for i, line := range lines {
+		line = expandTabs(line)
+		lines[i] = line
+		activeStyle = trackSGR(activeStyle, line)
+		if i%2 == 0 {
+			continue
+		}
+		// Resets inside the line would end the stripe early
+		striped := strings.ReplaceAll(line, ansiReset, ansiReset+stripeSGR)
+		if pad := width - displayWidth(line); pad > 0 {
+			striped += strings.Repeat(" ", pad)
+		}
+		// End with a reset and restore the style the content carries over
+		lines[i] = stripeSGR + striped + ansiReset + activeStyle
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderFragment renders the body of a file for term output: markdown styled
+// for the terminal, code colored with syntax unless it is nil and highlighted
+// lines marked. Empty files give "".
+func renderFragment(item FileContent, opts *FormattingOptions, syntax *syntaxStyles, plain bool) (string, error) {
+	fragmentRendered(item.Filepath)
+	content := item.Content
+
+	// Style markdown for the terminal instead of showing its source
+	if opts.RenderMarkdown && isMarkdownFile(item.Filepath) && content != "" {
+		parser := markdown.NewParser()
+		if opts.RenderMarkdownTables {
+			parser = markdown.NewTableParser()
+		}
+		mdDoc, err := parser.Parse([]byte(expandLeadingTabs(content)))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse content for file %s: %w", item.Filepath, err)
+		}
+		renderer := markdown.NewTerminalRenderer()
+		renderer.Width = displayWidth
+		renderer.Plain = plain
+		if opts.Reflow {
+			renderer.ReflowWidth = opts.PageWidth
+		}
+		content = renderer.Render(mdDoc)
+	}
+
+	// Wrap before numbering, so each wrapped line gets its own number
+	if opts.WrapWidth > 0 {
+		content = wrapContent(content, opts.WrapWidth)
+	}
+
+	content = highlightSyntax(content, item.Filepath, syntax)
+
+	if opts.HighlightLines != "" && content != "" {
+		highlighted, err := highlightSet(opts.HighlightLines, strings.Count(content, "\n")+1)
+		if err != nil {
+			return "", err
+		}
+		content = markHighlightedLines(content, highlighted)
 	}
-	
+	return content, nil
+}
+
+// highlightMarker prefixes highlighted lines in term output
+const highlightMarker = "» "
+
+// layoutRTL lays content out for right-to-left reading: lines are aligned to
+// the right edge of the page and line numbers, if any, follow them after a
+// " | " separator. Overflowing lines are cut or wrapped to the room left by
+// the gutter; wrapped continuation lines get a blank number.
+func layoutRTL(content string, mode LineNumberMode, startNum int, opts *FormattingOptions, highlighted, unnumbered map[int]bool, numberSGR, gutterSGR string) (string, int) {
+	lines := strings.Split(content, "\n")
+	lineNum := startNum
+	if mode == LineNumberFile {
+		lineNum = 1
+	}
+
+	width, gutterWidth := 0, 0
+	if mode != LineNumberNone {
+		width = lineNumberWidth(len(lines), mode, startNum)
+		gutterWidth = width + len(" | ")
+	}
+	textWidth := opts.PageWidth - gutterWidth
+
+	var result []string
+	for i, line := range lines {
+		// Like addLineNumbers, leave a trailing empty line alone
+		if i == len(lines)-1 && i > 0 && stripANSI(line) == "" {
+			result = append(result, line)
+			break
+		}
+
+		segments := []string{line}
+		if opts.PageWidth > 0 {
+			segments = strings.Split(applyOverflow(line, opts.Overflow, textWidth, 0), "\n")
+		}
+		for j, segment := range segments {
+			if opts.PageWidth > 0 {
+				segment = applyAlignment(segment, "right", textWidth)
+			}
+			if mode != LineNumberNone {
+				digits := strings.Repeat(" ", width)
+				if j == 0 && !unnumbered[i+1] {
+					digits = fmt.Sprintf("%*d", width, lineNum)
+					if gutterSGR != "" && highlighted[i+1] {
+						digits = gutterSGR + digits + ansiReset
+					} else if numberSGR != "" {
+						digits = numberSGR + digits + ansiReset
+					}
+				}
+				if strings.Contains(segment, "\x1b") {
+					segment += ansiReset
+				}
+				separator := " | "
+				if numberSGR != "" {
+					separator = numberSGR + separator + ansiReset
+				}
+				segment += separator + digits
+			}
+			result = append(result, segment)
+		}
+		if !unnumbered[i+1] {
+			lineNum++
+		}
+	}
+
 	return strings.Join(result, "\n"), lineNum
 }
 
+// blankRunMarker stands in for a squeezed run of blank lines with --blank-marker
+const blankRunMarker = "⋮"
+
+// squeezeBlankLines collapses each run of two or more blank lines in content
+// to a single blank line or, with marker, to one blankRunMarker line. The
+// highlighted lines are renumbered to match; the returned unnumbered set
+// holds the marker lines, which get no line number.
+func squeezeBlankLines(content string, highlighted map[int]bool, marker bool) (string, map[int]bool, map[int]bool) {
+	// A final newline is not a blank line of its own
+	body, trailing := strings.CutSuffix(content, "\n")
+	lines := strings.Split(body, "\n")
+
+	var result []string
+	var newHighlighted, unnumbered map[int]bool
+	if highlighted != nil {
+		newHighlighted = make(map[int]bool)
+	}
+	for i := 0; i < len(lines); i++ {
+		end := i
+		for end < len(lines) && strings.TrimSpace(stripANSI(lines[end])) == "" {
+			end++
+		}
+		if end-i >= 2 && marker {
+			if unnumbered == nil {
+				unnumbered = make(map[int]bool)
+			}
+			result = append(result, blankRunMarker)
+			unnumbered[len(result)] = true
+			i = end - 1
+			continue
+		}
+		result = append(result, lines[i])
+		if highlighted[i+1] {
+			newHighlighted[len(result)] = true
+		}
+		if end-i >= 2 {
+			i = end - 1
+		}
+	}
+
+	squeezed := strings.Join(result, "\n")
+	if trailing {
+		squeezed += "\n"
+	}
+	return squeezed, newHighlighted, unnumbered
+}
+
+// highlightSet returns the 1-based numbers of the lines within the ranges in
+// spec, for content of lineCount lines
+func highlightSet(spec string, lineCount int) (map[int]bool, error) {
+	ranges, err := parseRanges(spec, lineCount)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[int]bool)
+	for line := 1; line <= lineCount; line++ {
+		for _, r := range ranges {
+			if r.Contains(line) {
+				set[line] = true
+				break
+			}
+		}
+	}
+	return set, nil
+}
+
+// markHighlightedLines prefixes the highlighted lines of content with
+// highlightMarker and indents the others by the same width
+func markHighlightedLines(content string, highlighted map[int]bool) string {
+	lines := strings.Split(content, "\n")
+	padding := strings.Repeat(" ", len([]rune(highlightMarker)))
+	for i, line := range lines {
+		prefix := padding
+		if highlighted[i+1] {
+			prefix = highlightMarker
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyOverflow truncates or wraps content lines wider than pageWidth.
+// gutterWidth is the width of the line number prefix, if any; wrapped
+// continuation lines get a blank gutter so the content stays aligned.
+func applyOverflow(content, mode string, pageWidth, gutterWidth int) string {
+	if mode == "" || mode == OverflowNone || pageWidth <= 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	var result []string
+	for _, line := range lines {
+		if displayWidth(line) <= pageWidth {
+			result = append(result, line)
+			continue
+		}
+
+		switch mode {
+		case OverflowTruncate:
+			result = append(result, truncateToWidth(line, pageWidth))
+		case OverflowWrap:
+			head, tail := splitAtWidth(line, pageWidth)
+			result = append(result, head)
+
+			gutter := ""
+			if gutterWidth > 0 {
+				gutter = strings.Repeat(" ", gutterWidth-2) + "| "
+			}
+			for _, chunk := range wrapToWidth(tail, pageWidth-gutterWidth) {
+				result = append(result, gutter+chunk)
+			}
+		default:
+			result = append(result, line)
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// wrapContent word-wraps the lines of content wider than width, breaking
+// between words and keeping the line's indentation on the lines it wraps
+// onto. Blank lines and lines inside fenced code blocks are left as they are,
+// and a word wider than width gets a line of its own.
+func wrapContent(content string, width int) string {
+	lines := strings.Split(content, "\n")
+	var result []string
+	fence := ""
+	for _, line := range lines {
+		text := strings.TrimLeft(line, " \t")
+		if fence != "" {
+			if strings.HasPrefix(text, fence) {
+				fence = ""
+			}
+			result = append(result, line)
+			continue
+		}
+		if strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+			fence = text[:3]
+			result = append(result, line)
+			continue
+		}
+		if displayWidth(line) <= width {
+			result = append(result, line)
+			continue
+		}
+
+		indent := line[:len(line)-len(text)]
+		current := indent
+		for _, word := range strings.Fields(text) {
+			if current != indent && displayWidth(current)+1+displayWidth(word) > width {
+				result = append(result, current)
+				current = indent
+			}
+			if current != indent {
+				current += " "
+			}
+			current += word
+		}
+		result = append(result, current)
+	}
+	return strings.Join(result, "\n")
+}
+
+// markdownTabWidth is the tab stop markdown uses for indentation
+const markdownTabWidth = 4
+
+// expandLeadingTabs replaces tabs in the indentation of markdown lines with
+// spaces up to the next tab stop, so tab-indented nested lists parse like
+// space-indented ones. Lines inside fenced code blocks are left as they are.
+func expandLeadingTabs(content string) string {
+	if !strings.Contains(content, "\t") {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		text := strings.TrimLeft(line, " \t")
+		if fence != "" {
+			if strings.HasPrefix(text, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+			fence = text[:3]
+		}
+
+		indent := line[:len(line)-len(text)]
+		if !strings.Contains(indent, "\t") {
+			continue
+		}
+		var expanded strings.Builder
+		column := 0
+		for _, r := range indent {
+			width := 1
+			if r == '\t' {
+				width = markdownTabWidth - column%markdownTabWidth
+			}
+			expanded.WriteString(strings.Repeat(" ", width))
+			column += width
+		}
+		lines[i] = expanded.String() + text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fileNumberGroup returns the group whose files are numbered together under
+// the given --file-number-reset mode: the bundle a file came from, or its
+// directory. ok is false when numbering never restarts.
+func fileNumberGroup(item FileContent, reset string) (group string, ok bool) {
+	switch reset {
+	case FileNumberResetBundle:
+		return item.SourceGroup, true
+	case FileNumberResetDir:
+		return filepath.Dir(item.Filepath), true
+	}
+	return "", false
+}
+
+// fileAnchorIDs names an anchor for each file of the document, numbered in
+// the order the files appear
+func fileAnchorIDs(doc *Document) map[string]string {
+	anchors := make(map[string]string)
+	for _, item := range doc.ContentItems {
+		if item.SectionTitle != "" {
+			continue
+		}
+		if _, ok := anchors[item.Filepath]; !ok {
+			anchors[item.Filepath] = fmt.Sprintf("nanodoc-file-%d", len(anchors)+1)
+		}
+	}
+	return anchors
+}
+
+// limitTOC returns the first max TOC entries and how many were left out.
+// The full list stays in doc.TOC, which headers use to find file titles.
+func limitTOC(entries []TOCEntry, max int) ([]TOCEntry, int) {
+	if max <= 0 || len(entries) <= max {
+		return entries, 0
+	}
+	return entries[:max], len(entries) - max
+}
+
+// tocOmittedNote is the line that ends a truncated table of contents
+func tocOmittedNote(omitted int) string {
+	return fmt.Sprintf("%s (%d more entries)", Ellipsis, omitted)
+}
+
 // generateTOC generates a table of contents for the document using the markdown parser.
 func generateTOC(doc *Document) {
 	doc.TOC = make([]TOCEntry, 0)
@@ -270,7 +1132,7 @@ func generateTOC(doc *Document) {
 			continue
 		}
 
-		mdDoc, err := parser.Parse([]byte(item.Content))
+		mdDoc, err := parser.Parse([]byte(expandLeadingTabs(item.Content)))
 		if err != nil {
 			slog.Warn("failed to parse markdown for TOC generation", "file", item.Filepath, "error", err)
 			continue
@@ -282,6 +1144,7 @@ func generateTOC(doc *Document) {
 				Title:    entry.Text,
 				Level:    entry.Level,
 				Path:     item.Filepath,
+				Slug:     entry.ID,
 				Sequence: generateSequence(sequenceNum, doc.FormattingOptions.SequenceStyle),
 				// LineNumber is not available from the new parser, which is acceptable.
 			})
@@ -291,8 +1154,6 @@ func generateTOC(doc *Document) {
 	doc.TOC = allHeadings
 }
 
-
-
 // renderMarkdownBasic performs basic concatenation of markdown files without any modifications
 // This is kept for backward compatibility and fallback
 func renderMarkdownBasic(doc *Document) (string, error) {
@@ -301,7 +1162,7 @@ func renderMarkdownBasic(doc *Document) (string, error) {
 	for _, item := range doc.ContentItems {
 		// Simply append the content as-is
 		parts = append(parts, item.Content)
-		
+
 		// Ensure content ends with newline
 		if len(parts) > 0 && !strings.HasSuffix(parts[len(parts)-1], "\n") {
 			parts = append(parts, "\n")
@@ -322,6 +1183,11 @@ func renderMarkdownEnhanced(doc *Document, ctx *FormattingContext) (string, erro
 	headerFormatter := markdown.NewHeaderFormatter()
 
 	var processedDocs []*markdown.Document
+	// With --md-collapsible, the header and summary written around each file
+	var fileHeaders, summaries []string
+	collapsible := doc.FormattingOptions.MarkdownCollapsible
+	// Whether --md-normalize has already kept a file's top heading as the H1
+	hasTitle := false
 
 	// Generate TOC first if needed, so it's available for all renderers
 	if ctx.ShowTOC {
@@ -331,18 +1197,48 @@ func renderMarkdownEnhanced(doc *Document, ctx *FormattingContext) (string, erro
 
 	// Process each content item
 	for i, item := range doc.ContentItems {
-		mdDoc, err := parser.Parse([]byte(item.Content))
+		isMarkdown := strings.HasSuffix(item.Filepath, ".md") || strings.HasSuffix(item.Filepath, ".markdown")
+
+		source := item.Content
+		if isMarkdown {
+			source = expandLeadingTabs(source)
+		}
+		// With --headers-only every file is reduced to its header
+		headersOnly := doc.FormattingOptions.HeadersOnly
+		if headersOnly {
+			source = ""
+		}
+		if item.SectionTitle != "" {
+			source = "## " + item.SectionTitle
+		}
+		if !isMarkdown && !headersOnly && doc.FormattingOptions.MarkdownCodeFences {
+			numberLines := doc.FormattingOptions.LineNumbers != LineNumberNone && inLineNumberScope(item, doc.FormattingOptions.LineNumberScope)
+			source = fenceCodeContent(source, languageForFile(item.Filepath), numberLines)
+		}
+
+		mdDoc, err := parser.Parse([]byte(source))
 		if err != nil {
 			return "", fmt.Errorf("failed to parse content for file %s: %w", item.Filepath, err)
 		}
 
-		isMarkdown := strings.HasSuffix(item.Filepath, ".md") || strings.HasSuffix(item.Filepath, ".markdown")
+		fileHeader, summary := "", ""
+		if collapsible && !headersOnly && item.SectionTitle == "" {
+			summary = generateFileHeaderText(item, &doc.FormattingOptions, i+firstSequenceNumber(&doc.FormattingOptions), doc)
+		}
 
-		if isMarkdown {
+		if isMarkdown || (headersOnly && item.SectionTitle == "") {
 			// Perform markdown-specific transformations
 
-			// Adjust header levels for subsequent documents to maintain hierarchy
-			if i > 0 && transformer.HasH1(mdDoc) {
+			// Adjust header levels for subsequent documents to maintain hierarchy.
+			// With --md-normalize, the first file with headers provides the only
+			// H1 and every other file starts at H2.
+			if doc.FormattingOptions.MarkdownNormalize && isMarkdown {
+				if hasTitle {
+					transformer.NormalizeHeaderLevels(mdDoc, 2, false)
+				} else {
+					hasTitle = transformer.NormalizeHeaderLevels(mdDoc, 1, true)
+				}
+			} else if i > 0 && transformer.HasH1(mdDoc) {
 				if err := transformer.AdjustHeaderLevels(mdDoc, 1); err != nil {
 					return "", fmt.Errorf("failed to adjust header levels for %s: %w", item.Filepath, err)
 				}
@@ -350,31 +1246,45 @@ func renderMarkdownEnhanced(doc *Document, ctx *FormattingContext) (string, erro
 
 			// Insert file headers if requested
 			if ctx.ShowFilenames {
-				sequenceNum := i + 1
-				headerText := generateFileHeaderText(item.Filepath, &doc.FormattingOptions, sequenceNum, doc)
+				sequenceNum := i + firstSequenceNumber(&doc.FormattingOptions)
+				headerText := generateFileHeaderText(item, &doc.FormattingOptions, sequenceNum, doc)
 
 				// Format as a markdown header. H2 is chosen as a sensible default
 				// to avoid conflicting with a potential H1 title in the first document.
 				const headerLevel = 2
 				mdHeaderText := headerFormatter.FormatFileHeader(headerText, "", headerLevel)
 
-				if err := transformer.InsertFileHeader(mdDoc, mdHeaderText, headerLevel); err != nil {
+				// A collapsible file keeps its header outside the details
+				// block, so the header stays visible and linkable
+				if summary != "" {
+					fileHeader = strings.Repeat("#", headerLevel) + " " + mdHeaderText + "\n\n"
+				} else if err := transformer.InsertFileHeader(mdDoc, mdHeaderText, headerLevel); err != nil {
 					return "", fmt.Errorf("failed to insert file header for %s: %w", item.Filepath, err)
 				}
 			}
 		}
 
 		processedDocs = append(processedDocs, mdDoc)
+		fileHeaders = append(fileHeaders, fileHeader)
+		summaries = append(summaries, summary)
 	}
 
 	// Build final output
 	var output strings.Builder
+	output.WriteString(renderFrontMatter(doc.FormattingOptions.MarkdownFrontMatter))
+
+	indexBeforeTOC := doc.FormattingOptions.FileIndexPosition != FileIndexAfterTOC
+	if hasFileIndex(doc) && indexBeforeTOC {
+		output.WriteString(renderFileIndexMarkdown(doc))
+		output.WriteString("\n")
+	}
 
 	// Phase 2.4: Add TOC if requested
 	if ctx.ShowTOC && len(doc.TOC) > 0 {
 		// Convert nanodoc.TOCEntry to markdown.TOCEntry
-		mdTOCEntries := make([]markdown.TOCEntry, len(doc.TOC))
-		for i, entry := range doc.TOC {
+		entries, omitted := limitTOC(doc.TOC, doc.FormattingOptions.TOCMaxEntries)
+		mdTOCEntries := make([]markdown.TOCEntry, len(entries))
+		for i, entry := range entries {
 			mdTOCEntries[i] = markdown.TOCEntry{
 				Text:  fmt.Sprintf("%s - %s", filepath.Base(entry.Path), entry.Title),
 				Level: entry.Level,
@@ -382,9 +1292,17 @@ func renderMarkdownEnhanced(doc *Document, ctx *FormattingContext) (string, erro
 		}
 		tocMarkdown := tocGen.GenerateTOCMarkdown(mdTOCEntries)
 		output.WriteString(tocMarkdown)
+		if omitted > 0 {
+			output.WriteString("\n" + tocOmittedNote(omitted))
+		}
 		output.WriteString("\n\n")
 	}
 
+	if hasFileIndex(doc) && !indexBeforeTOC {
+		output.WriteString(renderFileIndexMarkdown(doc))
+		output.WriteString("\n")
+	}
+
 	// Render all processed documents
 	for i, mdDoc := range processedDocs {
 		if i > 0 {
@@ -396,7 +1314,14 @@ func renderMarkdownEnhanced(doc *Document, ctx *FormattingContext) (string, erro
 			return "", fmt.Errorf("failed to render markdown: %w", err)
 		}
 
+		if summaries[i] == "" {
+			output.Write(rendered)
+			continue
+		}
+		output.WriteString(fileHeaders[i])
+		output.WriteString("<details>\n<summary>" + html.EscapeString(summaries[i]) + "</summary>\n\n")
 		output.Write(rendered)
+		output.WriteString("\n</details>\n")
 	}
 
 	return output.String(), nil
@@ -406,10 +1331,38 @@ func renderMarkdownEnhanced(doc *Document, ctx *FormattingContext) (string, erro
 func renderPlainText(doc *Document) (string, error) {
 	var parts []string
 
+	// The file index is the only addition plain output supports
+	if hasFileIndex(doc) {
+		parts = append(parts, strings.Join(generateFileIndex(doc), "\n"))
+		parts = append(parts, "\n\n")
+	}
+
+	globalLine := 1
 	for _, item := range doc.ContentItems {
+		// Plain output has no headers, so sections are left out
+		if item.SectionTitle != "" {
+			continue
+		}
+
+		// Inline blocks have no name to show, so a blank line sets them apart
+		if doc.FormattingOptions.PlainHeaders {
+			if item.OriginalSource != "" {
+				if len(parts) > 0 {
+					parts = append(parts, "\n")
+				}
+			} else {
+				parts = append(parts, fmt.Sprintf("=== %s ===\n", filepath.Base(item.Filepath)))
+			}
+		}
+
+		content := item.Content
+		if doc.FormattingOptions.AnchorEvery > 0 {
+			content, globalLine = addLineAnchors(content, doc.FormattingOptions.AnchorEvery, globalLine)
+		}
+
 		// Simply append the content as-is
-		parts = append(parts, item.Content)
-		
+		parts = append(parts, content)
+
 		// Ensure content ends with newline
 		if len(parts) > 0 && !strings.HasSuffix(parts[len(parts)-1], "\n") {
 			parts = append(parts, "\n")
@@ -418,4 +1371,133 @@ func renderPlainText(doc *Document) (string, error) {
 
 	result := strings.Join(parts, "")
 	return result, nil
-}
\ No newline at end of file
+}
+
+// jsonDocument is the top-level object of JSON output
+type jsonDocument struct {
+	Files []jsonFile     `json:"files"`
+	TOC   []jsonTOCEntry `json:"toc,omitempty"`
+}
+
+// jsonFile is one content item in JSON output
+type jsonFile struct {
+	Path      string  `json:"path"`
+	Title     string  `json:"title"`
+	Sequence  string  `json:"sequence"`
+	LineCount int     `json:"lineCount"`
+	Content   *string `json:"content,omitempty"`
+}
+
+// jsonTOCEntry mirrors a TOCEntry in JSON output
+type jsonTOCEntry struct {
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	Level    int    `json:"level"`
+	Sequence string `json:"sequence"`
+	Slug     string `json:"slug"`
+}
+
+// renderJSON renders the document as a JSON object with one entry per file
+// and, with ShowTOC, the table of contents. Line numbers, headers and themes
+// are presentation only and are left out; titles are the nice header names.
+func renderJSON(doc *Document) (string, error) {
+	// Titles come from the TOC, so it is generated even when not shown
+	generateTOC(doc)
+
+	titleOpts := doc.FormattingOptions
+	titleOpts.HeaderFormat = HeaderFormatNice
+
+	out := jsonDocument{Files: make([]jsonFile, 0, len(doc.ContentItems))}
+	sequenceNumber := firstSequenceNumber(&doc.FormattingOptions) - 1
+	for _, item := range doc.ContentItems {
+		// Bundle sections are not files
+		if item.SectionTitle != "" {
+			continue
+		}
+
+		// Inline blocks get no header, so they get no sequence or title either
+		sequence, title := "", ""
+		if item.OriginalSource == "" {
+			sequenceNumber++
+			sequence = generateSequence(sequenceNumber, doc.FormattingOptions.SequenceStyle)
+			title = generateHeaderName(item, &titleOpts, doc)
+		}
+
+		// Without content, the file keeps its metadata but has no content key
+		var content *string
+		if !doc.FormattingOptions.JSONOmitContent {
+			content = &item.Content
+		}
+
+		out.Files = append(out.Files, jsonFile{
+			Path:      item.Filepath,
+			Title:     title,
+			Sequence:  sequence,
+			LineCount: countContentLines(item.Content),
+			Content:   content,
+		})
+	}
+
+	if doc.FormattingOptions.ShowTOC {
+		out.TOC = make([]jsonTOCEntry, 0, len(doc.TOC))
+		for _, entry := range doc.TOC {
+			out.TOC = append(out.TOC, jsonTOCEntry{
+				Title:    entry.Title,
+				Path:     entry.Path,
+				Level:    entry.Level,
+				Sequence: entry.Sequence,
+				Slug:     entry.Slug,
+			})
+		}
+	}
+
+	// Content is code and prose, so <, > and & are kept as they are
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return "", fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// addLineAnchors puts a "# --- line N ---" sentinel before every line of
+// content whose global number N is a multiple of every. Numbering starts at
+// startLine; the number of the line after content is returned.
+func addLineAnchors(content string, every, startLine int) (string, int) {
+	if content == "" {
+		return content, startLine
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	var result []string
+	lineNum := startLine
+	for _, line := range lines {
+		if lineNum%every == 0 {
+			result = append(result, fmt.Sprintf("# --- line %d ---", lineNum))
+		}
+		result = append(result, line)
+		lineNum++
+	}
+	anchored := strings.Join(result, "\n")
+	if strings.HasSuffix(content, "\n") {
+		anchored += "\n"
+	}
+	return anchored, lineNum
+}
+
+// renderFrontMatter renders KEY=VALUE pairs as a YAML front matter block.
+// Values are written as double-quoted scalars, whose escapes Go quoting covers.
+func renderFrontMatter(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+		b.WriteString(fmt.Sprintf("%s: %s\n", key, strconv.Quote(value)))
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
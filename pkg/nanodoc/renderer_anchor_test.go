@@ -0,0 +1,33 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderPlainLineAnchors(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/logs/a.log", Content: "a1\na2\na3\na4"},
+			{Filepath: "/logs/b.log", Content: "b1\nb2\nb3\n"},
+		},
+		FormattingOptions: FormattingOptions{OutputFormat: "plain", AnchorEvery: 3},
+	}
+
+	result, err := RenderDocument(doc, &FormattingContext{})
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	// Lines are counted across files, so b2 is global line 6
+	expected := "a1\na2\n# --- line 3 ---\na3\na4\nb1\n# --- line 6 ---\nb2\nb3\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	doc.FormattingOptions.AnchorEvery = 0
+	result, err = RenderDocument(doc, &FormattingContext{})
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if expected := "a1\na2\na3\na4\nb1\nb2\nb3\n"; result != expected {
+		t.Errorf("Expected no anchors when disabled, got %q", result)
+	}
+}
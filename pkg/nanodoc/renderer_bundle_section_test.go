@@ -0,0 +1,75 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderBundleAsSection(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"intro.txt":   "Intro text",
+		"install.txt": "Install text",
+		"usage.txt":   "Usage text",
+		"notes.txt":   "Standalone notes",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundleFile := filepath.Join(tempDir, "user-guide.bundle.txt")
+	if err := os.WriteFile(bundleFile, []byte("intro.txt\ninstall.txt\nusage.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notes := filepath.Join(tempDir, "notes.txt")
+
+	pathInfos := []PathInfo{
+		{Original: bundleFile, Absolute: bundleFile, Type: "bundle"},
+		{Original: notes, Absolute: notes, Type: "file"},
+	}
+
+	opts := FormattingOptions{
+		ShowFilenames:   true,
+		HeaderFormat:    HeaderFormatNice,
+		SequenceStyle:   SequenceNumerical,
+		HeaderAlignment: "left",
+		HeaderStyle:     "none",
+		BundleAsSection: true,
+	}
+	doc, err := BuildDocument(pathInfos, opts)
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if doc.ContentItems[i].SourceGroup != bundleFile {
+			t.Errorf("Item %d: expected SourceGroup %s, got %q", i, bundleFile, doc.ContentItems[i].SourceGroup)
+		}
+	}
+	if doc.ContentItems[3].SourceGroup != "" {
+		t.Errorf("Expected no SourceGroup for a direct file, got %q", doc.ContentItems[3].SourceGroup)
+	}
+
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatNice, SequenceStyle: SequenceNumerical}
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	if strings.Count(result, "User Guide") != 1 {
+		t.Errorf("Expected a single bundle banner, got:\n%s", result)
+	}
+	for _, want := range []string{"1. User Guide", "1.1. Intro", "1.2. Install", "1.3. Usage", "2. Notes"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+		}
+	}
+	if strings.Index(result, "1.1. Intro") < strings.Index(result, "1. User Guide") {
+		t.Errorf("Expected bundle banner before its files, got:\n%s", result)
+	}
+}
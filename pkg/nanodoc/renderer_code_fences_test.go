@@ -0,0 +1,79 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownCodeFences(t *testing.T) {
+	tests := []struct {
+		name        string
+		filepath    string
+		content     string
+		lineNumbers LineNumberMode
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name:     "go_file_gets_language",
+			filepath: "/src/main.go",
+			content:  "package main\n",
+			contains: []string{"```go\npackage main\n```"},
+		},
+		{
+			name:        "line_numbers_add_attribute",
+			filepath:    "/src/main.go",
+			content:     "package main\n",
+			lineNumbers: LineNumberFile,
+			contains:    []string{"```go {.numberLines}\npackage main\n```"},
+		},
+		{
+			name:     "unknown_extension_has_no_language",
+			filepath: "/notes/todo.txt",
+			content:  "buy milk\n",
+			contains: []string{"```\nbuy milk\n```"},
+		},
+		{
+			name:     "markdown_files_are_not_fenced",
+			filepath: "/docs/guide.md",
+			content:  "# Guide\n",
+			contains: []string{"# Guide"},
+			excludes: []string{"```"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				ContentItems: []FileContent{{Filepath: tt.filepath, Content: tt.content}},
+				FormattingOptions: FormattingOptions{
+					OutputFormat:       "markdown",
+					LineNumbers:        tt.lineNumbers,
+					MarkdownCodeFences: true,
+				},
+			}
+
+			result, err := RenderDocument(doc, &FormattingContext{})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestFenceCodeContentLongerThanBacktickRuns(t *testing.T) {
+	result := fenceCodeContent("x := \"```\"\n", "go", false)
+	if !strings.HasPrefix(result, "````go\n") || !strings.HasSuffix(result, "\n````\n") {
+		t.Errorf("Expected a four-backtick fence, got %q", result)
+	}
+}
@@ -0,0 +1,39 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownCollapsible(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/intro.md", Content: "# Intro\n\nHello"},
+			{Filepath: "/docs/a&b.md", Content: "# Usage\n\nRun it"},
+		},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:        "markdown",
+			ShowFilenames:       true,
+			HeaderFormat:        HeaderFormatFilename,
+			SequenceStyle:       SequenceNumerical,
+			MarkdownCollapsible: true,
+		},
+	}
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	for _, want := range []string{
+		"## 1. intro.md\n\n<details>\n<summary>1. intro.md</summary>\n\n# Intro\n\nHello\n\n</details>\n",
+		"## 2. a&b.md\n\n<details>\n<summary>2. a&amp;b.md</summary>\n\n## Usage\n\nRun it\n\n</details>\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q, got:\n%q", want, result)
+		}
+	}
+	if got := strings.Count(result, "<details>"); got != 2 {
+		t.Errorf("Expected one details block per file, got %d", got)
+	}
+}
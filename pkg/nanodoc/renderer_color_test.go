@@ -0,0 +1,76 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderColorNever(t *testing.T) {
+	theme := &Theme{Name: "test", Styles: map[string]string{"banner.color": "cyan", "line-number": "bright_black"}}
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/notes.txt", Content: "\x1b[31mred\x1b[0m\nplain"}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:  "term",
+			HeaderFormat:  HeaderFormatFilename,
+			SequenceStyle: SequenceNumerical,
+		},
+	}
+
+	tests := []struct {
+		color  string
+		themed bool
+	}{
+		{color: "", themed: true},
+		{color: ColorAlways, themed: true},
+		{color: ColorNever, themed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run("color="+tt.color, func(t *testing.T) {
+			ctx := &FormattingContext{
+				Theme:         theme,
+				LineNumbers:   LineNumberFile,
+				ShowFilenames: true,
+				HeaderFormat:  HeaderFormatFilename,
+				SequenceStyle: SequenceNumerical,
+				Color:         tt.color,
+			}
+
+			result, err := RenderDocument(doc, ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			// The theme's colors follow the color mode
+			if got := strings.Contains(result, "\x1b[36m"); got != tt.themed {
+				t.Errorf("Expected banner color = %v, got %q", tt.themed, result)
+			}
+			if got := strings.Contains(result, "\x1b[90m"); got != tt.themed {
+				t.Errorf("Expected line number color = %v, got %q", tt.themed, result)
+			}
+
+			// The escape codes in the file are its content and always kept
+			if !strings.Contains(result, "\x1b[31mred\x1b[0m") {
+				t.Errorf("Expected the file's own escape codes kept, got %q", result)
+			}
+		})
+	}
+}
+
+func TestRenderColorNeverMarkdown(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/guide.md", Content: "# Guide\n\nSome **bold** text."}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:   "term",
+			RenderMarkdown: true,
+		},
+	}
+	ctx := &FormattingContext{Color: ColorNever}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if result != "Guide\n\nSome bold text.\n" {
+		t.Errorf("Expected markdown rendered without escape codes, got %q", result)
+	}
+}
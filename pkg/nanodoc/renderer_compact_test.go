@@ -0,0 +1,60 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyCompactPreset(t *testing.T) {
+	base := FormattingOptions{Compact: true, HeaderStyle: "solid", ShowTOC: true}
+
+	got := ApplyCompactPreset(base, map[string]bool{})
+	if got.HeaderStyle != InlineHeaderStyle || !got.SqueezeBlanks || got.ShowTOC {
+		t.Errorf("ApplyCompactPreset() = style %q, squeeze %v, toc %v; want inline, true, false", got.HeaderStyle, got.SqueezeBlanks, got.ShowTOC)
+	}
+
+	// Explicit flags win over the preset
+	got = ApplyCompactPreset(base, map[string]bool{"header-style": true, "toc": true, "squeeze-blanks": true})
+	if got.HeaderStyle != "solid" || got.SqueezeBlanks || !got.ShowTOC {
+		t.Errorf("ApplyCompactPreset() with explicit flags = style %q, squeeze %v, toc %v; want solid, false, true", got.HeaderStyle, got.SqueezeBlanks, got.ShowTOC)
+	}
+
+	// Without --compact nothing changes
+	base.Compact = false
+	if got := ApplyCompactPreset(base, map[string]bool{}); got.HeaderStyle != "solid" || !got.ShowTOC {
+		t.Errorf("ApplyCompactPreset() without Compact changed options: %+v", got)
+	}
+}
+
+func TestRenderCompact(t *testing.T) {
+	opts := ApplyCompactPreset(FormattingOptions{
+		OutputFormat:  "term",
+		Compact:       true,
+		ShowFilenames: true,
+		HeaderFormat:  HeaderFormatFilename,
+		SequenceStyle: SequenceNumerical,
+		HeaderStyle:   "dashed",
+		ShowTOC:       true,
+	}, map[string]bool{})
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/src/a.txt", Content: "one\n\n\n\ntwo\n"},
+			{Filepath: "/src/b.txt", Content: "three\n"},
+		},
+		FormattingOptions: opts,
+	}
+	ctx := &FormattingContext{ShowFilenames: true, ShowTOC: opts.ShowTOC, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	expected := "1. a.txt:\none\n\ntwo\n2. b.txt:\nthree\n"
+	if result != expected {
+		t.Errorf("RenderDocument() = %q, want %q", result, expected)
+	}
+	if strings.Contains(result, "---") || strings.Contains(result, "Table of Contents") {
+		t.Errorf("Expected no banner rules or TOC, got:\n%s", result)
+	}
+}
@@ -0,0 +1,119 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Run with -race to check that rendering shares no mutable state
+func TestRenderDocumentConcurrent(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"a.md": "# Alpha\n\n## Setup\n\nalpha body",
+		"b.md": "# Beta\n\nbeta body",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	opts := NewDocument().FormattingOptions
+	opts.ShowTOC = true
+	opts.LineNumbers = LineNumberGlobal
+	opts.HeaderStyle = "solid"
+	opts.PageWidth = 80
+	opts.AdditionalExtensions = []string{"rst"}
+
+	ctx, err := NewFormattingContext(opts)
+	if err != nil {
+		t.Fatalf("NewFormattingContext() error = %v", err)
+	}
+
+	names := []string{"a.md", "b.md"}
+	results := make([]string, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			path := filepath.Join(tempDir, name)
+			doc, err := BuildDocument([]PathInfo{{Original: path, Absolute: path, Type: "file"}}, opts)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			// Alternate between the shared context and a clone
+			renderCtx := ctx
+			if i%2 == 1 {
+				renderCtx = ctx.Clone()
+			}
+			results[i], errs[i] = RenderDocument(doc, renderCtx)
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Fatalf("Rendering %s failed: %v", name, errs[i])
+		}
+	}
+	if !strings.Contains(results[0], "- Setup (a.md)") || strings.Contains(results[0], "Beta") {
+		t.Errorf("Expected only a.md's TOC in the first render, got:\n%s", results[0])
+	}
+	if !strings.Contains(results[1], "- Beta (b.md)") || strings.Contains(results[1], "Alpha") {
+		t.Errorf("Expected only b.md's TOC in the second render, got:\n%s", results[1])
+	}
+	if len(opts.AdditionalExtensions) != 1 || opts.AdditionalExtensions[0] != "rst" {
+		t.Errorf("Rendering modified the shared options: %v", opts.AdditionalExtensions)
+	}
+}
+
+func TestFormattingOptionsClone(t *testing.T) {
+	varDefault := "TBD"
+	opts := FormattingOptions{
+		Theme:                "classic-dark",
+		AdditionalExtensions: []string{"go"},
+		IncludePatterns:      []string{"*.md"},
+		BundleVars:           map[string]string{"VERSION": "1.0"},
+		BundleVarDefault:     &varDefault,
+	}
+
+	clone := opts.Clone()
+	clone.AdditionalExtensions[0] = "py"
+	clone.IncludePatterns[0] = "*.txt"
+	clone.BundleVars["VERSION"] = "2.0"
+	*clone.BundleVarDefault = "changed"
+
+	if opts.AdditionalExtensions[0] != "go" || opts.IncludePatterns[0] != "*.md" {
+		t.Errorf("Clone shares slices with the original: %+v", opts)
+	}
+	if opts.BundleVars["VERSION"] != "1.0" || varDefault != "TBD" {
+		t.Errorf("Clone shares the vars map or default with the original: %+v", opts)
+	}
+	if clone.Theme != "classic-dark" || clone.ExcludePatterns != nil {
+		t.Errorf("Clone did not keep plain fields: %+v", clone)
+	}
+}
+
+func TestFormattingContextClone(t *testing.T) {
+	ctx, err := NewFormattingContext(FormattingOptions{Theme: "classic", ShowTOC: true})
+	if err != nil {
+		t.Fatalf("NewFormattingContext() error = %v", err)
+	}
+
+	clone := ctx.Clone()
+	clone.ShowTOC = false
+	clone.Theme.Styles["banner.color"] = "red"
+
+	if !ctx.ShowTOC {
+		t.Error("Changing the clone changed the original context")
+	}
+	if _, ok := ctx.Theme.Styles["banner.color"]; ok {
+		t.Error("Clone shares its theme styles with the original context")
+	}
+}
@@ -1,6 +1,7 @@
 package nanodoc
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -217,6 +218,54 @@ func TestEmptyDocument(t *testing.T) {
 	}
 }
 
+func TestEmptyDocumentMessage(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{},
+		FormattingOptions: FormattingOptions{
+			ShowFilenames:        true,
+			ShowTOC:              true,
+			EmptyDocumentMessage: "No files matched.",
+		},
+	}
+	ctx := &FormattingContext{ShowFilenames: true, ShowTOC: true}
+
+	for _, format := range []string{"term", "plain", "markdown"} {
+		doc.FormattingOptions.OutputFormat = format
+		got, err := RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument(%s) error = %v", format, err)
+		}
+		if got != "No files matched.\n" {
+			t.Errorf("RenderDocument(%s) = %q, want the message", format, got)
+		}
+	}
+
+	// JSON output stays JSON: a document without files
+	doc.FormattingOptions.OutputFormat = "json"
+	got, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument(json) error = %v", err)
+	}
+	var parsed jsonDocument
+	if err := json.Unmarshal([]byte(got), &parsed); err != nil {
+		t.Fatalf("Expected a JSON document, got %q: %v", got, err)
+	}
+	if parsed.Files == nil || len(parsed.Files) != 0 {
+		t.Errorf("Expected an empty files array, got %q", got)
+	}
+
+	// Documents with content ignore the message
+	doc.FormattingOptions.OutputFormat = "plain"
+	doc.ContentItems = []FileContent{{Filepath: "a.txt", Content: "a"}}
+	got, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if got != "a\n" {
+		t.Errorf("Expected the content without the message, got %q", got)
+	}
+}
+
 func TestDocumentWithOnlyEmptyFiles(t *testing.T) {
 	// Test rendering a document with only empty files
 	doc := &Document{
@@ -309,3 +358,40 @@ func TestEmptyFileWithTOC(t *testing.T) {
 		t.Error("Output should not contain TOC entry for empty.md")
 	}
 }
+
+func TestEmptyFilePlaceholderWithoutNumber(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "first.txt", Content: "one\ntwo"},
+			{Filepath: "empty.txt", Content: ""},
+			{Filepath: "last.txt", Content: "three"},
+		},
+		FormattingOptions: FormattingOptions{
+			ShowFilenames: true,
+			HeaderFormat:  HeaderFormatNice,
+			SequenceStyle: SequenceNumerical,
+			EmptyNoNumber: true,
+		},
+	}
+	ctx := &FormattingContext{
+		ShowFilenames: true,
+		HeaderFormat:  HeaderFormatNice,
+		SequenceStyle: SequenceNumerical,
+		LineNumbers:   LineNumberGlobal,
+	}
+
+	got, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	if !strings.Contains(got, "\n(empty file)\n") || strings.Contains(got, "| (empty file)") {
+		t.Errorf("Expected the placeholder without a gutter\nGot:\n%s", got)
+	}
+	// Surrounding files keep their numbering, and the placeholder takes no number
+	for _, want := range []string{"1 | one", "2 | two", "3 | three"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output missing numbered line %q\nGot:\n%s", want, got)
+		}
+	}
+}
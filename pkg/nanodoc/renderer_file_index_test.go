@@ -0,0 +1,117 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func newFileIndexDocument(outputFormat, position string) *Document {
+	return &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/intro.txt", Content: "Welcome"},
+			{Filepath: "/docs/guide.md", Content: "# Guide\n\nSteps"},
+		},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:      outputFormat,
+			ShowFilenames:     true,
+			HeaderFormat:      HeaderFormatNice,
+			SequenceStyle:     SequenceNumerical,
+			ShowTOC:           true,
+			ShowFileIndex:     true,
+			FileIndexPosition: position,
+		},
+	}
+}
+
+func TestRenderFileIndex(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputFormat string
+		position     string
+		check        func(t *testing.T, result string)
+	}{
+		{
+			name:         "term_before_toc",
+			outputFormat: "term",
+			position:     FileIndexBeforeTOC,
+			check: func(t *testing.T, result string) {
+				if !strings.HasPrefix(result, "Files\n=====\n\n1. intro.txt\n2. guide.md\n") {
+					t.Errorf("Expected numbered file index at the top, got:\n%s", result)
+				}
+				if strings.Index(result, "Files") > strings.Index(result, "Table of Contents") {
+					t.Errorf("Expected file index before the TOC, got:\n%s", result)
+				}
+			},
+		},
+		{
+			name:         "term_after_toc",
+			outputFormat: "term",
+			position:     FileIndexAfterTOC,
+			check: func(t *testing.T, result string) {
+				tocIdx := strings.Index(result, "Table of Contents")
+				indexIdx := strings.Index(result, "1. intro.txt")
+				if tocIdx == -1 || indexIdx == -1 || indexIdx < tocIdx {
+					t.Errorf("Expected file index after the TOC, got:\n%s", result)
+				}
+				if indexIdx > strings.Index(result, "Welcome") {
+					t.Errorf("Expected file index before content, got:\n%s", result)
+				}
+			},
+		},
+		{
+			name:         "plain",
+			outputFormat: "plain",
+			position:     FileIndexBeforeTOC,
+			check: func(t *testing.T, result string) {
+				expected := "1. intro.txt\n2. guide.md\n\nWelcome\n# Guide\n\nSteps\n"
+				if result != expected {
+					t.Errorf("Expected %q, got %q", expected, result)
+				}
+			},
+		},
+		{
+			name:         "markdown",
+			outputFormat: "markdown",
+			position:     FileIndexBeforeTOC,
+			check: func(t *testing.T, result string) {
+				if !strings.HasPrefix(result, "## Files\n\n1. intro.txt\n2. guide.md\n") {
+					t.Errorf("Expected markdown file index at the top, got:\n%s", result)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newFileIndexDocument(tt.outputFormat, tt.position)
+			ctx := &FormattingContext{
+				ShowFilenames: true,
+				ShowTOC:       true,
+				HeaderFormat:  HeaderFormatNice,
+				SequenceStyle: SequenceNumerical,
+			}
+
+			result, err := RenderDocument(doc, ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			tt.check(t, result)
+		})
+	}
+}
+
+func TestGenerateFileIndexSequenceStyle(t *testing.T) {
+	doc := newFileIndexDocument("term", FileIndexBeforeTOC)
+	doc.FormattingOptions.SequenceStyle = SequenceRoman
+
+	entries := generateFileIndex(doc)
+	expected := []string{"i. intro.txt", "ii. guide.md"}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("Entry %d: expected %q, got %q", i, expected[i], entries[i])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFileNumberReset(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"one/a.txt":         "a",
+		"one/b.txt":         "b",
+		"two/c.txt":         "c",
+		"two/d.txt":         "d",
+		"first.bundle.txt":  "one/a.txt\none/b.txt\n",
+		"second.bundle.txt": "two/c.txt\ntwo/d.txt\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldDir) }()
+
+	pathInfos, err := ResolvePaths([]string{"first.bundle.txt", "second.bundle.txt"})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+
+	headerPattern := regexp.MustCompile(`(?m)^\d+\. \w+\.txt$`)
+	tests := []struct {
+		reset    string
+		expected []string
+	}{
+		{FileNumberResetNone, []string{"1. a.txt", "2. b.txt", "3. c.txt", "4. d.txt"}},
+		{FileNumberResetBundle, []string{"1. a.txt", "2. b.txt", "1. c.txt", "2. d.txt"}},
+		{FileNumberResetDir, []string{"1. a.txt", "2. b.txt", "1. c.txt", "2. d.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reset, func(t *testing.T) {
+			opts := FormattingOptions{
+				OutputFormat:    "term",
+				ShowFilenames:   true,
+				HeaderFormat:    HeaderFormatFilename,
+				SequenceStyle:   SequenceNumerical,
+				FileNumberReset: tt.reset,
+			}
+			doc, err := BuildDocument(pathInfos, opts)
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+			result, err := RenderDocument(doc, &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if got := headerPattern.FindAllString(result, -1); strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected headers %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
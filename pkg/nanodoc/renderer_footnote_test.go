@@ -0,0 +1,49 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderFootnotePaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    SequenceStyle
+		expected string
+	}{
+		{
+			name:     "numerical",
+			style:    SequenceNumerical,
+			expected: "[1]\n\nintro text\n\n[2]\n\nguide text\n\n[1] = /docs/intro.txt\n[2] = /docs/guide.txt\n",
+		},
+		{
+			name:     "roman",
+			style:    SequenceRoman,
+			expected: "[i]\n\nintro text\n\n[ii]\n\nguide text\n\n[i] = /docs/intro.txt\n[ii] = /docs/guide.txt\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				ContentItems: []FileContent{
+					{Filepath: "/docs/intro.txt", Content: "intro text"},
+					{Filepath: "/docs/guide.txt", Content: "guide text"},
+				},
+				FormattingOptions: FormattingOptions{
+					OutputFormat:    "term",
+					HeaderFormat:    HeaderFormatPath,
+					SequenceStyle:   tt.style,
+					HeaderAlignment: "left",
+					FootnotePaths:   true,
+				},
+			}
+			ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatPath, SequenceStyle: tt.style}
+
+			result, err := RenderDocument(doc, ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownFrontMatter(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/intro.md", Content: "# Intro\n\nHello"},
+		},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:        "markdown",
+			ShowTOC:             true,
+			MarkdownFrontMatter: []string{"title=My \"Guide\"", "tags=a: b", "empty="},
+		},
+	}
+	ctx := &FormattingContext{ShowTOC: true}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	expected := "---\ntitle: \"My \\\"Guide\\\"\"\ntags: \"a: b\"\nempty: \"\"\n---\n\n"
+	if !strings.HasPrefix(result, expected) {
+		t.Errorf("Expected output to start with %q, got:\n%q", expected, result)
+	}
+	if !strings.Contains(result[len(expected):], "Table of Contents") || !strings.Contains(result, "Hello") {
+		t.Errorf("Expected the TOC and content after the front matter, got:\n%s", result)
+	}
+
+	// Other formats ignore it
+	doc.FormattingOptions.OutputFormat = "plain"
+	result, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if strings.HasPrefix(result, "---") {
+		t.Errorf("Expected no front matter in plain output, got:\n%q", result)
+	}
+
+	defaults, err := ParseBundleOptions(nil)
+	if err != nil {
+		t.Fatalf("ParseBundleOptions() error = %v", err)
+	}
+	for _, pair := range []string{"title", "=value", "bad key=x"} {
+		opts := defaults
+		opts.MarkdownFrontMatter = []string{pair}
+		if err := opts.Validate(); err == nil || !strings.Contains(err.Error(), "invalid --md-frontmatter value") {
+			t.Errorf("Expected Validate() to reject %q as front matter, got %v", pair, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeaderSuffixesForRepeatedFile(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/a.txt", Content: "one\ntwo", Ranges: []Range{{Start: 1, End: 2}}, FileLines: 6},
+			{Filepath: "/docs/b.txt", Content: "bee", Ranges: []Range{{Start: 1, End: 0}}, FileLines: 1},
+			{Filepath: "/docs/a.txt", Content: "five\nsix", Ranges: []Range{{Start: 5, End: 6}}, FileLines: 6},
+		},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:    "term",
+			HeaderFormat:    HeaderFormatFilename,
+			SequenceStyle:   SequenceNumerical,
+			HeaderShowRange: true,
+			HeaderShowSize:  true,
+		},
+	}
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	for _, want := range []string{
+		"1. a.txt (L1-2) (7 B)\n\none\ntwo\n",
+		"2. b.txt (3 B)\n\nbee\n",
+		"3. a.txt (L5-6) (8 B)\n\nfive\nsix\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, result)
+		}
+	}
+}
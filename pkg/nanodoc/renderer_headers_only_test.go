@@ -0,0 +1,50 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHeadersOnly(t *testing.T) {
+	newDoc := func(format string) *Document {
+		return &Document{
+			ContentItems: []FileContent{
+				{Filepath: "/docs/intro.md", Content: "# Intro\n\nintro body"},
+				{Filepath: "/docs/notes.txt", Content: "notes body"},
+			},
+			FormattingOptions: FormattingOptions{
+				OutputFormat:  format,
+				ShowFilenames: true,
+				HeaderFormat:  HeaderFormatFilename,
+				SequenceStyle: SequenceNumerical,
+				ShowTOC:       true,
+				HeadersOnly:   true,
+			},
+		}
+	}
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical, ShowTOC: true}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"term", []string{"Table of Contents", "- Intro (intro.md)", "1. intro.md\n\n2. notes.txt\n"}},
+		{"markdown", []string{"## Table of Contents", "## 1. intro.md\n\n## 2. notes.txt\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			result, err := RenderDocument(newDoc(tt.format), ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			if strings.Contains(result, "intro body") || strings.Contains(result, "notes body") {
+				t.Errorf("Expected no file content, got:\n%s", result)
+			}
+		})
+	}
+}
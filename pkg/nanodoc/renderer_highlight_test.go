@@ -0,0 +1,109 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHighlightLegend(t *testing.T) {
+	tests := []struct {
+		name      string
+		highlight string
+		legend    string
+		expected  string
+	}{
+		{
+			name:      "legend before content",
+			highlight: "L2-3",
+			legend:    "» = changed",
+			expected:  "» = changed\n\n  one\n» two\n» three\n  four\n",
+		},
+		{
+			name:      "no legend without highlighting",
+			highlight: "",
+			legend:    "» = changed",
+			expected:  "one\ntwo\nthree\nfour\n",
+		},
+		{
+			name:      "highlighting without legend",
+			highlight: "L$1",
+			expected:  "  one\n  two\n  three\n» four\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				ContentItems: []FileContent{{Filepath: "/docs/notes.txt", Content: "one\ntwo\nthree\nfour"}},
+				FormattingOptions: FormattingOptions{
+					OutputFormat:    "term",
+					HighlightLines:  tt.highlight,
+					HighlightLegend: tt.legend,
+				},
+			}
+
+			result, err := RenderDocument(doc, &FormattingContext{})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRenderHighlightInvalidRange(t *testing.T) {
+	doc := &Document{
+		ContentItems:      []FileContent{{Filepath: "/docs/notes.txt", Content: "one"}},
+		FormattingOptions: FormattingOptions{OutputFormat: "term", HighlightLines: "3-5"},
+	}
+
+	_, err := RenderDocument(doc, &FormattingContext{})
+	if err == nil || !strings.Contains(err.Error(), "invalid range") {
+		t.Errorf("Expected a range error, got %v", err)
+	}
+
+	// Validate rejects it before any file is read
+	for _, spec := range []string{"3-5", "L2,x", "L5-L2"} {
+		if err := (FormattingOptions{HighlightLines: spec}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid --highlight-lines value") {
+			t.Errorf("Expected Validate() to reject %q, got %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"L2-3", "L$1", "L40-"} {
+		if err := (FormattingOptions{HighlightLines: spec}).Validate(); err != nil {
+			t.Errorf("Expected Validate() to accept %q, got %v", spec, err)
+		}
+	}
+}
+
+func TestRenderHighlightGutter(t *testing.T) {
+	ctx, err := NewFormattingContext(FormattingOptions{Theme: "classic", LineNumbers: LineNumberFile})
+	if err != nil {
+		t.Fatalf("NewFormattingContext() error = %v", err)
+	}
+	sgr := ctx.Theme.EmphasisSGR()
+
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/notes.txt", Content: "one\ntwo\nthree"}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:    "term",
+			HighlightLines:  "L2",
+			HighlightGutter: true,
+		},
+	}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	// Only the digits are colored, not the separator
+	expected := "1 |   one\n" + sgr + "2" + ansiReset + " | » two\n3 |   three\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+	if sgr != "\x1b[97;3m" {
+		t.Errorf("Expected the classic theme's emphasis style, got %q", sgr)
+	}
+}
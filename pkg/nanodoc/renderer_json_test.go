@@ -0,0 +1,114 @@
+package nanodoc
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderJSON(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/getting_started.md", Content: "# Getting Started\n\n## Install\n\nRun <make>.\n"},
+			{Filepath: "/docs/book.bundle.txt#section-2", OriginalSource: "/docs/book.bundle.txt", SectionTitle: "Part 1"},
+			{Filepath: "/src/main.go", Content: "package main\n\nfunc main() {}"},
+		},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:  "json",
+			ShowTOC:       true,
+			LineNumbers:   LineNumberGlobal,
+			HeaderStyle:   "boxed",
+			SequenceStyle: SequenceRoman,
+		},
+	}
+	ctx := &FormattingContext{ShowTOC: true, LineNumbers: LineNumberGlobal, SequenceStyle: SequenceRoman}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if !strings.Contains(result, `"slug": "getting-started"`) {
+		t.Errorf("Expected the TOC slugs in the output, got:\n%s", result)
+	}
+	if !strings.Contains(result, "Run <make>.") {
+		t.Errorf("Expected content without HTML escaping, got:\n%s", result)
+	}
+
+	var got jsonDocument
+	if err := json.Unmarshal([]byte(result), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, result)
+	}
+
+	gettingStarted, mainGo := doc.ContentItems[0].Content, doc.ContentItems[2].Content
+	wantFiles := []jsonFile{
+		{Path: "/docs/getting_started.md", Title: "Getting Started", Sequence: "i", LineCount: 5, Content: &gettingStarted},
+		{Path: "/src/main.go", Title: "Main", Sequence: "ii", LineCount: 3, Content: &mainGo},
+	}
+	if !reflect.DeepEqual(got.Files, wantFiles) {
+		t.Errorf("files = %+v, want %+v", got.Files, wantFiles)
+	}
+
+	wantTOC := []jsonTOCEntry{
+		{Title: "Getting Started", Path: "/docs/getting_started.md", Level: 1, Sequence: "i", Slug: "getting-started"},
+		{Title: "Install", Path: "/docs/getting_started.md", Level: 2, Sequence: "ii", Slug: "install"},
+	}
+	if !reflect.DeepEqual(got.TOC, wantTOC) {
+		t.Errorf("toc = %+v, want %+v", got.TOC, wantTOC)
+	}
+
+	// Without ShowTOC the toc key is left out
+	doc.FormattingOptions.ShowTOC = false
+	result, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if strings.Contains(result, `"toc"`) {
+		t.Errorf("Expected no toc without ShowTOC, got:\n%s", result)
+	}
+}
+
+func TestRenderJSONIncludeContent(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/notes.txt", Content: "first\nsecond"},
+			{Filepath: "/docs/empty.txt", Content: ""},
+		},
+		FormattingOptions: FormattingOptions{OutputFormat: "json"},
+	}
+	ctx := &FormattingContext{}
+
+	decode := func() []map[string]interface{} {
+		t.Helper()
+		result, err := RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		var got struct {
+			Files []map[string]interface{} `json:"files"`
+		}
+		if err := json.Unmarshal([]byte(result), &got); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, result)
+		}
+		return got.Files
+	}
+
+	// By default every file carries its content, even an empty one
+	for _, file := range decode() {
+		if _, ok := file["content"]; !ok {
+			t.Errorf("Expected a content key for %v", file["path"])
+		}
+	}
+
+	// Without content the metadata is kept
+	doc.FormattingOptions.JSONOmitContent = true
+	files := decode()
+	for _, file := range files {
+		if _, ok := file["content"]; ok {
+			t.Errorf("Expected no content key for %v", file["path"])
+		}
+	}
+	if len(files) != 2 || files[0]["path"] != "/docs/notes.txt" || files[0]["lineCount"] != float64(2) {
+		t.Errorf("Expected paths and line counts kept, got %v", files)
+	}
+}
@@ -0,0 +1,56 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderLineNumberColor(t *testing.T) {
+	theme := &Theme{Name: "test", Styles: map[string]string{"line-number": "bright_black", "emphasis": "bold"}}
+	sgr := theme.LineNumberSGR()
+	if sgr != "\x1b[90m" {
+		t.Fatalf("LineNumberSGR() = %q, want %q", sgr, "\x1b[90m")
+	}
+	gutter := func(digits string) string {
+		return sgr + digits + ansiReset + sgr + " | " + ansiReset
+	}
+
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/notes.txt", Content: "one\ntwo"}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat: "term",
+		},
+	}
+	ctx := &FormattingContext{Theme: theme, LineNumbers: LineNumberFile}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	// The gutter is colored, the content keeps its own style
+	expected := gutter("1") + "one\n" + gutter("2") + "two\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// A highlighted line's digits take the emphasis style instead
+	doc.FormattingOptions.HighlightLines = "L2"
+	doc.FormattingOptions.HighlightGutter = true
+	result, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	expected = gutter("1") + "  one\n" + "\x1b[1m2" + ansiReset + sgr + " | " + ansiReset + "» two\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Themes without the style leave the gutter alone
+	ctx.Theme = &Theme{Name: "plain", Styles: map[string]string{}}
+	doc.FormattingOptions.HighlightLines = ""
+	doc.FormattingOptions.HighlightGutter = false
+	result, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if result != "1 | one\n2 | two\n" {
+		t.Errorf("Expected an uncolored gutter, got %q", result)
+	}
+}
@@ -0,0 +1,86 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderLineNumberScope(t *testing.T) {
+	items := []FileContent{
+		{Filepath: "/src/main.go", Content: "package main\n\nfunc main() {}"},
+		{Filepath: "/docs/guide.md", Content: "# Guide\n\nprose"},
+		{Filepath: "/src/util.go", Content: "package main"},
+	}
+
+	tests := []struct {
+		scope    string
+		expected string
+	}{
+		{
+			scope:    LineNumberScopeCode,
+			expected: "1 | package main\n2 | \n3 | func main() {}\n# Guide\n\nprose\n4 | package main\n",
+		},
+		{
+			scope:    LineNumberScopeText,
+			expected: "package main\n\nfunc main() {}\n1 | # Guide\n2 | \n3 | prose\npackage main\n",
+		},
+		{
+			scope:    LineNumberScopeAll,
+			expected: "1 | package main\n2 | \n3 | func main() {}\n4 | # Guide\n5 | \n6 | prose\n7 | package main\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			doc := &Document{
+				ContentItems:      items,
+				FormattingOptions: FormattingOptions{OutputFormat: "term", LineNumberScope: tt.scope},
+			}
+
+			// The global counter only advances over numbered files
+			result, err := RenderDocument(doc, &FormattingContext{LineNumbers: LineNumberGlobal})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRenderLineNumberMinLines(t *testing.T) {
+	items := []FileContent{
+		{Filepath: "/src/short.go", Content: "package a"},
+		{Filepath: "/src/long.go", Content: "package b\nvar x = 1\nfunc B() {}"},
+	}
+
+	tests := []struct {
+		mode     LineNumberMode
+		expected string
+	}{
+		{
+			// The short file's line still uses up number 1
+			mode:     LineNumberGlobal,
+			expected: "package a\n2 | package b\n3 | var x = 1\n4 | func B() {}\n",
+		},
+		{
+			mode:     LineNumberFile,
+			expected: "package a\n1 | package b\n2 | var x = 1\n3 | func B() {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			doc := &Document{
+				ContentItems:      items,
+				FormattingOptions: FormattingOptions{OutputFormat: "term", LineNumberMinLines: 3},
+			}
+
+			result, err := RenderDocument(doc, &FormattingContext{LineNumbers: tt.mode})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandLeadingTabs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "no tabs",
+			input:    "- a\n  - b",
+			expected: "- a\n  - b",
+		},
+		{
+			name:     "nested list",
+			input:    "- a\n\t- b\n\t\t- c",
+			expected: "- a\n    - b\n        - c",
+		},
+		{
+			name:     "tab after spaces stops at the next tab stop",
+			input:    "- a\n  \t- b",
+			expected: "- a\n    - b",
+		},
+		{
+			name:     "tabs inside text are kept",
+			input:    "- a\tb",
+			expected: "- a\tb",
+		},
+		{
+			name:     "fenced code is kept",
+			input:    "```make\nall:\n\tgo build\n```\n\t- after",
+			expected: "```make\nall:\n\tgo build\n```\n    - after",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandLeadingTabs(tt.input); got != tt.expected {
+				t.Errorf("expandLeadingTabs() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownTabIndentedList(t *testing.T) {
+	content := "# Guide\n\n- parent\n\t- child\n\t\t- grandchild\n\n## Details\n\n### Notes\n"
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/guide.md", Content: content}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat:  "markdown",
+			ShowTOC:       true,
+			HeaderFormat:  HeaderFormatNice,
+			SequenceStyle: SequenceNumerical,
+		},
+	}
+	ctx := &FormattingContext{ShowTOC: true, HeaderFormat: HeaderFormatNice, SequenceStyle: SequenceNumerical}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	if !strings.Contains(result, "- parent\n  - child\n    - grandchild\n") {
+		t.Errorf("Expected the tab-indented list to stay nested, got:\n%s", result)
+	}
+
+	expected := []struct {
+		title string
+		level int
+	}{{"Guide", 1}, {"Details", 2}, {"Notes", 3}}
+	if len(doc.TOC) != len(expected) {
+		t.Fatalf("Expected %d TOC entries, got %+v", len(expected), doc.TOC)
+	}
+	for i, want := range expected {
+		if doc.TOC[i].Title != want.title || doc.TOC[i].Level != want.level {
+			t.Errorf("TOC entry %d = %q (level %d), want %q (level %d)", i, doc.TOC[i].Title, doc.TOC[i].Level, want.title, want.level)
+		}
+	}
+	if !strings.Contains(result, "- [guide.md - Guide](#)\n  - [guide.md - Details](#)\n    - [guide.md - Notes](#)") {
+		t.Errorf("Expected a nested TOC, got:\n%s", result)
+	}
+}
@@ -0,0 +1,47 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderMarkdownNormalize(t *testing.T) {
+	newDoc := func(normalize bool) *Document {
+		return &Document{
+			ContentItems: []FileContent{
+				{Filepath: "/docs/setup.md", Content: "## Setup\n\n### Details"},
+				{Filepath: "/docs/usage.md", Content: "# Usage\n\n## Flags\n\n# Other"},
+			},
+			FormattingOptions: FormattingOptions{
+				OutputFormat:      "markdown",
+				MarkdownNormalize: normalize,
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		normalize bool
+		want      string
+	}{
+		{
+			name:      "default keeps the first file and demotes later H1s",
+			normalize: false,
+			want:      "## Setup\n\n### Details\n\n## Usage\n\n### Flags\n\n## Other\n",
+		},
+		{
+			name:      "normalize promotes the first file's top heading",
+			normalize: true,
+			want:      "# Setup\n\n## Details\n\n## Usage\n\n### Flags\n\n## Other\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderDocument(newDoc(tt.normalize), &FormattingContext{})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("RenderDocument() = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
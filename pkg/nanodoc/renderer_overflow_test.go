@@ -0,0 +1,78 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func renderOverflow(t *testing.T, content, overflow string, lineNumbers LineNumberMode) string {
+	t.Helper()
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/long.txt", Content: content}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat: "term",
+			PageWidth:    40,
+			Overflow:     overflow,
+		},
+	}
+	ctx := &FormattingContext{LineNumbers: lineNumbers}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	return result
+}
+
+func TestRenderOverflowTruncate(t *testing.T) {
+	long := strings.Repeat("abcdefghij", 6)
+
+	t.Run("without_line_numbers", func(t *testing.T) {
+		result := renderOverflow(t, long+"\nshort\n", OverflowTruncate, LineNumberNone)
+		expected := strings.Repeat("abcdefghij", 3) + "abcdefghi…\nshort\n"
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	})
+
+	t.Run("with_line_numbers", func(t *testing.T) {
+		result := renderOverflow(t, long, OverflowTruncate, LineNumberFile)
+		lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+		if displayWidth(lines[0]) != 40 {
+			t.Errorf("Expected truncated line of width 40, got %d: %q", displayWidth(lines[0]), lines[0])
+		}
+		if !strings.HasPrefix(lines[0], "1 | abcdefghij") || !strings.HasSuffix(lines[0], "…") {
+			t.Errorf("Expected numbered line ending with an ellipsis, got %q", lines[0])
+		}
+	})
+
+	t.Run("wide_characters", func(t *testing.T) {
+		result := renderOverflow(t, strings.Repeat("漢", 30), OverflowTruncate, LineNumberNone)
+		line := strings.TrimSuffix(result, "\n")
+		if displayWidth(line) > 40 {
+			t.Errorf("Expected line to fit in 40 cells, got %d: %q", displayWidth(line), line)
+		}
+		if line != strings.Repeat("漢", 19)+"…" {
+			t.Errorf("Unexpected truncation: %q", line)
+		}
+	})
+}
+
+func TestRenderOverflowWrap(t *testing.T) {
+	long := strings.Repeat("abcdefghij", 6)
+
+	result := renderOverflow(t, long, OverflowWrap, LineNumberFile)
+	expected := "1 | " + strings.Repeat("abcdefghij", 3) + "abcdef\n" +
+		"  | ghij" + strings.Repeat("abcdefghij", 2) + "\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestRenderOverflowNone(t *testing.T) {
+	long := strings.Repeat("abcdefghij", 6)
+	result := renderOverflow(t, long, OverflowNone, LineNumberNone)
+	if result != long+"\n" {
+		t.Errorf("Expected content unchanged, got %q", result)
+	}
+}
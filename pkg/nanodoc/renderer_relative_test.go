@@ -0,0 +1,105 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderRelativeHeaders(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	files := []string{
+		filepath.Join(root, "README.txt"),
+		filepath.Join(root, "src", "main.txt"),
+		filepath.Join(root, "src", "util", "strings.txt"),
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathInfos, err := ResolvePaths(files[1:])
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	opts := FormattingOptions{
+		OutputFormat:  "term",
+		ShowFilenames: true,
+		HeaderFormat:  HeaderFormatRelative,
+		SequenceStyle: SequenceNumerical,
+		HeaderStyle:   "none",
+	}
+	doc, err := BuildDocument(pathInfos, opts)
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+	if want := filepath.Join(root, "src"); doc.CommonRoot != want {
+		t.Errorf("CommonRoot = %q, want %q", doc.CommonRoot, want)
+	}
+
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatRelative, SequenceStyle: SequenceNumerical}
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	for _, want := range []string{"1. main.txt", "2. " + filepath.Join("util", "strings.txt")} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected header %q, got:\n%s", want, result)
+		}
+	}
+
+	// Adding a file higher up moves the root to the project directory
+	pathInfos, err = ResolvePaths(files)
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	doc, err = BuildDocument(pathInfos, opts)
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+	result, err = RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	for _, want := range []string{"1. README.txt", "2. " + filepath.Join("src", "main.txt"), "3. " + filepath.Join("src", "util", "strings.txt")} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected header %q, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestCommonRoot(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []FileContent
+		want  string
+	}{
+		{"no files", nil, ""},
+		{"single file", []FileContent{{Filepath: "/a/b/c.txt"}}, "/a/b"},
+		{"siblings", []FileContent{{Filepath: "/a/b/c.txt"}, {Filepath: "/a/b/d.txt"}}, "/a/b"},
+		{"shared prefix is not a directory", []FileContent{{Filepath: "/a/bc/x.txt"}, {Filepath: "/a/bd/y.txt"}}, "/a"},
+		{"only the filesystem root", []FileContent{{Filepath: "/a/x.txt"}, {Filepath: "/b/y.txt"}}, "/"},
+		{
+			name: "inline blocks and stdin are ignored",
+			items: []FileContent{
+				{Filepath: "/a/b/c.txt"},
+				{Filepath: "/x/project.bundle.txt#inline-1", OriginalSource: "/x/project.bundle.txt"},
+				{Filepath: StdinName},
+			},
+			want: "/a/b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commonRoot(tt.items); got != filepath.FromSlash(tt.want) {
+				t.Errorf("commonRoot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderRTL(t *testing.T) {
+	opts := FormattingOptions{
+		OutputFormat:  "term",
+		PageWidth:     20,
+		RTL:           true,
+		HeaderFormat:  HeaderFormatFilename,
+		SequenceStyle: SequenceNumerical,
+	}
+	opts = ApplyDirectionDefaults(opts, map[string]bool{})
+	doc := &Document{
+		ContentItems:      []FileContent{{Filepath: "/docs/שלום.txt", Content: "שלום עולם\nab"}},
+		FormattingOptions: opts,
+	}
+	ctx := &FormattingContext{ShowFilenames: true, LineNumbers: LineNumberFile, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	// Headers are right-aligned and line numbers come after the content
+	expected := strings.Join([]string{
+		"         1. שלום.txt",
+		"",
+		"       שלום עולם | 1",
+		"              ab | 2",
+		"",
+	}, "\n")
+	if result != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+	}
+}
+
+func TestRenderRTLWrap(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/docs/long.txt", Content: "abcdefghijklmnopqrstuvwxyz"}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat: "term",
+			PageWidth:    14,
+			Overflow:     OverflowWrap,
+			RTL:          true,
+		},
+	}
+
+	result, err := RenderDocument(doc, &FormattingContext{LineNumbers: LineNumberFile})
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	// Continuation lines get a blank number and every line fits the page
+	expected := "abcdefghij | 1\nklmnopqrst |  \n    uvwxyz |  \n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestApplyDirectionDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     FormattingOptions
+		explicit map[string]bool
+		want     string
+	}{
+		{"ltr keeps the alignment", FormattingOptions{HeaderAlignment: "left"}, nil, "left"},
+		{"rtl defaults to right", FormattingOptions{RTL: true, HeaderAlignment: "left"}, nil, "right"},
+		{"rtl keeps center", FormattingOptions{RTL: true, HeaderAlignment: "center"}, nil, "center"},
+		{"explicit alignment wins", FormattingOptions{RTL: true, HeaderAlignment: "left"}, map[string]bool{"header-align": true}, "left"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyDirectionDefaults(tt.opts, tt.explicit).HeaderAlignment; got != tt.want {
+				t.Errorf("HeaderAlignment = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package nanodoc
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSequenceStart(t *testing.T) {
+	headerPattern := regexp.MustCompile(`(?m)^\w+\. \w+\.txt$`)
+	tests := []struct {
+		style    SequenceStyle
+		start    int
+		expected []string
+	}{
+		{SequenceNumerical, 0, []string{"1. a.txt", "2. b.txt"}},
+		{SequenceNumerical, 41, []string{"41. a.txt", "42. b.txt"}},
+		{SequenceLetter, 27, []string{"aa. a.txt", "ab. b.txt"}},
+		{SequenceRoman, 4, []string{"iv. a.txt", "v. b.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			opts := FormattingOptions{
+				OutputFormat:  "term",
+				ShowFilenames: true,
+				HeaderFormat:  HeaderFormatFilename,
+				SequenceStyle: tt.style,
+				SequenceStart: tt.start,
+			}
+			doc := &Document{
+				ContentItems: []FileContent{
+					{Filepath: "/docs/a.txt", Content: "a"},
+					{Filepath: "/docs/b.txt", Content: "b"},
+				},
+				FormattingOptions: opts,
+			}
+			result, err := RenderDocument(doc, &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: tt.style})
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if got := headerPattern.FindAllString(result, -1); strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected headers %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if err := (FormattingOptions{SequenceStart: -1}).Validate(); err == nil {
+		t.Error("Expected Validate() to reject a negative --seq-start")
+	}
+	if err := (FormattingOptions{}).Validate(); err != nil {
+		t.Errorf("Expected the unset --seq-start to be valid, got %v", err)
+	}
+}
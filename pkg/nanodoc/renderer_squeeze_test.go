@@ -0,0 +1,58 @@
+package nanodoc
+
+import "testing"
+
+func TestRenderSqueezeBlanks(t *testing.T) {
+	content := "one\n\n\n\ntwo\n\nthree"
+
+	tests := []struct {
+		name     string
+		marker   bool
+		expected string
+	}{
+		{
+			name:     "runs become one blank line",
+			expected: "1 | one\n2 | \n3 | two\n4 | \n5 | three\n",
+		},
+		{
+			name:     "runs become an unnumbered marker",
+			marker:   true,
+			expected: "1 | one\n  | ⋮\n2 | two\n3 | \n4 | three\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				ContentItems: []FileContent{{Filepath: "/notes.txt", Content: content}},
+				FormattingOptions: FormattingOptions{
+					OutputFormat:  "term",
+					SqueezeBlanks: true,
+					BlankMarker:   tt.marker,
+				},
+			}
+			ctx := &FormattingContext{LineNumbers: LineNumberFile}
+
+			result, err := RenderDocument(doc, ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("RenderDocument() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSqueezeBlankLinesKeepsHighlights(t *testing.T) {
+	got, highlighted, unnumbered := squeezeBlankLines("a\n\n\n\nb\n", map[int]bool{5: true}, true)
+	if got != "a\n⋮\nb\n" {
+		t.Errorf("squeezeBlankLines() = %q, want %q", got, "a\n⋮\nb\n")
+	}
+	if !highlighted[3] || len(highlighted) != 1 {
+		t.Errorf("Expected line 3 to stay highlighted, got %v", highlighted)
+	}
+	if !unnumbered[2] || len(unnumbered) != 1 {
+		t.Errorf("Expected line 2 to be unnumbered, got %v", unnumbered)
+	}
+}
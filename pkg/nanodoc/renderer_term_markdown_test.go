@@ -0,0 +1,44 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownForTerminal(t *testing.T) {
+	newDoc := func(renderMarkdown bool) *Document {
+		return &Document{
+			ContentItems: []FileContent{
+				{Filepath: "/docs/guide.md", Content: "A **bold** claim"},
+				{Filepath: "/docs/notes.txt", Content: "Raw **stars**"},
+			},
+			FormattingOptions: FormattingOptions{
+				OutputFormat:   "term",
+				RenderMarkdown: renderMarkdown,
+			},
+		}
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		result, err := RenderDocument(newDoc(true), &FormattingContext{})
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		if !strings.Contains(result, "A \x1b[1mbold\x1b[22m claim") {
+			t.Errorf("Expected bold markers converted to ANSI, got %q", result)
+		}
+		if !strings.Contains(result, "Raw **stars**") {
+			t.Errorf("Expected non-markdown files untouched, got %q", result)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result, err := RenderDocument(newDoc(false), &FormattingContext{})
+		if err != nil {
+			t.Fatalf("RenderDocument() error = %v", err)
+		}
+		if !strings.Contains(result, "A **bold** claim") {
+			t.Errorf("Expected markdown source by default, got %q", result)
+		}
+	})
+}
@@ -42,6 +42,12 @@ func TestGenerateSequence(t *testing.T) {
 			style: SequenceLetter,
 			want:  "aa",
 		},
+		{
+			name:  "letter aaa",
+			num:   703,
+			style: SequenceLetter,
+			want:  "aaa",
+		},
 		{
 			name:  "roman i",
 			num:   1,
@@ -66,6 +72,30 @@ func TestGenerateSequence(t *testing.T) {
 			style: SequenceRoman,
 			want:  "xiv",
 		},
+		{
+			name:  "alphabetical b",
+			num:   2,
+			style: SequenceAlphabetical,
+			want:  "b",
+		},
+		{
+			name:  "letter upper B",
+			num:   2,
+			style: SequenceLetterUpper,
+			want:  "B",
+		},
+		{
+			name:  "letter upper AA",
+			num:   27,
+			style: SequenceLetterUpper,
+			want:  "AA",
+		},
+		{
+			name:  "roman upper XIV",
+			num:   14,
+			style: SequenceRomanUpper,
+			want:  "XIV",
+		},
 	}
 
 	for _, tt := range tests {
@@ -169,6 +199,40 @@ func TestAddLineNumbers(t *testing.T) {
 	}
 }
 
+func TestAddLineNumbersColoredContent(t *testing.T) {
+	// A block comment colored across lines, followed by plain lines
+	lines := []string{
+		"\x1b[32m/* start",
+		"   middle */\x1b[0m",
+	}
+	for i := 0; i < 8; i++ {
+		lines = append(lines, "\x1b[1mcode\x1b[0m")
+	}
+	content := strings.Join(lines, "\n")
+
+	got, _ := addLineNumbers(content, LineNumberFile, 1)
+	numbered := strings.Split(got, "\n")
+
+	// Every gutter has the same visible width
+	for i, line := range numbered {
+		plain := stripANSI(line)
+		if idx := strings.Index(plain, " | "); idx != 2 {
+			t.Errorf("Line %d: expected separator at column 2, got %d in %q", i+1, idx, plain)
+		}
+	}
+
+	// The gutter is reset before printing, and the open style is restored after it
+	if numbered[0] != "\x1b[0m 1 | \x1b[32m/* start" {
+		t.Errorf("Unexpected first line: %q", numbered[0])
+	}
+	if numbered[1] != "\x1b[0m 2 | \x1b[32m   middle */\x1b[0m" {
+		t.Errorf("Expected style to carry over after the gutter, got %q", numbered[1])
+	}
+	if numbered[2] != "\x1b[0m 3 | \x1b[1mcode\x1b[0m" {
+		t.Errorf("Expected no carried style after a reset, got %q", numbered[2])
+	}
+}
+
 func TestGenerateFilename(t *testing.T) {
 	doc := &Document{
 		TOC: []TOCEntry{
@@ -263,11 +327,88 @@ func TestGenerateFilename(t *testing.T) {
 			doc:    &Document{},
 			want:   "                                      1. Test File",
 		},
+		{
+			name:     "size suffix",
+			filepath: "/var/log/app.log",
+			opts: &FormattingOptions{
+				HeaderFormat:   HeaderFormatFilename,
+				SequenceStyle:  SequenceNumerical,
+				HeaderStyle:    "none",
+				HeaderShowSize: true,
+			},
+			seqNum: 1,
+			doc:    &Document{ContentItems: []FileContent{{Filepath: "/var/log/app.log", Content: strings.Repeat("x", 12595)}}},
+			want:   "1. app.log (12.3 KB)",
+		},
+		{
+			name:     "range suffix",
+			filepath: "/docs/intro.txt",
+			opts: &FormattingOptions{
+				HeaderFormat:    HeaderFormatFilename,
+				SequenceStyle:   SequenceNumerical,
+				HeaderStyle:     "none",
+				HeaderShowRange: true,
+			},
+			seqNum: 1,
+			doc:    &Document{ContentItems: []FileContent{{Filepath: "/docs/intro.txt", Ranges: []Range{{Start: 10, End: 20}, {Start: 30, End: 0}}}}},
+			want:   "1. intro.txt (L10-20,L30-)",
+		},
+		{
+			name:     "range suffix left out for a whole file",
+			filepath: "/docs/intro.txt",
+			opts: &FormattingOptions{
+				HeaderFormat:    HeaderFormatFilename,
+				SequenceStyle:   SequenceNumerical,
+				HeaderStyle:     "none",
+				HeaderShowRange: true,
+			},
+			seqNum: 1,
+			doc:    &Document{ContentItems: []FileContent{{Filepath: "/docs/intro.txt", Ranges: []Range{{Start: 1, End: 12}}, FileLines: 12}}},
+			want:   "1. intro.txt",
+		},
+		{
+			// Wide characters take two cells each, so the padding is
+			// measured in cells rather than bytes
+			name:     "right align path with unicode directories",
+			filepath: "/home/用户/文档/notes.txt",
+			opts: &FormattingOptions{
+				HeaderFormat:    HeaderFormatPath,
+				SequenceStyle:   SequenceNumerical,
+				HeaderAlignment: "right",
+				HeaderStyle:     "none",
+				PageWidth:       40,
+			},
+			seqNum: 1,
+			doc:    &Document{},
+			want:   "            1. /home/用户/文档/notes.txt",
+		},
+		{
+			name:     "right align dashed path with accented directory",
+			filepath: "/données/été.txt",
+			opts: &FormattingOptions{
+				HeaderFormat:    HeaderFormatPath,
+				SequenceStyle:   SequenceNumerical,
+				HeaderAlignment: "right",
+				HeaderStyle:     "dashed",
+				PageWidth:       30,
+			},
+			seqNum: 1,
+			doc:    &Document{},
+			want: "           -------------------\n" +
+				"           1. /données/été.txt\n" +
+				"           -------------------",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := generateFilename(tt.filepath, tt.opts, tt.seqNum, tt.doc)
+			item := FileContent{Filepath: tt.filepath}
+			for _, docItem := range tt.doc.ContentItems {
+				if docItem.Filepath == tt.filepath {
+					item = docItem
+				}
+			}
+			got := generateFilename(item, tt.opts, tt.seqNum, tt.doc, nil)
 			if got != tt.want {
 				t.Errorf("generateFilename() = %v, want %v", got, tt.want)
 			}
@@ -721,6 +862,34 @@ func TestRenderDocumentWithOutputFormat(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "plain_format_with_plain_headers",
+			doc: &Document{
+				ContentItems: []FileContent{
+					{Filepath: "/docs/a.txt", Content: "alpha\n"},
+					{Filepath: "/docs/b.txt", Content: "beta"},
+				},
+				FormattingOptions: FormattingOptions{
+					OutputFormat:  "plain",
+					ShowFilenames: true,
+					ShowTOC:       true,
+					PlainHeaders:  true,
+				},
+			},
+			ctx: &FormattingContext{
+				ShowFilenames: true,
+				ShowTOC:       true,
+				LineNumbers:   LineNumberFile,
+			},
+			outputFormat: "plain",
+			checkFunc: func(t *testing.T, result string) {
+				// A separator starts each file and nothing else is added
+				expected := "=== a.txt ===\nalpha\n=== b.txt ===\nbeta\n"
+				if result != expected {
+					t.Errorf("Expected %q, got %q", expected, result)
+				}
+			},
+		},
 		{
 			name: "term_format_uses_context",
 			doc: &Document{
@@ -0,0 +1,58 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTOCMaxEntries(t *testing.T) {
+	var content strings.Builder
+	for i := 1; i <= 10; i++ {
+		content.WriteString("## Heading " + string(rune('A'+i-1)) + "\n\ntext\n\n")
+	}
+
+	tests := []struct {
+		outputFormat string
+		expected     string
+	}{
+		{
+			outputFormat: "term",
+			expected:     "- Heading A (big.md)\n  - Heading B (big.md)\n  - Heading C (big.md)\n… (7 more entries)\n",
+		},
+		{
+			outputFormat: "markdown",
+			expected:     "- [big.md - Heading A](#)\n  - [big.md - Heading B](#)\n  - [big.md - Heading C](#)\n\n… (7 more entries)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outputFormat, func(t *testing.T) {
+			doc := &Document{
+				ContentItems: []FileContent{{Filepath: "/docs/big.md", Content: content.String()}},
+				FormattingOptions: FormattingOptions{
+					OutputFormat:  tt.outputFormat,
+					ShowTOC:       true,
+					TOCMaxEntries: 3,
+					HeaderFormat:  HeaderFormatNice,
+					SequenceStyle: SequenceNumerical,
+				},
+			}
+			ctx := &FormattingContext{ShowTOC: true, HeaderFormat: HeaderFormatNice, SequenceStyle: SequenceNumerical}
+
+			result, err := RenderDocument(doc, ctx)
+			if err != nil {
+				t.Fatalf("RenderDocument() error = %v", err)
+			}
+			if !strings.Contains(result, tt.expected) {
+				t.Errorf("Expected a TOC cut to 3 entries, got:\n%s", result)
+			}
+			if strings.Contains(result, "Heading D (") || strings.Contains(result, "big.md - Heading D") {
+				t.Errorf("Expected the fourth entry to be left out, got:\n%s", result)
+			}
+			// Headers still see the whole TOC
+			if len(doc.TOC) != 10 {
+				t.Errorf("Expected doc.TOC to keep all 10 entries, got %d", len(doc.TOC))
+			}
+		})
+	}
+}
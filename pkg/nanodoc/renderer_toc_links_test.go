@@ -0,0 +1,57 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTOCLinks(t *testing.T) {
+	newDoc := func(format string) *Document {
+		return &Document{
+			ContentItems: []FileContent{
+				{Filepath: "/docs/intro.md", Content: "# Intro"},
+				{Filepath: "/docs/usage.md", Content: "# Usage"},
+			},
+			FormattingOptions: FormattingOptions{
+				OutputFormat:  format,
+				ShowFilenames: true,
+				HeaderFormat:  HeaderFormatFilename,
+				SequenceStyle: SequenceNumerical,
+				ShowTOC:       true,
+				TOCLinks:      true,
+			},
+		}
+	}
+	ctx := &FormattingContext{ShowFilenames: true, HeaderFormat: HeaderFormatFilename, SequenceStyle: SequenceNumerical, ShowTOC: true, TOCLinks: true}
+
+	result, err := RenderDocument(newDoc("term"), ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if want := "- " + hyperlink("#nanodoc-file-2", "Usage") + " (usage.md)"; !strings.Contains(result, want) {
+		t.Errorf("Expected the TOC entry %q, got:\n%q", want, result)
+	}
+	if want := hyperlinkAnchor("nanodoc-file-2") + "2. usage.md"; !strings.Contains(result, want) {
+		t.Errorf("Expected an anchor before the header, got:\n%q", result)
+	}
+
+	// Without hyperlink support the TOC is plain text
+	plainCtx := ctx.Clone()
+	plainCtx.TOCLinks = false
+	result, err = RenderDocument(newDoc("term"), plainCtx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if strings.Contains(result, "\x1b]8;") || !strings.Contains(result, "- Usage (usage.md)") {
+		t.Errorf("Expected a plain TOC, got:\n%q", result)
+	}
+
+	// Markdown keeps its own links
+	result, err = RenderDocument(newDoc("markdown"), ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	if strings.Contains(result, "\x1b]8;") {
+		t.Errorf("Expected no hyperlinks in markdown output, got:\n%q", result)
+	}
+}
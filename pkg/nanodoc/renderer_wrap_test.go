@@ -0,0 +1,48 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		width   int
+		want    string
+	}{
+		{"short lines are kept", "one two\n\nthree", 10, "one two\n\nthree"},
+		{"breaks between words", "the quick brown fox jumps", 10, "the quick\nbrown fox\njumps"},
+		{"keeps indentation", "  - alpha beta gamma", 12, "  - alpha\n  beta gamma"},
+		{"long words get their own line", "a verylongword b", 6, "a\nverylongword\nb"},
+		{"blank lines are preserved", "aaa bbb\n\n\nccc ddd", 4, "aaa\nbbb\n\n\nccc\nddd"},
+		{"fenced code is left alone", "```\nlong code line here\n```\nwrap this text", 9, "```\nlong code line here\n```\nwrap this\ntext"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapContent(tt.content, tt.width); got != tt.want {
+				t.Errorf("wrapContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderWrapWidth(t *testing.T) {
+	doc := &Document{
+		ContentItems: []FileContent{
+			{Filepath: "/docs/notes.txt", Content: "the quick brown fox jumps\n\nover"},
+		},
+		FormattingOptions: FormattingOptions{OutputFormat: "term", WrapWidth: 10},
+	}
+	ctx := &FormattingContext{LineNumbers: LineNumberFile}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+	want := "1 | the quick\n2 | brown fox\n3 | jumps\n4 | \n5 | over"
+	if !strings.Contains(result, want) {
+		t.Errorf("Expected wrapped lines numbered one by one, got:\n%q", result)
+	}
+}
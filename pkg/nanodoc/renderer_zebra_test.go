@@ -0,0 +1,85 @@
+package nanodoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderZebra(t *testing.T) {
+	theme := &Theme{Name: "test", Styles: map[string]string{"zebra": "on bright_black"}}
+	stripe := theme.ZebraSGR()
+	if stripe != "\x1b[100m" {
+		t.Fatalf("ZebraSGR() = %q, want %q", stripe, "\x1b[100m")
+	}
+
+	doc := &Document{
+		ContentItems: []FileContent{{Filepath: "/logs/app.log", Content: "one\ntwo\nthree\nfour\nfive"}},
+		FormattingOptions: FormattingOptions{
+			OutputFormat: "term",
+			PageWidth:    12,
+			Zebra:        true,
+		},
+	}
+	ctx := &FormattingContext{Theme: theme, LineNumbers: LineNumberFile}
+
+	result, err := RenderDocument(doc, ctx)
+	if err != nil {
+		t.Fatalf("RenderDocument() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 content lines, got %d:\n%q", len(lines), result)
+	}
+	for i, line := range lines {
+		striped := strings.Contains(line, stripe)
+		if striped != (i%2 == 1) {
+			t.Errorf("Line %d striped = %v, want %v: %q", i+1, striped, i%2 == 1, line)
+		}
+		// The gutter stays unshaded and the stripe fills the page
+		if striped {
+			gutter := line[:strings.Index(line, " | ")]
+			if strings.Contains(gutter, stripe) {
+				t.Errorf("Line %d has a shaded gutter: %q", i+1, line)
+			}
+			if width := displayWidth(line); width != 12 {
+				t.Errorf("Line %d is %d cells wide, want 12: %q", i+1, width, line)
+			}
+		}
+	}
+
+	// Other formats ignore the flag
+	for _, format := range []string{"plain", "markdown"} {
+		doc.FormattingOptions.OutputFormat = format
+		result, err := RenderDocument(doc, ctx)
+		if err != nil {
+			t.Fatalf("RenderDocument(%s) error = %v", format, err)
+		}
+		if strings.Contains(result, stripe) {
+			t.Errorf("Expected no stripes in %s output, got:\n%q", format, result)
+		}
+	}
+}
+
+func TestStripeLinesWithTabs(t *testing.T) {
+	stripe := "\x1b[100m"
+	got := stripeLines("a\tb\nab\tc\td", stripe, 20)
+	lines := strings.Split(got, "\n")
+
+	if lines[0] != "a       b" {
+		t.Errorf("Expected the unstriped line's tab expanded, got %q", lines[0])
+	}
+	if want := stripe + "ab      c       d   " + ansiReset; lines[1] != want {
+		t.Errorf("Striped line = %q, want %q", lines[1], want)
+	}
+	if width := displayWidth(lines[1]); width != 20 {
+		t.Errorf("Striped line is %d cells wide, want 20", width)
+	}
+}
+
+func TestStripeLinesWithoutStyle(t *testing.T) {
+	content := "a\nb\nc"
+	if got := stripeLines(content, "", 10); got != content {
+		t.Errorf("stripeLines() without a zebra style = %q, want %q", got, content)
+	}
+}
@@ -20,6 +20,9 @@ type PathInfo struct {
 
 	// If directory, the files found within
 	Files []string
+
+	// Header title given in a bundle line ("intro.txt | Getting Started")
+	Title string
 }
 
 // ResolvePaths takes a list of source paths and resolves them to absolute paths
@@ -50,6 +53,33 @@ func ResolvePathsWithOptions(sources []string, options *FormattingOptions) ([]Pa
 	return results, nil
 }
 
+// ResolvePathsKeepGoing resolves paths like ResolvePathsWithOptions, but skips
+// sources that fail to resolve instead of stopping. The errors for skipped
+// sources are returned alongside the paths that did resolve.
+func ResolvePathsKeepGoing(sources []string, options *FormattingOptions) ([]PathInfo, []error, error) {
+	if len(sources) == 0 {
+		return nil, nil, ErrEmptySource
+	}
+
+	results := make([]PathInfo, 0, len(sources))
+	var skipped []error
+
+	for _, source := range sources {
+		pathInfo, err := resolveSinglePathWithOptions(source, options)
+		if err != nil {
+			skipped = append(skipped, &FileError{Path: source, Err: err})
+			continue
+		}
+		results = append(results, pathInfo)
+	}
+
+	if len(results) == 0 {
+		return nil, skipped, ErrEmptySource
+	}
+
+	return results, skipped, nil
+}
+
 // resolveSinglePath resolves a single path to PathInfo
 func resolveSinglePath(path string) (PathInfo, error) {
 	return resolveSinglePathWithOptions(path, nil)
@@ -57,6 +87,10 @@ func resolveSinglePath(path string) (PathInfo, error) {
 
 // resolveSinglePathWithOptions resolves a single path with optional pattern filtering
 func resolveSinglePathWithOptions(path string, options *FormattingOptions) (PathInfo, error) {
+	// Standard input is read when the content is extracted
+	if isStdinPath(path) {
+		return PathInfo{Original: path, Absolute: StdinName, Type: "file"}, nil
+	}
 	if strings.ContainsAny(path, "*?[") {
 		return resolveGlobPathWithOptions(path, options)
 	}
@@ -72,9 +106,12 @@ func resolveNonGlobPath(path string) (PathInfo, error) {
 func resolveNonGlobPathWithOptions(path string, options *FormattingOptions) (PathInfo, error) {
 	// Parse out any range specification for file system operations
 	// but keep the original path with range for later processing
-	basePath := path
-	if idx := strings.LastIndex(path, ":L"); idx > 0 {
-		basePath = path[:idx]
+	basePath, _ := parsePathWithRange(path)
+	// Strip a second spec too, so paths mixing line and byte ranges reach
+	// the extractor, which rejects them with a clearer error
+	basePath, _ = parsePathWithRange(basePath)
+	if basePath == "" {
+		basePath = path
 	}
 
 	absPath, err := filepath.Abs(basePath)
@@ -120,15 +157,33 @@ func handleDirectoryWithOptions(pathInfo PathInfo, options *FormattingOptions) (
 	
 	var files []string
 	var err error
+
+	// Patterns from the directory's .nanodocignore are added to the excludes
+	ignorePatterns, err := loadIgnorePatterns(pathInfo.Absolute)
+	if err != nil {
+		return PathInfo{}, err
+	}
+	var includePatterns, excludePatterns []string
+	if options != nil {
+		includePatterns = options.IncludePatterns
+		excludePatterns = options.ExcludePatterns
+	}
 	
 	// Check if we need pattern-based filtering
-	if options != nil && (len(options.IncludePatterns) > 0 || len(options.ExcludePatterns) > 0) {
-		matcher := NewPatternMatcher(pathInfo.Absolute, options.IncludePatterns, options.ExcludePatterns)
-		
-		if matcher.NeedsRecursion() {
-			files, err = findTextFilesRecursive(pathInfo.Absolute, options.AdditionalExtensions, matcher)
+	if len(includePatterns) > 0 || len(excludePatterns) > 0 || len(ignorePatterns) > 0 {
+		// Only the given patterns decide whether to scan subdirectories
+		recursive := NewPatternMatcher(pathInfo.Absolute, includePatterns, excludePatterns).NeedsRecursion()
+		excludePatterns = append(cloneStrings(excludePatterns), ignorePatterns...)
+		matcher := NewPatternMatcher(pathInfo.Absolute, includePatterns, excludePatterns)
+
+		var additionalExts []string
+		if options != nil {
+			additionalExts = options.AdditionalExtensions
+		}
+		if recursive {
+			files, err = findTextFilesRecursive(pathInfo.Absolute, additionalExts, matcher)
 		} else {
-			files, err = findTextFilesWithMatcher(pathInfo.Absolute, options.AdditionalExtensions, matcher)
+			files, err = findTextFilesWithMatcher(pathInfo.Absolute, additionalExts, matcher)
 		}
 	} else {
 		// No patterns, use existing behavior
@@ -142,10 +197,74 @@ func handleDirectoryWithOptions(pathInfo PathInfo, options *FormattingOptions) (
 	if err != nil {
 		return PathInfo{}, err
 	}
+
+	// Only keep the explicitly requested extensions
+	if options != nil && options.NoDefaultExtensions {
+		files = filterByExtensions(files, options.AdditionalExtensions)
+	}
+
 	pathInfo.Files = files
 	return pathInfo, nil
 }
 
+// loadIgnorePatterns reads the .nanodocignore file in dir, if there is one,
+// and turns its gitignore-style lines into exclude patterns relative to dir.
+// Blank lines, # comments and ! negations are skipped.
+func loadIgnorePatterns(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		dirOnly := strings.HasSuffix(line, "/")
+		line = strings.TrimSuffix(line, "/")
+		// Like gitignore, a pattern with no "/" but a trailing one matches
+		// at any depth, and a leading "/" anchors it to dir
+		if !strings.Contains(line, "/") {
+			line = "**/" + line
+		}
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		// A pattern naming a directory excludes everything under it
+		if !dirOnly {
+			patterns = append(patterns, line)
+		}
+		patterns = append(patterns, line+"/**")
+	}
+	return patterns, nil
+}
+
+// filterByExtensions keeps only the files whose extension is in extensions
+func filterByExtensions(files []string, extensions []string) []string {
+	filtered := make([]string, 0, len(files))
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file))
+		for _, want := range extensions {
+			if !strings.HasPrefix(want, ".") {
+				want = "." + want
+			}
+			if ext == strings.ToLower(want) {
+				filtered = append(filtered, file)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 // handleFile processes a file path.
 func handleFile(pathInfo PathInfo) (PathInfo, error) {
 	if isBundleFile(pathInfo.Absolute) {
@@ -163,7 +282,13 @@ func resolveGlobPath(pattern string) (PathInfo, error) {
 
 // resolveGlobPathWithOptions resolves a glob pattern with optional additional filtering
 func resolveGlobPathWithOptions(pattern string, options *FormattingOptions) (PathInfo, error) {
-	matches, err := filepath.Glob(pattern)
+	// A range applies to every match, so it is not part of the pattern
+	globPattern, _ := parsePathWithRange(pattern)
+	if options != nil && options.GlobBase != "" && !filepath.IsAbs(globPattern) {
+		globPattern = filepath.Join(options.GlobBase, globPattern)
+	}
+
+	matches, err := filepath.Glob(globPattern)
 	if err != nil {
 		return PathInfo{}, err
 	}
@@ -187,7 +312,10 @@ func resolveGlobPathWithOptions(pattern string, options *FormattingOptions) (Pat
 
 		if !info.IsDir() {
 			isText := isTextFile(absPath)
-			if options != nil && len(options.AdditionalExtensions) > 0 {
+			if options != nil && options.NoDefaultExtensions {
+				// Trust the glob pattern to select the files
+				isText = true
+			} else if options != nil && len(options.AdditionalExtensions) > 0 {
 				isText = isTextFileWithExtensions(absPath, options.AdditionalExtensions)
 			}
 			if isText {
@@ -155,4 +155,57 @@ func TestResolveDirectoryWithAdditionalExtensionsNoOptions(t *testing.T) {
 	if !foundTxxt {
 		t.Error("Expected to find .txxt file when using --ext txxt, but didn't")
 	}
-}
\ No newline at end of file
+}
+func TestResolveGlobWithNoDefaultExtensions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"report.custom", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pattern := filepath.Join(tmpDir, "*.custom")
+
+	// Without the flag the glob finds no text files
+	if _, err := ResolvePathsWithOptions([]string{pattern}, nil); err == nil {
+		t.Error("Expected no matches for *.custom without --no-default-extensions")
+	}
+
+	options := &FormattingOptions{NoDefaultExtensions: true}
+	paths, err := ResolvePathsWithOptions([]string{pattern}, options)
+	if err != nil {
+		t.Fatalf("Failed to resolve paths: %v", err)
+	}
+
+	if len(paths) != 1 || len(paths[0].Files) != 1 {
+		t.Fatalf("Expected a single glob match, got %+v", paths)
+	}
+	if filepath.Base(paths[0].Files[0]) != "report.custom" {
+		t.Errorf("Expected report.custom, got %s", paths[0].Files[0])
+	}
+}
+
+func TestResolveDirectoryWithNoDefaultExtensions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"a.txt", "b.md", "c.custom"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options := &FormattingOptions{
+		NoDefaultExtensions:  true,
+		AdditionalExtensions: []string{"custom"},
+	}
+	paths, err := ResolvePathsWithOptions([]string{tmpDir}, options)
+	if err != nil {
+		t.Fatalf("Failed to resolve paths: %v", err)
+	}
+
+	files := paths[0].Files
+	if len(files) != 1 || filepath.Base(files[0]) != "c.custom" {
+		t.Errorf("Expected only c.custom, got %v", files)
+	}
+}
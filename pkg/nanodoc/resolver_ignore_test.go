@@ -0,0 +1,97 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadIgnorePatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	patterns, err := loadIgnorePatterns(tmpDir)
+	if err != nil || patterns != nil {
+		t.Fatalf("Expected no patterns without an ignore file, got %v (%v)", patterns, err)
+	}
+
+	content := "# generated\n*.gen.md\n\nvendor/\n/build\ndocs/draft.md\n!keep.md\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, IgnoreFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	patterns, err = loadIgnorePatterns(tmpDir)
+	if err != nil {
+		t.Fatalf("loadIgnorePatterns() error = %v", err)
+	}
+	want := []string{
+		"**/*.gen.md", "**/*.gen.md/**",
+		"**/vendor/**",
+		"build", "build/**",
+		"docs/draft.md", "docs/draft.md/**",
+	}
+	if !reflect.DeepEqual(patterns, want) {
+		t.Errorf("loadIgnorePatterns() = %v, want %v", patterns, want)
+	}
+}
+
+func TestResolveDirectoryWithIgnoreFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		IgnoreFileName:        "*.gen.md\nvendor/\n",
+		"intro.md":            "# Intro",
+		"api.gen.md":          "# Generated",
+		"notes.txt":           "notes",
+		"docs/guide.md":       "# Guide",
+		"docs/ref.gen.md":     "# Generated",
+		"vendor/lib/READ.md":  "# Vendored",
+		"docs/.nanodocignore": "guide.md\n",
+	}
+	for path, content := range testFiles {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		options   *FormattingOptions
+		wantFiles []string
+	}{
+		{
+			name:      "ignore file alone keeps the scan flat",
+			wantFiles: []string{"intro.md", "notes.txt"},
+		},
+		{
+			name:      "unioned with CLI excludes",
+			options:   &FormattingOptions{ExcludePatterns: []string{"*.txt"}},
+			wantFiles: []string{"intro.md"},
+		},
+		{
+			// Only the scanned directory's ignore file is read, so
+			// docs/guide.md is kept
+			name:      "recursive scan",
+			options:   &FormattingOptions{IncludePatterns: []string{"**/*.md"}},
+			wantFiles: []string{"docs/guide.md", "intro.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathInfos, err := ResolvePathsWithOptions([]string{tmpDir}, tt.options)
+			if err != nil {
+				t.Fatalf("ResolvePathsWithOptions() error = %v", err)
+			}
+			var got []string
+			for _, file := range pathInfos[0].Files {
+				rel, _ := filepath.Rel(tmpDir, file)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.wantFiles) {
+				t.Errorf("Files = %v, want %v", got, tt.wantFiles)
+			}
+		})
+	}
+}
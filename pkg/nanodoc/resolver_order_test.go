@@ -3,6 +3,7 @@ package nanodoc
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -122,4 +123,40 @@ func TestResolvePathsWithDirectoryPreservesOrder(t *testing.T) {
 			t.Errorf("ResolvePaths() result[%d].Absolute = %s, want %s", i, result.Absolute, expectedOrder[i])
 		}
 	}
-}
\ No newline at end of file
+}
+func TestDirectoryAndGlobKeepArgumentOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"z-dir/b.txt", "z-dir/a.txt", "a-dir/2.txt", "a-dir/1.txt", "middle.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each argument's files stay together, sorted by path within it
+	sources := []string{
+		filepath.Join(tempDir, "z-dir"),
+		filepath.Join(tempDir, "middle.txt"),
+		filepath.Join(tempDir, "a-dir", "*.txt"),
+	}
+	pathInfos, err := ResolvePaths(sources)
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	doc, err := BuildDocument(pathInfos, FormattingOptions{})
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+
+	var got []string
+	for _, item := range doc.ContentItems {
+		got = append(got, item.Content)
+	}
+	want := []string{"z-dir/a.txt", "z-dir/b.txt", "middle.txt", "a-dir/1.txt", "a-dir/2.txt"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected files in order %v, got %v", want, got)
+	}
+}
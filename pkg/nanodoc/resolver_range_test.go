@@ -1,6 +1,7 @@
 package nanodoc
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -102,4 +103,77 @@ func TestResolveNonGlobPathWithRange(t *testing.T) {
 	if result.Absolute != expectedAbs {
 		t.Errorf("Absolute = %v, want %v", result.Absolute, expectedAbs)
 	}
-}
\ No newline at end of file
+}
+func TestDirectoryAndGlobRanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"a.go.txt": "// License A\n// line 2\npackage a\nfunc A() {}",
+		"b.go.txt": "// License B",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "directory",
+			source: tmpDir + string(filepath.Separator) + ":L1-2",
+			want:   []string{"// License A\n// line 2", "// License B"},
+		},
+		{
+			name:   "glob",
+			source: filepath.Join(tmpDir, "*.txt") + ":L1",
+			want:   []string{"// License A", "// License B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathInfos, err := ResolvePaths([]string{tt.source})
+			if err != nil {
+				t.Fatalf("ResolvePaths() error = %v", err)
+			}
+
+			contents, err := ResolveAndExtractFiles(pathInfos, nil)
+			if err != nil {
+				t.Fatalf("ResolveAndExtractFiles() error = %v", err)
+			}
+			doc, err := BuildDocument(pathInfos, FormattingOptions{})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %v", err)
+			}
+
+			for _, got := range [][]FileContent{contents, doc.ContentItems} {
+				if len(got) != len(tt.want) {
+					t.Fatalf("Expected %d files, got %d", len(tt.want), len(got))
+				}
+				for i, want := range tt.want {
+					if got[i].Content != want {
+						t.Errorf("File %d content = %q, want %q", i, got[i].Content, want)
+					}
+				}
+			}
+		})
+	}
+
+	// A range that does not fit one of the files names it
+	pathInfos, err := ResolvePaths([]string{tmpDir + string(filepath.Separator) + ":L$2-1"})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	_, err = ResolveAndExtractFiles(pathInfos, nil)
+	var fileErr *FileError
+	var rangeErr *RangeError
+	if !errors.As(err, &fileErr) || !errors.As(err, &rangeErr) {
+		t.Fatalf("ResolveAndExtractFiles() error = %v, want a FileError wrapping a RangeError", err)
+	}
+	if fileErr.Path != filepath.Join(tmpDir, "a.go.txt") {
+		t.Errorf("Expected the error to name a.go.txt, got %q", fileErr.Path)
+	}
+}
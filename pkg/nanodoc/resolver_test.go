@@ -1,8 +1,11 @@
 package nanodoc
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -439,3 +442,78 @@ func TestGetFilesFromDirectory(t *testing.T) {
 		})
 	}
 }
+
+func TestResolvePathsKeepGoing(t *testing.T) {
+	tempDir := t.TempDir()
+	valid := filepath.Join(tempDir, "valid.txt")
+	if err := os.WriteFile(valid, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(tempDir, "missing.txt")
+
+	paths, skipped, err := ResolvePathsKeepGoing([]string{valid, missing}, nil)
+	if err != nil {
+		t.Fatalf("ResolvePathsKeepGoing() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0].Absolute != valid {
+		t.Errorf("Expected only the valid path, got %+v", paths)
+	}
+	if len(skipped) != 1 || !errors.Is(skipped[0], ErrFileNotFound) {
+		t.Fatalf("Expected one ErrFileNotFound, got %v", skipped)
+	}
+	if !strings.Contains(skipped[0].Error(), missing) {
+		t.Errorf("Expected skipped error to name the source, got %v", skipped[0])
+	}
+
+	// Nothing left to render is still an error
+	if _, _, err := ResolvePathsKeepGoing([]string{missing}, nil); err == nil {
+		t.Error("Expected an error when no source resolves")
+	}
+}
+
+func TestResolveGlobWithBase(t *testing.T) {
+	baseDir := t.TempDir()
+	for _, name := range []string{"a.md", "b.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(baseDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The working directory has no markdown files
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldDir) }()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ResolvePathsWithOptions([]string{"*.md"}, &FormattingOptions{}); err == nil {
+		t.Fatal("Expected *.md to match nothing in the working directory")
+	}
+
+	results, err := ResolvePathsWithOptions([]string{"*.md"}, &FormattingOptions{GlobBase: baseDir})
+	if err != nil {
+		t.Fatalf("ResolvePathsWithOptions() error = %v", err)
+	}
+	want := []string{filepath.Join(baseDir, "a.md"), filepath.Join(baseDir, "b.md")}
+	if len(results) != 1 || !reflect.DeepEqual(results[0].Files, want) {
+		t.Errorf("Expected %v, got %+v", want, results)
+	}
+	if results[0].Original != "*.md" {
+		t.Errorf("Expected the original pattern to be kept, got %q", results[0].Original)
+	}
+
+	// Absolute patterns ignore the base
+	otherDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(otherDir, "c.md"), []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err = ResolvePathsWithOptions([]string{filepath.Join(otherDir, "*.md")}, &FormattingOptions{GlobBase: baseDir})
+	if err != nil {
+		t.Fatalf("ResolvePathsWithOptions() error = %v", err)
+	}
+	if want := []string{filepath.Join(otherDir, "c.md")}; !reflect.DeepEqual(results[0].Files, want) {
+		t.Errorf("Expected %v for an absolute pattern, got %v", want, results[0].Files)
+	}
+}
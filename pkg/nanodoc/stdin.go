@@ -0,0 +1,78 @@
+package nanodoc
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// StdinPath is the source argument that stands for standard input
+const StdinPath = "-"
+
+// StdinName is the file path shown for content read from standard input
+const StdinName = "<stdin>"
+
+// Standard input can only be read once, so its content is kept for every
+// "-" source and range that refers to it
+var (
+	stdinMu     sync.Mutex
+	stdinSource io.Reader = os.Stdin
+	stdinData   []byte
+	stdinErr    error
+	stdinRead   bool
+)
+
+// SetStdin sets the reader "-" sources come from, dropping any content
+// buffered from the previous one
+func SetStdin(r io.Reader) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	stdinSource = r
+	stdinData, stdinErr, stdinRead = nil, nil, false
+}
+
+// readStdin returns all of standard input, reading it on first use only
+func readStdin() ([]byte, error) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	if !stdinRead {
+		stdinData, stdinErr = io.ReadAll(stdinSource)
+		stdinRead = true
+	}
+	return stdinData, stdinErr
+}
+
+// isStdinPath reports whether a source, ignoring any range, is standard input
+func isStdinPath(pathWithRange string) bool {
+	path, _ := parsePathWithRange(pathWithRange)
+	return path == StdinPath
+}
+
+// sourceName returns the path content from path is shown under
+func sourceName(path string) string {
+	if path == StdinPath {
+		return StdinName
+	}
+	return path
+}
+
+// openSource opens path for reading; "-" gives the buffered standard input
+func openSource(path string) (io.ReadCloser, error) {
+	if path == StdinPath {
+		data, err := readStdin()
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return os.Open(path)
+}
+
+// readSource reads all of path; "-" gives the buffered standard input
+func readSource(path string) ([]byte, error) {
+	if path == StdinPath {
+		return readStdin()
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,55 @@
+package nanodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStdinSource(t *testing.T) {
+	SetStdin(strings.NewReader("one\ntwo\nthree\nfour\nfive\nsix\n"))
+	defer SetStdin(os.Stdin)
+
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(file, []byte("from a file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same input is used by every "-" source, with or without a range
+	pathInfos, err := ResolvePaths([]string{file, "-:L2-5", "-"})
+	if err != nil {
+		t.Fatalf("ResolvePaths() error = %v", err)
+	}
+	doc, err := BuildDocument(pathInfos, FormattingOptions{})
+	if err != nil {
+		t.Fatalf("BuildDocument() error = %v", err)
+	}
+
+	expected := []FileContent{
+		{Filepath: file, Content: "from a file"},
+		{Filepath: StdinName, Content: "two\nthree\nfour\nfive"},
+		{Filepath: StdinName, Content: "one\ntwo\nthree\nfour\nfive\nsix"},
+	}
+	if len(doc.ContentItems) != len(expected) {
+		t.Fatalf("Expected %d items, got %+v", len(expected), doc.ContentItems)
+	}
+	for i, want := range expected {
+		got := doc.ContentItems[i]
+		if got.Filepath != want.Filepath || got.Content != want.Content {
+			t.Errorf("Item %d = %q %q, want %q %q", i, got.Filepath, got.Content, want.Filepath, want.Content)
+		}
+	}
+
+	info, err := GenerateDryRunInfo(pathInfos[1:2], FormattingOptions{})
+	if err != nil {
+		t.Fatalf("GenerateDryRunInfo() error = %v", err)
+	}
+	if info.Files[0].LineCount != 4 {
+		t.Errorf("Expected 4 lines from standard input, got %d", info.Files[0].LineCount)
+	}
+	output := FormatDryRunOutput(info)
+	if !strings.Contains(output, "From standard input:\n") || !strings.Contains(output, "<stdin>:L2-5") {
+		t.Errorf("Expected standard input in the dry run, got:\n%s", output)
+	}
+}
@@ -1,5 +1,10 @@
 package nanodoc
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Range represents a line range in a file
 // Start is 1-based inclusive, End is 1-based inclusive (or 0 for EOF)
 type Range struct {
@@ -15,6 +20,15 @@ type FileContent struct {
 	// Line ranges to include
 	Ranges []Range
 
+	// Number of lines in the whole file, before Ranges were applied
+	FileLines int
+
+	// Title from the markdown front matter removed with StripFrontMatter
+	FrontMatterTitle string
+
+	// Header title given for the file in its bundle line
+	HeaderTitle string
+
 	// Content after applying ranges
 	Content string
 
@@ -23,6 +37,13 @@ type FileContent struct {
 
 	// Source file if part of an inline bundle
 	OriginalSource string
+
+	// Bundle file this content was expanded from, if any
+	SourceGroup string
+
+	// Title of a bundle comment rendered as a section header; such items
+	// have no content of their own
+	SectionTitle string
 }
 
 // Document represents the entire document after processing bundles
@@ -41,6 +62,13 @@ type Document struct {
 
 	// Formatting options
 	FormattingOptions FormattingOptions
+
+	// Files left out for being larger than MaxFileSize
+	Skipped []error
+
+	// Nearest directory containing every file, which relative headers are
+	// shown from; "" has it worked out from ContentItems when needed
+	CommonRoot string
 }
 
 // TOCEntry represents an entry in the table of contents
@@ -54,6 +82,9 @@ type TOCEntry struct {
 	// Heading level (1 for H1, 2 for H2, etc.)
 	Level int
 
+	// GitHub-style anchor of the heading, unique within its file
+	Slug string
+
 	// Sequence number/letter/roman numeral if applicable
 	Sequence string
 
@@ -81,26 +112,214 @@ type FormattingOptions struct {
 	// Whether to show table of contents
 	ShowTOC bool
 
+	// Most entries shown in the table of contents; 0 shows them all
+	TOCMaxEntries int
+
 	// Header alignment
 	HeaderAlignment string
 
 	// Header style
 	HeaderStyle string
 
+	// Character that replaces the rule lines of the dashed and solid header
+	// styles and the border of the boxed one; "" keeps their own
+	HeaderChar string
+
+	// Where file header numbers restart at 1 (none, bundle, dir)
+	FileNumberReset string
+
+	// Number of the first file header; 0 is the same as 1
+	SequenceStart int
+
 	// Page width for alignment
 	PageWidth int
 
 	// Additional file extensions to process
 	AdditionalExtensions []string
 
+	// Render each bundle as one section with lighter per-file sub-headers
+	BundleAsSection bool
+
+	// Sort the files listed in each bundle alphabetically instead of keeping
+	// their declaration order
+	SortBundle bool
+
+	// How resolved files are deduplicated (none, basename, dir)
+	UniqueBy string
+
+	// What to do with a file included more than once with the same ranges
+	// (keep, skip, error)
+	OnDuplicate string
+
+	// Drop lines that were already included earlier, by exact match
+	DedupeLines bool
+
+	// Where DedupeLines looks for earlier copies (document, file)
+	DedupeLinesScope string
+
+	// Values for ${VAR} placeholders in bundle files
+	BundleVars map[string]string
+
+	// Value for undefined bundle variables; nil makes them an error
+	BundleVarDefault *string
+
+	// Don't treat the default extensions as text: directories only include
+	// AdditionalExtensions and globs include whatever they match
+	NoDefaultExtensions bool
+
+	// Directory relative glob patterns are matched under instead of the
+	// working directory; "" keeps the working directory
+	GlobBase string
+
 	// Include patterns for file filtering (gitignore-style)
 	IncludePatterns []string
 
 	// Exclude patterns for file filtering (gitignore-style)
 	ExcludePatterns []string
 
-	// Output format (term, plain, markdown)
+	// Output format (term, plain, markdown, json)
 	OutputFormat string
+
+	// Leave each file's content out of json output, keeping its metadata
+	JSONOmitContent bool
+
+	// Whether to show a numbered index of the included files
+	ShowFileIndex bool
+
+	// Position of the file index relative to the TOC (before-toc, after-toc)
+	FileIndexPosition string
+
+	// Wrap non-markdown files in fenced code blocks in markdown output
+	MarkdownCodeFences bool
+
+	// Wrap each file's content in a collapsible <details> block in markdown output
+	MarkdownCollapsible bool
+
+	// Shift markdown headings so the document has exactly one H1, the first
+	// file's top heading, and every other file starts at H2
+	MarkdownNormalize bool
+
+	// KEY=VALUE pairs emitted as a YAML front matter block in markdown output
+	MarkdownFrontMatter []string
+
+	// How term output handles lines wider than the page (none, truncate, wrap)
+	Overflow string
+
+	// Render markdown files with terminal styling in term output
+	RenderMarkdown bool
+
+	// With RenderMarkdown, draw GFM tables as aligned box tables
+	RenderMarkdownTables bool
+
+	// Show the empty-file placeholder without a line number
+	EmptyNoNumber bool
+
+	// Which files get line numbers (all, code, text)
+	LineNumberScope string
+
+	// Number only files with at least this many lines; 0 numbers all. In
+	// global mode the lines of shorter files still use up numbers.
+	LineNumberMinLines int
+
+	// Render decorated bundle comments ("# --- Title ---") as section headers
+	BundleCommentsAsSections bool
+
+	// Headings of the markdown sections to keep from each file
+	Sections []string
+
+	// Patterns of files whose [[file:]] directives are processed even when
+	// their name (README, CHANGELOG, ...) would skip them
+	ProcessIncludesIn []string
+
+	// Skip files without any of the Sections instead of including them fully
+	SectionOnly bool
+
+	// Line ranges to mark in each file in term output, e.g. "L3-5,L8"
+	HighlightLines string
+
+	// Legend printed before the content when lines are highlighted
+	HighlightLegend string
+
+	// Color the line numbers of highlighted lines with the emphasis style
+	HighlightGutter bool
+
+	// Directory caching rendered term fragments across runs; "" disables it
+	CacheDir string
+
+	// Largest file, in bytes, that is read; 0 means no limit
+	MaxFileSize int64
+
+	// What to do with a file over MaxFileSize: OversizeSkip or OversizeError
+	OnOversize string
+
+	// How many files are read at once; 0 uses runtime.NumCPU()
+	Concurrency int
+
+	// Printed instead of the empty output of a document with no content
+	// items in the term, plain and markdown formats; "" prints nothing
+	EmptyDocumentMessage string
+
+	// Lay term output out right to left: content right-aligned, line numbers
+	// after it and headers right-aligned unless aligned explicitly
+	RTL bool
+
+	// Start each file in plain output with an "=== name ===" line
+	PlainHeaders bool
+
+	// Collapse runs of blank lines in term output to a single blank line
+	SqueezeBlanks bool
+
+	// With SqueezeBlanks, show each collapsed run as one "⋮" line that gets
+	// no line number
+	BlankMarker bool
+
+	// Compact is a preset for dense output: inline headers, squeezed blank
+	// lines and no TOC, each unless set explicitly (see ApplyCompactPreset)
+	Compact bool
+
+	// With RenderMarkdown, rejoin hard-wrapped paragraphs and wrap them to
+	// PageWidth; code blocks, lists and blockquotes keep their lines
+	Reflow bool
+
+	// Word-wrap file content to this many columns before numbering (0 is off)
+	WrapWidth int
+
+	// Strict makes a live bundle directive whose target is missing an error
+	// instead of leaving it in the output
+	Strict bool
+
+	// Remove the YAML front matter block markdown files start with, using its
+	// title as the file's header title
+	StripFrontMatter bool
+
+	// TOCLinks makes term TOC entries OSC 8 hyperlinks to anchors before
+	// the file headers, where the terminal supports them
+	TOCLinks bool
+
+	// HeadersOnly renders the file headers and TOC but leaves out file content
+	HeadersOnly bool
+
+	// Put a "# --- line N ---" sentinel before every Nth line of plain
+	// output, counting lines across all files; 0 disables it
+	AnchorEvery int
+
+	// Shade every other content line in term output with the theme's zebra
+	// background, leaving the line number gutter unshaded
+	Zebra bool
+
+	// Color the keywords, strings, comments and numbers of code files in
+	// term output
+	Highlight bool
+
+	// Show "[1]" markers as file headers in term output and list the paths
+	// they stand for after the content
+	FootnotePaths bool
+
+	// Append the size of each file's content to its header, e.g. "(12.3 KB)"
+	HeaderShowSize bool
+
+	// Append the line ranges a file was included with to its header
+	HeaderShowRange bool
 }
 
 // NewRange creates a new Range with validation
@@ -131,6 +350,45 @@ func (r Range) Contains(line int) bool {
 	return line <= r.End
 }
 
+// String returns the range in path syntax: "L5-10", "L5" for a single line
+// and "L8-" when it runs to the end of the file
+func (r Range) String() string {
+	switch {
+	case r.End == 0:
+		return fmt.Sprintf("L%d-", r.Start)
+	case r.End == r.Start:
+		return fmt.Sprintf("L%d", r.Start)
+	default:
+		return fmt.Sprintf("L%d-%d", r.Start, r.End)
+	}
+}
+
+// MarshalText encodes the range in path syntax, e.g. in JSON output
+func (r Range) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// FormatRanges joins ranges into a path range specification like "L1-2,L5"
+func FormatRanges(ranges []Range) string {
+	specs := make([]string, len(ranges))
+	for i, r := range ranges {
+		specs[i] = r.String()
+	}
+	return strings.Join(specs, ",")
+}
+
+// coversFile reports whether ranges select every line of a file with
+// fileLines lines, as the default range of a path without one does
+func coversFile(ranges []Range, fileLines int) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	if len(ranges) > 1 || ranges[0].Start != 1 {
+		return false
+	}
+	return ranges[0].End == 0 || (fileLines > 0 && ranges[0].End >= fileLines)
+}
+
 // IsFullFile returns true if this range represents the entire file
 func (r Range) IsFullFile() bool {
 	return r.Start == 1 && r.End == 0
@@ -142,13 +400,65 @@ func NewDocument() *Document {
 		ContentItems: make([]FileContent, 0),
 		TOC:          make([]TOCEntry, 0),
 		FormattingOptions: FormattingOptions{
-			Theme:           ThemeClassic,
-			ShowFilenames:   true,
-			HeaderFormat:    HeaderFormatNice,
-			SequenceStyle:   SequenceNumerical,
-			LineNumbers:     LineNumberNone,
-			HeaderAlignment: "left",
-			HeaderStyle:     "none",
+			Theme:             ThemeClassic,
+			ShowFilenames:     true,
+			HeaderFormat:      HeaderFormatNice,
+			SequenceStyle:     SequenceNumerical,
+			LineNumbers:       LineNumberNone,
+			HeaderAlignment:   "left",
+			HeaderStyle:       "none",
+			FileIndexPosition: FileIndexBeforeTOC,
+			Overflow:          OverflowNone,
+			UniqueBy:          UniqueByNone,
+			OnDuplicate:       DuplicateKeep,
 		},
 	}
-}
\ No newline at end of file
+}
+
+// Validate checks a document, typically one built by hand, before it is
+// rendered: every content item needs a file path when headers are shown,
+// ranges must be well-formed and the formatting options valid.
+func (d *Document) Validate() error {
+	for i, item := range d.ContentItems {
+		if item.Filepath == "" && d.FormattingOptions.ShowFilenames {
+			return fmt.Errorf("content item %d has no file path, which headers need", i+1)
+		}
+		for _, r := range item.Ranges {
+			if _, err := NewRange(r.Start, r.End); err != nil {
+				return fmt.Errorf("content item %d (%s): %w", i+1, item.Filepath, err)
+			}
+		}
+	}
+	return d.FormattingOptions.Validate()
+}
+
+// Clone returns a copy of opts that shares no slices, maps or pointers with
+// the original, so either can be modified without affecting the other
+func (opts FormattingOptions) Clone() FormattingOptions {
+	clone := opts
+	clone.AdditionalExtensions = cloneStrings(opts.AdditionalExtensions)
+	clone.IncludePatterns = cloneStrings(opts.IncludePatterns)
+	clone.ExcludePatterns = cloneStrings(opts.ExcludePatterns)
+	clone.Sections = cloneStrings(opts.Sections)
+	clone.ProcessIncludesIn = cloneStrings(opts.ProcessIncludesIn)
+	clone.MarkdownFrontMatter = cloneStrings(opts.MarkdownFrontMatter)
+	if opts.BundleVars != nil {
+		clone.BundleVars = make(map[string]string, len(opts.BundleVars))
+		for k, v := range opts.BundleVars {
+			clone.BundleVars[k] = v
+		}
+	}
+	if opts.BundleVarDefault != nil {
+		value := *opts.BundleVarDefault
+		clone.BundleVarDefault = &value
+	}
+	return clone
+}
+
+// cloneStrings copies a string slice, keeping nil as nil
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
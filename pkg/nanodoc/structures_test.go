@@ -2,6 +2,7 @@ package nanodoc
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -163,6 +164,45 @@ func TestRange_IsFullFile(t *testing.T) {
 	}
 }
 
+func TestRange_String(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Range
+		want string
+	}{
+		{
+			name: "closed range",
+			r:    Range{Start: 5, End: 10},
+			want: "L5-10",
+		},
+		{
+			name: "single line",
+			r:    Range{Start: 5, End: 5},
+			want: "L5",
+		},
+		{
+			name: "open-ended range",
+			r:    Range{Start: 8, End: 0},
+			want: "L8-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.String(); got != tt.want {
+				t.Errorf("Range.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRanges(t *testing.T) {
+	ranges := []Range{{Start: 1, End: 2}, {Start: 5, End: 5}, {Start: 8, End: 0}}
+	if got := FormatRanges(ranges); got != "L1-2,L5,L8-" {
+		t.Errorf("FormatRanges() = %q, want %q", got, "L1-2,L5,L8-")
+	}
+}
+
 func TestNewDocument(t *testing.T) {
 	doc := NewDocument()
 
@@ -195,6 +235,73 @@ func TestNewDocument(t *testing.T) {
 	}
 }
 
+func TestDocumentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(doc *Document)
+		wantErr string
+	}{
+		{
+			name:   "valid document",
+			modify: func(doc *Document) {},
+		},
+		{
+			name: "missing file path with headers",
+			modify: func(doc *Document) {
+				doc.ContentItems = append(doc.ContentItems, FileContent{Content: "orphan"})
+			},
+			wantErr: "content item 2 has no file path",
+		},
+		{
+			name: "missing file path without headers",
+			modify: func(doc *Document) {
+				doc.ContentItems = append(doc.ContentItems, FileContent{Content: "orphan"})
+				doc.FormattingOptions.ShowFilenames = false
+			},
+		},
+		{
+			name: "malformed range",
+			modify: func(doc *Document) {
+				doc.ContentItems[0].Ranges = []Range{{Start: 5, End: 2}}
+			},
+			wantErr: "content item 1 (/docs/a.txt)",
+		},
+		{
+			name: "invalid options",
+			modify: func(doc *Document) {
+				doc.FormattingOptions.Overflow = "scroll"
+			},
+			wantErr: "invalid --overflow value",
+		},
+		{
+			name: "invalid header format",
+			modify: func(doc *Document) {
+				doc.FormattingOptions.HeaderFormat = "title"
+			},
+			wantErr: "invalid --header-format value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := NewDocument()
+			doc.ContentItems = []FileContent{{Filepath: "/docs/a.txt", Content: "a", Ranges: []Range{{Start: 1, End: 1}}}}
+			tt.modify(doc)
+
+			err := doc.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestFileContent(t *testing.T) {
 	// Test FileContent structure initialization
 	fc := FileContent{
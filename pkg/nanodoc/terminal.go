@@ -24,4 +24,30 @@ func GetTerminalWidth() int {
 // IsTerminal checks if the given file descriptor is a terminal
 func IsTerminal(fd int) bool {
 	return term.IsTerminal(fd)
-}
\ No newline at end of file
+}
+
+// SupportsColor reports whether ANSI colors can be shown on the given file
+// descriptor: it must be a terminal, and NO_COLOR or TERM=dumb turn them off
+func SupportsColor(fd int) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(fd)
+}
+
+// SupportsHyperlinks reports whether OSC 8 hyperlinks can be shown on the
+// given file descriptor; NO_COLOR and TERM=dumb turn them off like colors
+func SupportsHyperlinks(fd int) bool {
+	return SupportsColor(fd)
+}
+
+// hyperlink wraps text in an OSC 8 hyperlink to uri
+func hyperlink(uri, text string) string {
+	return "\x1b]8;;" + uri + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// hyperlinkAnchor is an empty OSC 8 hyperlink carrying id, an invisible
+// marker that TOC links point to
+func hyperlinkAnchor(id string) string {
+	return "\x1b]8;id=" + id + ";\x1b\\\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,215 @@
+package nanodoc
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ansiReset clears all SGR styling
+const ansiReset = "\x1b[0m"
+
+// Ellipsis is appended to lines cut by the truncate overflow mode
+const Ellipsis = "…"
+
+// wideRanges lists code point ranges rendered two cells wide in terminals
+// (East Asian wide/fullwidth characters and emoji)
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of terminal cells a rune occupies
+func runeWidth(r rune) int {
+	if r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+	if r < 0x1100 {
+		return 1
+	}
+	for _, rng := range wideRanges {
+		if r >= rng[0] && r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// ansiSequenceLength returns the byte length of the ANSI escape sequence at
+// the start of s, or 0 when s does not start with one
+func ansiSequenceLength(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		// CSI: parameters end with a byte in the range 0x40-0x7E
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		// OSC: terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 0
+}
+
+// trackSGR returns the SGR styling still active after s is printed, given
+// the styling active before it. A reset clears the accumulated styles.
+func trackSGR(active, s string) string {
+	for i := 0; i < len(s); {
+		n := ansiSequenceLength(s[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		seq := s[i : i+n]
+		if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+			if seq == ansiReset || seq == "\x1b[m" {
+				active = ""
+			} else {
+				active += seq
+			}
+		}
+		i += n
+	}
+	return active
+}
+
+// stripANSI removes ANSI escape sequences from s
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// displayWidth returns the number of terminal cells s occupies, ignoring ANSI
+// escape sequences and accounting for wide and zero-width characters
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range stripANSI(s) {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// terminalTabWidth is the tab stop terminals use by default
+const terminalTabWidth = 8
+
+// expandTabs replaces the tabs in s with spaces up to the next tab stop,
+// counted from the start of s, so its displayWidth is the width it is shown
+// at. Escape sequences take no cells.
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var b strings.Builder
+	column := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r == '\t' {
+			width := terminalTabWidth - column%terminalTabWidth
+			b.WriteString(strings.Repeat(" ", width))
+			column += width
+			continue
+		}
+		b.WriteRune(r)
+		column += runeWidth(r)
+	}
+	return b.String()
+}
+
+// splitAtWidth splits s into a head that fits within width cells and the
+// remaining tail. Escape sequences are kept with the head and never split.
+func splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r)
+		if used+w > width {
+			return s[:i], s[i:]
+		}
+		used += w
+		i += size
+	}
+	return s, ""
+}
+
+// truncateToWidth cuts s to fit within width cells, appending an ellipsis
+// when anything was removed
+func truncateToWidth(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	head, _ := splitAtWidth(s, width-displayWidth(Ellipsis))
+	if strings.Contains(head, "\x1b") {
+		head += ansiReset
+	}
+	return head + Ellipsis
+}
+
+// wrapToWidth breaks s into chunks of at most width cells
+func wrapToWidth(s string, width int) []string {
+	if width <= 0 || displayWidth(s) <= width {
+		return []string{s}
+	}
+	var chunks []string
+	for displayWidth(s) > width {
+		head, tail := splitAtWidth(s, width)
+		if head == "" {
+			// A single rune wider than the available width
+			_, size := utf8.DecodeRuneInString(tail)
+			head, tail = tail[:size], tail[size:]
+		}
+		chunks = append(chunks, head)
+		s = tail
+	}
+	return append(chunks, s)
+}
@@ -0,0 +1,35 @@
+package nanodoc
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"ascii", "hello", 5},
+		{"accented", "café", 4},
+		{"combining_mark", "é", 1},
+		{"cjk", "日本語", 6},
+		{"emoji", "🚀 go", 5},
+		{"ansi_color", "\x1b[31mred\x1b[0m", 3},
+		{"osc_hyperlink", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.input); got != tt.want {
+				t.Errorf("displayWidth(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateToWidthKeepsEscapes(t *testing.T) {
+	got := truncateToWidth("\x1b[31mabcdefgh\x1b[0m", 5)
+	want := "\x1b[31mabcd\x1b[0m…"
+	if got != want {
+		t.Errorf("truncateToWidth() = %q, want %q", got, want)
+	}
+}